@@ -0,0 +1,165 @@
+package gograph
+
+import (
+	"fmt"
+)
+
+// BlockCutTree is the tree whose nodes are the biconnected components (blocks) and the
+// articulation points of a graph, with an edge between each block and every articulation
+// point it contains.
+type BlockCutTree struct {
+	Tree   Graph     // block nodes, with IDs "block-0", "block-1", ..., then articulation point nodes
+	Blocks [][]*Node // nodes of the graph in each block, in the order of the block nodes of Tree
+}
+
+// biconnectivity holds the results of a single depth-first search for articulation points,
+// bridges and biconnected components, expressed in node indices.
+type biconnectivity struct {
+	articulation []bool
+	bridges      [][2]int
+	blocks       [][]int
+}
+
+// findBiconnectivity implements Tarjan's lowpoint algorithm over adjacency lists
+func findBiconnectivity(adjacency [][]int) biconnectivity {
+	var n = len(adjacency)
+	var result = biconnectivity{articulation: make([]bool, n)}
+	var discovery = make([]int, n)
+	var low = make([]int, n)
+	for i := range discovery {
+		discovery[i] = -1
+	}
+	var clock = 0
+	var edgeStack [][2]int
+
+	var popBlock = func(u int, v int) {
+		var seen = map[int]bool{}
+		var block []int
+		for {
+			var edge = edgeStack[len(edgeStack)-1]
+			edgeStack = edgeStack[:len(edgeStack)-1]
+			for _, w := range edge {
+				if !seen[w] {
+					seen[w] = true
+					block = append(block, w)
+				}
+			}
+			if edge[0] == u && edge[1] == v {
+				break
+			}
+		}
+		result.blocks = append(result.blocks, block)
+	}
+
+	var visit func(u int, parent int)
+	visit = func(u int, parent int) {
+		discovery[u], low[u] = clock, clock
+		clock++
+		var children = 0
+		var skippedParent = false
+		for _, v := range adjacency[u] {
+			// Skip the tree edge back to the parent once, so that parallel edges still count
+			if v == parent && !skippedParent {
+				skippedParent = true
+				continue
+			}
+			if discovery[v] == -1 {
+				edgeStack = append(edgeStack, [2]int{u, v})
+				children++
+				visit(v, u)
+				if low[v] < low[u] {
+					low[u] = low[v]
+				}
+				if low[v] > discovery[u] {
+					result.bridges = append(result.bridges, [2]int{u, v})
+				}
+				if low[v] >= discovery[u] {
+					if parent != -1 || children > 1 {
+						result.articulation[u] = true
+					}
+					popBlock(u, v)
+				}
+			} else if discovery[v] < discovery[u] {
+				edgeStack = append(edgeStack, [2]int{u, v})
+				if discovery[v] < low[u] {
+					low[u] = discovery[v]
+				}
+			}
+		}
+	}
+
+	for u := range adjacency {
+		if discovery[u] == -1 {
+			visit(u, -1)
+		}
+	}
+	return result
+}
+
+// ArticulationPoints returns the nodes of an undirected graph whose removal would increase
+// the number of connected components.
+func ArticulationPoints(graph Graph) []*Node {
+	var result = findBiconnectivity(adjacencyLists(graph))
+	var points []*Node
+	for index, node := range graph.Nodes {
+		if result.articulation[index] {
+			points = append(points, node)
+		}
+	}
+	return points
+}
+
+// Bridges returns the edges of an undirected graph whose removal would increase the number
+// of connected components.
+func Bridges(graph Graph) []Edge {
+	var result = findBiconnectivity(adjacencyLists(graph))
+	var bridges = make([]Edge, len(result.bridges))
+	for index, bridge := range result.bridges {
+		bridges[index] = Edge{From: graph.Nodes[bridge[0]].ID, To: graph.Nodes[bridge[1]].ID}
+	}
+	return bridges
+}
+
+// BiconnectedComponents returns the nodes of each maximal biconnected subgraph of an
+// undirected graph. A bridge forms a component of its own two nodes, articulation points
+// belong to several components and isolated nodes belong to none.
+func BiconnectedComponents(graph Graph) [][]*Node {
+	var result = findBiconnectivity(adjacencyLists(graph))
+	var components = make([][]*Node, len(result.blocks))
+	for index, block := range result.blocks {
+		for _, i := range block {
+			components[index] = append(components[index], graph.Nodes[i])
+		}
+	}
+	return components
+}
+
+// CreateBlockCutTree returns the block-cut tree of an undirected graph. A disconnected graph
+// yields a forest with one tree per connected component that has an edge.
+func CreateBlockCutTree(graph Graph) BlockCutTree {
+	var result = findBiconnectivity(adjacencyLists(graph))
+	var blockCutTree = BlockCutTree{Blocks: make([][]*Node, len(result.blocks))}
+	var tree = Graph{}
+
+	for index := range result.blocks {
+		tree.Nodes = append(tree.Nodes, &Node{ID: fmt.Sprintf("block-%d", index)})
+	}
+	var cutNodes = map[int]*Node{}
+	for index, node := range graph.Nodes {
+		if result.articulation[index] {
+			cutNodes[index] = &Node{ID: node.ID}
+			tree.Nodes = append(tree.Nodes, cutNodes[index])
+		}
+	}
+
+	for index, block := range result.blocks {
+		for _, i := range block {
+			blockCutTree.Blocks[index] = append(blockCutTree.Blocks[index], graph.Nodes[i])
+			if cutNode, ok := cutNodes[i]; ok {
+				tree, _, _ = CreateEdge(tree, tree.Nodes[index], cutNode)
+			}
+		}
+	}
+	blockCutTree.Tree = tree
+	return blockCutTree
+}
@@ -0,0 +1,90 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// createTwoTriangleGraph builds two triangles joined by a bridge, with a pendant bridge:
+//
+//	0 - 1 - 3 - 5 - 6
+//	 \ /     \ /
+//	  2       4
+func createTwoTriangleGraph() Graph {
+	return createWeightedGraph(7, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {1, 3, 1}, {3, 4, 1}, {4, 5, 1}, {5, 3, 1}, {5, 6, 1},
+	})
+}
+
+func TestArticulationPoints(t *testing.T) {
+	describe("ArticulationPoints", t)
+	var graph = createTwoTriangleGraph()
+	var points = ArticulationPoints(graph)
+
+	it("returns the nodes joining the blocks", t)
+	expectEqualInts(len(points), 3, t)
+	expectEqualStrings(points[0].ID, graph.Nodes[1].ID, t)
+	expectEqualStrings(points[1].ID, graph.Nodes[3].ID, t)
+	expectEqualStrings(points[2].ID, graph.Nodes[5].ID, t)
+
+	context("the graph is a directed chain treated as undirected", t)
+	var directed = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 1, 1}})
+	points = ArticulationPoints(CreateUndirectedGraph(directed))
+
+	it("returns the middle node", t)
+	expectEqualInts(len(points), 1, t)
+	expectEqualStrings(points[0].ID, directed.DirectedNodes[1].ID, t)
+}
+
+func TestBridges(t *testing.T) {
+	describe("Bridges", t)
+	var graph = createTwoTriangleGraph()
+	var bridges = Bridges(graph)
+
+	it("returns the edges outside of any cycle", t)
+	expectEqualInts(len(bridges), 2, t)
+	var isBridge = map[Edge]bool{}
+	for _, bridge := range bridges {
+		isBridge[bridge] = true
+	}
+	expectEqualBools(isBridge[Edge{From: graph.Nodes[1].ID, To: graph.Nodes[3].ID}], true, t)
+	expectEqualBools(isBridge[Edge{From: graph.Nodes[5].ID, To: graph.Nodes[6].ID}], true, t)
+
+	context("two nodes are joined by parallel edges", t)
+	graph = createWeightedGraph(2, [][3]float64{{0, 1, 1}, {0, 1, 1}})
+
+	it("does not count them as bridges", t)
+	expectEqualInts(len(Bridges(graph)), 0, t)
+}
+
+func TestBiconnectedComponents(t *testing.T) {
+	describe("BiconnectedComponents", t)
+	var graph = createTwoTriangleGraph()
+	var components = BiconnectedComponents(graph)
+
+	it("returns every block of the graph", t)
+	expectEqualInts(len(components), 4, t)
+	var sizes = map[int]int{}
+	for _, component := range components {
+		sizes[len(component)]++
+	}
+	expectEqualInts(sizes[2], 2, t)
+	expectEqualInts(sizes[3], 2, t)
+}
+
+func TestCreateBlockCutTree(t *testing.T) {
+	describe("CreateBlockCutTree", t)
+	var graph = createTwoTriangleGraph()
+	var blockCutTree = CreateBlockCutTree(graph)
+
+	it("has a node for every block and articulation point", t)
+	expectEqualInts(len(blockCutTree.Blocks), 4, t)
+	expectEqualInts(len(blockCutTree.Tree.Nodes), 7, t)
+	expectEqualStrings(blockCutTree.Tree.Nodes[0].ID, "block-0", t)
+
+	it("is a tree", t)
+	expectEqualInts(countUndirectedEdges(blockCutTree.Tree), 6, t)
+
+	it("joins each articulation point to the blocks containing it", t)
+	var index = FindNode(blockCutTree.Tree, graph.Nodes[3].ID)
+	expectEqualInts(len(blockCutTree.Tree.Nodes[index].Edges), 2, t)
+}
@@ -0,0 +1,135 @@
+package gograph
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalHashOptions selects what besides structure contributes to a canonical hash
+type CanonicalHashOptions struct {
+	ValueKeys     []string // node values included in the hash
+	EdgeValueKeys []string // edge values included in the hash
+	Iterations    int      // refinement rounds, or 0 to refine until the node partition is stable
+}
+
+// hashStrings returns the hexadecimal SHA-256 hash of a sequence of strings
+func hashStrings(parts ...string) string {
+	var h = sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(strconv.Quote(part)))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// selectedValues encodes the values of the given keys, in key order
+func selectedValues(values map[string]string, keys []string) string {
+	var parts = make([]string, len(keys))
+	for index, key := range keys {
+		var value, ok = values[key]
+		if ok {
+			parts[index] = strconv.Quote(key) + "=" + strconv.Quote(value)
+		} else {
+			parts[index] = strconv.Quote(key) + "!"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// weisfeilerLehman refines node labels by repeatedly hashing every label with the sorted
+// labels of the node's parents and children. It returns the final label of every node, and
+// the sorted labels of every round.
+func weisfeilerLehman(graph DirectedGraph, options CanonicalHashOptions) ([]string, [][]string) {
+	var children = childLists(graph)
+	var parents = parentLists(children)
+	var nodes = graph.DirectedNodes
+	var n = len(nodes)
+
+	var sortedKeys = append([]string{}, options.ValueKeys...)
+	sort.Strings(sortedKeys)
+	var sortedEdgeKeys = append([]string{}, options.EdgeValueKeys...)
+	sort.Strings(sortedEdgeKeys)
+
+	var labels = make([]string, n)
+	for index, node := range nodes {
+		labels[index] = hashStrings(strconv.FormatBool(node == graph.RootDirectedNode), selectedValues(node.Values, sortedKeys))
+	}
+	var rounds = [][]string{sortedCopy(labels)}
+	var classes = countDistinct(labels)
+
+	var edgeLabel = func(parent int, child int) string {
+		if len(sortedEdgeKeys) == 0 {
+			return ""
+		}
+		return selectedValues(nodes[parent].EdgeValues[nodes[child].ID], sortedEdgeKeys)
+	}
+
+	for round := 1; options.Iterations <= 0 || round <= options.Iterations; round++ {
+		var next = make([]string, n)
+		for v := range nodes {
+			var incoming = make([]string, 0, len(parents[v]))
+			for _, u := range parents[v] {
+				incoming = append(incoming, labels[u]+":"+edgeLabel(u, v))
+			}
+			var outgoing = make([]string, 0, len(children[v]))
+			for _, w := range children[v] {
+				outgoing = append(outgoing, labels[w]+":"+edgeLabel(v, w))
+			}
+			sort.Strings(incoming)
+			sort.Strings(outgoing)
+			next[v] = hashStrings(labels[v], strings.Join(incoming, ";"), strings.Join(outgoing, ";"))
+		}
+		labels = next
+		rounds = append(rounds, sortedCopy(labels))
+
+		var refined = countDistinct(labels)
+		if options.Iterations <= 0 && refined == classes {
+			break
+		}
+		classes = refined
+	}
+	return labels, rounds
+}
+
+func sortedCopy(labels []string) []string {
+	var sorted = append([]string{}, labels...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func countDistinct(labels []string) int {
+	var distinct = map[string]bool{}
+	for _, label := range labels {
+		distinct[label] = true
+	}
+	return len(distinct)
+}
+
+// CanonicalHash computes a hash of a directed graph from its structure, regardless of node
+// IDs and the order of its nodes, using Weisfeiler-Lehman label refinement. Whether each node
+// is the root, and the node and edge values selected in the options, are part of the hash.
+// Isomorphic graphs always hash alike; non-isomorphic graphs that Weisfeiler-Lehman cannot
+// tell apart, such as some regular graphs, hash alike too, so equal hashes can be confirmed
+// with FindIsomorphism where that matters.
+func CanonicalHash(graph DirectedGraph, options CanonicalHashOptions) string {
+	var _, rounds = weisfeilerLehman(graph, options)
+	var parts = make([]string, len(rounds))
+	for index, labels := range rounds {
+		parts[index] = strings.Join(labels, ",")
+	}
+	return hashStrings(parts...)
+}
+
+// CanonicalNodeHashes computes the Weisfeiler-Lehman label of every node of a directed graph,
+// keyed by node ID. Nodes with equal labels have neighborhoods of the same shape up to the
+// number of refinement rounds, so the labels can be used to match nodes between graphs.
+func CanonicalNodeHashes(graph DirectedGraph, options CanonicalHashOptions) map[string]string {
+	var labels, _ = weisfeilerLehman(graph, options)
+	var hashes = make(map[string]string, len(labels))
+	for index, node := range graph.DirectedNodes {
+		hashes[node.ID] = labels[index]
+	}
+	return hashes
+}
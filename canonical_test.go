@@ -0,0 +1,61 @@
+package gograph
+
+import (
+	"testing"
+)
+
+func TestCanonicalHash(t *testing.T) {
+	describe("CanonicalHash", t)
+
+	context("two graphs are built alike", t)
+	var edges = [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 3, 1}, {2, 3, 1}, {3, 4, 1}}
+	var a = createWeightedDirectedGraph(5, edges)
+	var b = createWeightedDirectedGraph(5, edges)
+
+	it("hashes them alike despite their random node IDs", t)
+	expectEqualStrings(CanonicalHash(a, CanonicalHashOptions{}), CanonicalHash(b, CanonicalHashOptions{}), t)
+
+	context("the nodes of a graph are listed in another order", t)
+	var reordered = a
+	reordered.DirectedNodes = []*DirectedNode{a.DirectedNodes[4], a.DirectedNodes[2], a.DirectedNodes[0], a.DirectedNodes[3], a.DirectedNodes[1]}
+
+	it("hashes it alike", t)
+	expectEqualStrings(CanonicalHash(reordered, CanonicalHashOptions{}), CanonicalHash(a, CanonicalHashOptions{}), t)
+
+	context("the graphs differ in structure", t)
+	var c = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 3, 1}, {2, 4, 1}})
+
+	it("hashes them differently", t)
+	expectEqualBools(CanonicalHash(a, CanonicalHashOptions{}) == CanonicalHash(c, CanonicalHashOptions{}), false, t)
+
+	context("the graphs differ only in node values", t)
+	b.DirectedNodes[4].Values["name"] = "leaf"
+
+	it("hashes them alike unless the values are selected", t)
+	expectEqualStrings(CanonicalHash(a, CanonicalHashOptions{}), CanonicalHash(b, CanonicalHashOptions{}), t)
+	var options = CanonicalHashOptions{ValueKeys: []string{"name"}}
+	expectEqualBools(CanonicalHash(a, options) == CanonicalHash(b, options), false, t)
+
+	context("the graphs differ only in edge values", t)
+	b.DirectedNodes[4].Values["name"] = "4"
+	SetDirectedEdgeValue(b.DirectedNodes[3], b.DirectedNodes[4], WeightKey, "2")
+
+	it("hashes them alike unless the edge values are selected", t)
+	options = CanonicalHashOptions{ValueKeys: []string{"name"}, EdgeValueKeys: []string{WeightKey}}
+	expectEqualStrings(CanonicalHash(a, CanonicalHashOptions{}), CanonicalHash(b, CanonicalHashOptions{}), t)
+	expectEqualBools(CanonicalHash(a, options) == CanonicalHash(b, options), false, t)
+}
+
+func TestCanonicalNodeHashes(t *testing.T) {
+	describe("CanonicalNodeHashes", t)
+	var graph = createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 3, 1}, {2, 3, 1}})
+	var nodes = graph.DirectedNodes
+	var hashes = CanonicalNodeHashes(graph, CanonicalHashOptions{})
+
+	it("labels structurally equivalent nodes alike", t)
+	expectEqualStrings(hashes[nodes[1].ID], hashes[nodes[2].ID], t)
+
+	it("labels structurally distinct nodes differently", t)
+	expectEqualBools(hashes[nodes[0].ID] == hashes[nodes[3].ID], false, t)
+	expectEqualBools(hashes[nodes[0].ID] == hashes[nodes[1].ID], false, t)
+}
@@ -0,0 +1,432 @@
+package gograph
+
+import (
+	"errors"
+	"math"
+)
+
+// CentralityOptions controls the convergence of the iterative centrality measures. A tolerance
+// or maximum of iterations that is not positive, as in the zero value, takes its default.
+type CentralityOptions struct {
+	Tolerance     float64 // iteration stops once the summed change of all scores falls below this
+	MaxIterations int     // most iterations before giving up
+}
+
+// DefaultCentralityOptions returns the convergence controls used when none are specified
+func DefaultCentralityOptions() CentralityOptions {
+	return CentralityOptions{Tolerance: 1e-10, MaxIterations: 1000}
+}
+
+// withDefaults replaces a tolerance or maximum of iterations that is not positive with the default
+func (options CentralityOptions) withDefaults() CentralityOptions {
+	var defaults = DefaultCentralityOptions()
+	if options.Tolerance <= 0 {
+		options.Tolerance = defaults.Tolerance
+	}
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = defaults.MaxIterations
+	}
+	return options
+}
+
+// errNotConverged is returned when an iterative measure exceeds its maximum iterations
+var errNotConverged = errors.New("centrality did not converge within the maximum iterations")
+
+// childLists returns, for every node of a directed graph, the indices of its children.
+// Edges to nodes outside of the graph are left out.
+func childLists(graph DirectedGraph) [][]int {
+	var indices = indexDirectedNodes(graph)
+	var children = make([][]int, len(graph.DirectedNodes))
+	for i, node := range graph.DirectedNodes {
+		for _, child := range node.Children {
+			if j, ok := indices[child]; ok {
+				children[i] = append(children[i], j)
+			}
+		}
+	}
+	return children
+}
+
+// parentLists returns, for every node of a directed graph, the indices of its parents
+func parentLists(children [][]int) [][]int {
+	var parents = make([][]int, len(children))
+	for i, list := range children {
+		for _, j := range list {
+			parents[j] = append(parents[j], i)
+		}
+	}
+	return parents
+}
+
+// scoresByID maps node scores, indexed as the nodes of the graph, to node IDs
+func scoresByID(graph DirectedGraph, scores []float64) map[string]float64 {
+	var result = make(map[string]float64, len(scores))
+	for index, node := range graph.DirectedNodes {
+		result[node.ID] = scores[index]
+	}
+	return result
+}
+
+// normalize scales a vector to unit length in the given norm (1 or 2), leaving a zero vector as is
+func normalize(vector []float64, norm int) {
+	var length float64
+	for _, x := range vector {
+		if norm == 1 {
+			length += math.Abs(x)
+		} else {
+			length += x * x
+		}
+	}
+	if norm != 1 {
+		length = math.Sqrt(length)
+	}
+	if length == 0 {
+		return
+	}
+	for i := range vector {
+		vector[i] /= length
+	}
+}
+
+// change returns the summed absolute difference between two vectors
+func change(previous []float64, next []float64) float64 {
+	var total float64
+	for i := range previous {
+		total += math.Abs(next[i] - previous[i])
+	}
+	return total
+}
+
+// PageRank computes the PageRank of every node of a directed graph, following edges from
+// parent to child. A random surfer follows an edge with probability damping, or otherwise
+// teleports to a node chosen by the personalization weights; nodes without children teleport
+// the same way. A nil personalization teleports uniformly. Scores sum to 1.
+func PageRank(graph DirectedGraph, damping float64, personalization map[string]float64, options CentralityOptions) (map[string]float64, error) {
+	options = options.withDefaults()
+	var n = len(graph.DirectedNodes)
+	if n == 0 {
+		return map[string]float64{}, nil
+	}
+	if damping < 0 || damping > 1 {
+		return nil, errors.New("damping must lie between 0 and 1")
+	}
+
+	var teleport = make([]float64, n)
+	var total float64
+	for index, node := range graph.DirectedNodes {
+		if personalization == nil {
+			teleport[index] = 1
+		} else {
+			teleport[index] = personalization[node.ID]
+		}
+		if teleport[index] < 0 {
+			return nil, errors.New("personalization weights must not be negative")
+		}
+		total += teleport[index]
+	}
+	if total == 0 {
+		return nil, errors.New("personalization weights must not all be zero")
+	}
+	normalize(teleport, 1)
+
+	var children = childLists(graph)
+	var rank = make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		var next = make([]float64, n)
+		var dangling float64
+		for u, list := range children {
+			if len(list) == 0 {
+				dangling += rank[u]
+				continue
+			}
+			for _, v := range list {
+				next[v] += damping * rank[u] / float64(len(list))
+			}
+		}
+		for v := range next {
+			next[v] += (damping*dangling + 1 - damping) * teleport[v]
+		}
+		var delta = change(rank, next)
+		rank = next
+		if delta < options.Tolerance {
+			return scoresByID(graph, rank), nil
+		}
+	}
+	return nil, errNotConverged
+}
+
+// brandes accumulates shortest path dependencies from every source with Brandes' algorithm,
+// counting path lengths in edges. It returns the betweenness of every node, and of every
+// edge keyed by its parent and child indices.
+func brandes(children [][]int) ([]float64, map[[2]int]float64) {
+	var n = len(children)
+	var nodeScores = make([]float64, n)
+	var edgeScores = map[[2]int]float64{}
+
+	for source := 0; source < n; source++ {
+		var order []int
+		var predecessors = make([][]int, n)
+		var paths = make([]float64, n)
+		var distance = make([]int, n)
+		for i := range distance {
+			distance[i] = -1
+		}
+		paths[source], distance[source] = 1, 0
+		var queue = []int{source}
+		for len(queue) > 0 {
+			var u = queue[0]
+			queue = queue[1:]
+			order = append(order, u)
+			for _, v := range children[u] {
+				if distance[v] == -1 {
+					distance[v] = distance[u] + 1
+					queue = append(queue, v)
+				}
+				if distance[v] == distance[u]+1 {
+					paths[v] += paths[u]
+					predecessors[v] = append(predecessors[v], u)
+				}
+			}
+		}
+
+		// Accumulate dependencies in order of non-increasing distance from the source
+		var dependency = make([]float64, n)
+		for i := len(order) - 1; i >= 0; i-- {
+			var w = order[i]
+			for _, u := range predecessors[w] {
+				var share = paths[u] / paths[w] * (1 + dependency[w])
+				edgeScores[[2]int{u, w}] += share
+				dependency[u] += share
+			}
+			if w != source {
+				nodeScores[w] += dependency[w]
+			}
+		}
+	}
+	return nodeScores, edgeScores
+}
+
+// BetweennessCentrality computes, for every node of a directed graph, the number of
+// shortest paths between other pairs of nodes that pass through it, where each pair's paths
+// share a total of 1. Path lengths count edges followed from parent to child.
+func BetweennessCentrality(graph DirectedGraph) map[string]float64 {
+	var scores, _ = brandes(childLists(graph))
+	return scoresByID(graph, scores)
+}
+
+// EdgeBetweennessCentrality computes, for every edge of a directed graph, the number of
+// shortest paths between pairs of nodes that pass through it, where each pair's paths share
+// a total of 1.
+func EdgeBetweennessCentrality(graph DirectedGraph) map[Edge]float64 {
+	var children = childLists(graph)
+	var _, scores = brandes(children)
+	var result = map[Edge]float64{}
+	for u, list := range children {
+		for _, v := range list {
+			var edge = Edge{From: graph.DirectedNodes[u].ID, To: graph.DirectedNodes[v].ID}
+			result[edge] = scores[[2]int{u, v}]
+		}
+	}
+	return result
+}
+
+// distancesFrom returns the number of edges on a shortest path from a source to every node,
+// or -1 for nodes it cannot reach.
+func distancesFrom(children [][]int, source int) []int {
+	var distance = make([]int, len(children))
+	for i := range distance {
+		distance[i] = -1
+	}
+	distance[source] = 0
+	var queue = []int{source}
+	for len(queue) > 0 {
+		var u = queue[0]
+		queue = queue[1:]
+		for _, v := range children[u] {
+			if distance[v] == -1 {
+				distance[v] = distance[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return distance
+}
+
+// ClosenessCentrality computes, for every node of a directed graph, the inverse of its
+// average distance to the nodes it can reach by following edges from parent to child. The
+// score is scaled by the fraction of other nodes it reaches, so that nodes reaching few
+// others are not favored; nodes reaching none score 0.
+func ClosenessCentrality(graph DirectedGraph) map[string]float64 {
+	var children = childLists(graph)
+	var n = len(children)
+	var scores = make([]float64, n)
+	for source := range children {
+		var total, reached = 0, 0
+		for _, d := range distancesFrom(children, source) {
+			if d > 0 {
+				total += d
+				reached++
+			}
+		}
+		if total > 0 {
+			scores[source] = float64(reached) / float64(total) * float64(reached) / float64(n-1)
+		}
+	}
+	return scoresByID(graph, scores)
+}
+
+// HarmonicCentrality computes, for every node of a directed graph, the sum of the inverse
+// distances to the nodes it can reach by following edges from parent to child.
+func HarmonicCentrality(graph DirectedGraph) map[string]float64 {
+	var children = childLists(graph)
+	var scores = make([]float64, len(children))
+	for source := range children {
+		for _, d := range distancesFrom(children, source) {
+			if d > 0 {
+				scores[source] += 1 / float64(d)
+			}
+		}
+	}
+	return scoresByID(graph, scores)
+}
+
+// EigenvectorCentrality computes the eigenvector centrality of every node of a directed
+// graph, where a node is important if its parents are. Power iteration is applied to the
+// adjacency matrix shifted by the identity so that periodic graphs converge. On an acyclic
+// graph, whose eigenvalues are all zero, the iteration only drifts towards its limit, so the
+// limit is computed directly: nodes score by the number of longest paths ending at them, and
+// nodes ending no longest path score 0. Scores have unit Euclidean length.
+func EigenvectorCentrality(graph DirectedGraph, options CentralityOptions) (map[string]float64, error) {
+	options = options.withDefaults()
+	var n = len(graph.DirectedNodes)
+	if n == 0 {
+		return map[string]float64{}, nil
+	}
+	var children = childLists(graph)
+	var parents = parentLists(children)
+	if order, acyclic := topologicalOrder(children); acyclic {
+		// Count the paths of the greatest length ending at every node, which only start at
+		// sources and pass through nodes of every smaller length
+		var length = make([]int, n)
+		var paths = make([]float64, n)
+		var longest = 0
+		for _, v := range order {
+			for _, u := range parents[v] {
+				if length[u]+1 > length[v] {
+					length[v] = length[u] + 1
+				}
+			}
+			paths[v] = 1
+			if length[v] > 0 {
+				paths[v] = 0
+				for _, u := range parents[v] {
+					if length[u] == length[v]-1 {
+						paths[v] += paths[u]
+					}
+				}
+			}
+			if length[v] > longest {
+				longest = length[v]
+			}
+		}
+		var scores = make([]float64, n)
+		for v := range scores {
+			if length[v] == longest {
+				scores[v] = paths[v]
+			}
+		}
+		normalize(scores, 2)
+		return scoresByID(graph, scores), nil
+	}
+	var scores = make([]float64, n)
+	for i := range scores {
+		scores[i] = 1 / float64(n)
+	}
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		var next = append([]float64{}, scores...)
+		for v, list := range parents {
+			for _, u := range list {
+				next[v] += scores[u]
+			}
+		}
+		normalize(next, 2)
+		var delta = change(scores, next)
+		scores = next
+		if delta < float64(n)*options.Tolerance {
+			return scoresByID(graph, scores), nil
+		}
+	}
+	return nil, errNotConverged
+}
+
+// KatzCentrality computes the Katz centrality of every node of a directed graph: every node
+// receives a base score of beta plus alpha times the scores of its parents. Alpha must be
+// smaller than the inverse of the largest eigenvalue of the adjacency matrix for the scores
+// to converge. Scores have unit Euclidean length.
+func KatzCentrality(graph DirectedGraph, alpha float64, beta float64, options CentralityOptions) (map[string]float64, error) {
+	options = options.withDefaults()
+	var n = len(graph.DirectedNodes)
+	if n == 0 {
+		return map[string]float64{}, nil
+	}
+	var parents = parentLists(childLists(graph))
+	var scores = make([]float64, n)
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		var next = make([]float64, n)
+		for v, list := range parents {
+			next[v] = beta
+			for _, u := range list {
+				next[v] += alpha * scores[u]
+			}
+		}
+		var delta = change(scores, next)
+		scores = next
+		if delta < float64(n)*options.Tolerance {
+			normalize(scores, 2)
+			return scoresByID(graph, scores), nil
+		}
+	}
+	return nil, errNotConverged
+}
+
+// HITS computes the hub and authority scores of every node of a directed graph: good hubs
+// are parents of good authorities, and good authorities are children of good hubs. Each set
+// of scores sums to 1.
+func HITS(graph DirectedGraph, options CentralityOptions) (map[string]float64, map[string]float64, error) {
+	options = options.withDefaults()
+	var n = len(graph.DirectedNodes)
+	if n == 0 {
+		return map[string]float64{}, map[string]float64{}, nil
+	}
+	var children = childLists(graph)
+	var parents = parentLists(children)
+	var hubs = make([]float64, n)
+	for i := range hubs {
+		hubs[i] = 1 / float64(n)
+	}
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		var authorities = make([]float64, n)
+		for v, list := range parents {
+			for _, u := range list {
+				authorities[v] += hubs[u]
+			}
+		}
+		normalize(authorities, 1)
+		var next = make([]float64, n)
+		for u, list := range children {
+			for _, v := range list {
+				next[u] += authorities[v]
+			}
+		}
+		normalize(next, 1)
+		var delta = change(hubs, next)
+		hubs = next
+		if delta < options.Tolerance {
+			return scoresByID(graph, hubs), scoresByID(graph, authorities), nil
+		}
+	}
+	return nil, nil, errNotConverged
+}
@@ -0,0 +1,185 @@
+package gograph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPageRank(t *testing.T) {
+	describe("PageRank", t)
+
+	context("the graph is a cycle", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+	var ranks, err = PageRank(graph, 0.85, nil, DefaultCentralityOptions())
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("ranks every node equally", t)
+	for _, node := range graph.DirectedNodes {
+		expectEqualFloats(ranks[node.ID], 1.0/3, t)
+	}
+
+	context("every node links to a hub", t)
+	graph = createWeightedDirectedGraph(4, [][3]float64{{1, 0, 1}, {2, 0, 1}, {3, 0, 1}})
+	ranks, _ = PageRank(graph, 0.85, nil, DefaultCentralityOptions())
+
+	it("ranks the hub highest and sums to 1", t)
+	var nodes = graph.DirectedNodes
+	expectEqualBools(ranks[nodes[0].ID] > ranks[nodes[1].ID], true, t)
+	expectEqualFloats(ranks[nodes[0].ID]+ranks[nodes[1].ID]+ranks[nodes[2].ID]+ranks[nodes[3].ID], 1, t)
+
+	context("teleportation is personalized to a single node", t)
+	ranks, _ = PageRank(graph, 0.85, map[string]float64{nodes[1].ID: 1}, DefaultCentralityOptions())
+
+	it("never ranks nodes only reachable by teleporting elsewhere", t)
+	expectEqualFloats(ranks[nodes[2].ID], 0, t)
+	expectEqualBools(ranks[nodes[1].ID] > 0, true, t)
+
+	context("the options are left at their zero value", t)
+	var defaulted, _ = PageRank(graph, 0.85, nil, DefaultCentralityOptions())
+	ranks, err = PageRank(graph, 0.85, nil, CentralityOptions{})
+
+	it("uses the default options", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(ranks[nodes[0].ID], defaulted[nodes[0].ID], t)
+
+	context("the iterations are capped too low to converge", t)
+	_, err = PageRank(graph, 0.85, nil, CentralityOptions{Tolerance: 1e-12, MaxIterations: 1})
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
+
+func TestBetweennessCentrality(t *testing.T) {
+	describe("BetweennessCentrality", t)
+	//  0 -> 1 -> 3
+	//  0 -> 2 -> 3
+	var graph = createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 3, 1}, {2, 3, 1}})
+	var nodes = graph.DirectedNodes
+	var scores = BetweennessCentrality(graph)
+
+	it("splits the dependency among equally short paths", t)
+	expectEqualFloats(scores[nodes[0].ID], 0, t)
+	expectEqualFloats(scores[nodes[1].ID], 0.5, t)
+	expectEqualFloats(scores[nodes[2].ID], 0.5, t)
+	expectEqualFloats(scores[nodes[3].ID], 0, t)
+}
+
+func TestEdgeBetweennessCentrality(t *testing.T) {
+	describe("EdgeBetweennessCentrality", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	var nodes = graph.DirectedNodes
+	var scores = EdgeBetweennessCentrality(graph)
+
+	it("counts every shortest path crossing each edge", t)
+	expectEqualInts(len(scores), 2, t)
+	expectEqualFloats(scores[Edge{From: nodes[0].ID, To: nodes[1].ID}], 2, t)
+	expectEqualFloats(scores[Edge{From: nodes[1].ID, To: nodes[2].ID}], 2, t)
+}
+
+func TestClosenessCentrality(t *testing.T) {
+	describe("ClosenessCentrality", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	var nodes = graph.DirectedNodes
+	var scores = ClosenessCentrality(graph)
+
+	it("scales the inverse average distance by the fraction of nodes reached", t)
+	expectEqualFloats(scores[nodes[0].ID], 2.0/3, t)
+	expectEqualFloats(scores[nodes[1].ID], 0.5, t)
+	expectEqualFloats(scores[nodes[2].ID], 0, t)
+}
+
+func TestHarmonicCentrality(t *testing.T) {
+	describe("HarmonicCentrality", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	var nodes = graph.DirectedNodes
+	var scores = HarmonicCentrality(graph)
+
+	it("sums the inverse distances to reachable nodes", t)
+	expectEqualFloats(scores[nodes[0].ID], 1.5, t)
+	expectEqualFloats(scores[nodes[1].ID], 1, t)
+	expectEqualFloats(scores[nodes[2].ID], 0, t)
+}
+
+func TestEigenvectorCentrality(t *testing.T) {
+	describe("EigenvectorCentrality", t)
+
+	context("the graph is a cycle", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+	var scores, err = EigenvectorCentrality(graph, DefaultCentralityOptions())
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("scores every node equally with unit length", t)
+	for _, node := range graph.DirectedNodes {
+		expectEqualFloats(scores[node.ID], 1/math.Sqrt(3), t)
+	}
+
+	context("two cycles share a node", t)
+	graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 0, 1}, {0, 2, 1}, {2, 0, 1}, {3, 4, 1}})
+	scores, _ = EigenvectorCentrality(graph, DefaultCentralityOptions())
+
+	it("scores the shared node highest", t)
+	expectEqualBools(scores[graph.DirectedNodes[0].ID] > scores[graph.DirectedNodes[1].ID], true, t)
+
+	context("the graph is acyclic", t)
+	graph = createWeightedDirectedGraph(8, [][3]float64{{0, 1, 1}, {1, 2, 1}, {3, 4, 1}, {4, 5, 1}, {3, 6, 1}, {6, 5, 1}})
+	scores, err = EigenvectorCentrality(graph, DefaultCentralityOptions())
+
+	it("scores the ends of the longest paths by how many end there", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(scores[graph.DirectedNodes[2].ID], 1/math.Sqrt(5), t)
+	expectEqualFloats(scores[graph.DirectedNodes[5].ID], 2/math.Sqrt(5), t)
+	expectEqualFloats(scores[graph.DirectedNodes[4].ID], 0, t)
+	expectEqualFloats(scores[graph.DirectedNodes[7].ID], 0, t)
+}
+
+func TestKatzCentrality(t *testing.T) {
+	describe("KatzCentrality", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	var nodes = graph.DirectedNodes
+	var scores, err = KatzCentrality(graph, 0.1, 1, DefaultCentralityOptions())
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("attenuates the contribution of distant ancestors", t)
+	var length = math.Sqrt(1 + 1.1*1.1 + 1.11*1.11)
+	expectEqualFloats(scores[nodes[0].ID], 1/length, t)
+	expectEqualFloats(scores[nodes[1].ID], 1.1/length, t)
+	expectEqualFloats(scores[nodes[2].ID], 1.11/length, t)
+
+	context("alpha is too large for the scores to converge", t)
+	graph = createWeightedDirectedGraph(2, [][3]float64{{0, 1, 1}, {1, 0, 1}})
+	_, err = KatzCentrality(graph, 2, 1, DefaultCentralityOptions())
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
+
+func TestHITS(t *testing.T) {
+	describe("HITS", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}})
+	var nodes = graph.DirectedNodes
+	var hubs, authorities, err = HITS(graph, DefaultCentralityOptions())
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("scores parents as hubs and children as authorities", t)
+	expectEqualFloats(hubs[nodes[0].ID], 1, t)
+	expectEqualFloats(hubs[nodes[1].ID], 0, t)
+	expectEqualFloats(authorities[nodes[0].ID], 0, t)
+	expectEqualFloats(authorities[nodes[1].ID], 0.5, t)
+	expectEqualFloats(authorities[nodes[2].ID], 0.5, t)
+}
@@ -0,0 +1,252 @@
+package gograph
+
+import (
+	"sort"
+)
+
+// neighborSets returns, for every node of a graph, the set of indices of its neighbors.
+// Self-loops and parallel edges are left out.
+func neighborSets(graph Graph) []map[int]bool {
+	var sets = make([]map[int]bool, len(graph.Nodes))
+	for i, list := range adjacencyLists(graph) {
+		sets[i] = make(map[int]bool, len(list))
+		for _, j := range list {
+			sets[i][j] = true
+		}
+	}
+	return sets
+}
+
+// intersect returns the members of a list that are in a set, preserving their order
+func intersect(list []int, set map[int]bool) []int {
+	var result []int
+	for _, x := range list {
+		if set[x] {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// bronKerbosch enumerates the maximal cliques extending the clique R with candidates P and
+// excluded nodes X, choosing a pivot to avoid exploring cliques found through its neighbors.
+// Exploration stops early if report returns false, and branches are skipped when prune
+// reports that they cannot lead anywhere useful.
+func bronKerbosch(neighbors []map[int]bool, R []int, P []int, X []int, report func([]int) bool, prune func(int) bool) bool {
+	if len(P) == 0 && len(X) == 0 {
+		return report(R)
+	}
+	if prune(len(R) + len(P)) {
+		return true
+	}
+
+	var pivot, pivotDegree = -1, -1
+	for _, candidates := range [][]int{P, X} {
+		for _, u := range candidates {
+			var degree = len(intersect(P, neighbors[u]))
+			if degree > pivotDegree {
+				pivot, pivotDegree = u, degree
+			}
+		}
+	}
+
+	var remaining = append([]int{}, P...)
+	for _, v := range P {
+		if neighbors[pivot][v] {
+			continue
+		}
+		var extended = append(append([]int{}, R...), v)
+		if !bronKerbosch(neighbors, extended, intersect(remaining, neighbors[v]), intersect(X, neighbors[v]), report, prune) {
+			return false
+		}
+		for index, w := range remaining {
+			if w == v {
+				remaining = append(remaining[:index], remaining[index+1:]...)
+				break
+			}
+		}
+		X = append(X, v)
+	}
+	return true
+}
+
+// cliqueNodes returns the nodes at the given indices, in the order of the graph
+func cliqueNodes(graph Graph, clique []int) []*Node {
+	var sorted = append([]int{}, clique...)
+	sort.Ints(sorted)
+	var nodes = make([]*Node, len(sorted))
+	for index, i := range sorted {
+		nodes[index] = graph.Nodes[i]
+	}
+	return nodes
+}
+
+// MaximalCliques implements the Bron-Kerbosch algorithm with pivoting to enumerate every
+// clique of an undirected graph that cannot be extended by another node. Nodes within each
+// clique appear in the order of the graph. A graph without nodes has no cliques.
+func MaximalCliques(graph Graph) [][]*Node {
+	if len(graph.Nodes) == 0 {
+		return nil
+	}
+	var neighbors = neighborSets(graph)
+	var cliques [][]*Node
+	var report = func(clique []int) bool {
+		cliques = append(cliques, cliqueNodes(graph, clique))
+		return true
+	}
+	var prune = func(int) bool { return false }
+	bronKerbosch(neighbors, nil, identityPartition(len(graph.Nodes)), nil, report, prune)
+	return cliques
+}
+
+// MaximumClique returns a largest clique of an undirected graph, searching the maximal cliques
+// while skipping branches too small to beat the best clique found so far.
+func MaximumClique(graph Graph) []*Node {
+	var neighbors = neighborSets(graph)
+	var best []int
+	var report = func(clique []int) bool {
+		if len(clique) > len(best) {
+			best = append([]int{}, clique...)
+		}
+		return true
+	}
+	var prune = func(bound int) bool { return bound <= len(best) }
+	bronKerbosch(neighbors, nil, identityPartition(len(graph.Nodes)), nil, report, prune)
+	return cliqueNodes(graph, best)
+}
+
+// CoreNumbers implements the Batagelj-Zaversnik algorithm to find the core number of every
+// node of an undirected graph: the largest k for which the node belongs to a k-core, the
+// maximal subgraph in which every node has at least k neighbors.
+func CoreNumbers(graph Graph) map[string]int {
+	var neighbors = neighborSets(graph)
+	var n = len(neighbors)
+	var degree = make([]int, n)
+	var maxDegree = 0
+	for i, set := range neighbors {
+		degree[i] = len(set)
+		if degree[i] > maxDegree {
+			maxDegree = degree[i]
+		}
+	}
+
+	// Bucket the nodes by degree, keeping every node's position within the ordering
+	var bins = make([]int, maxDegree+2)
+	for _, d := range degree {
+		bins[d+1]++
+	}
+	for d := 1; d < len(bins); d++ {
+		bins[d] += bins[d-1]
+	}
+	var order = make([]int, n)
+	var position = make([]int, n)
+	var next = append([]int{}, bins...)
+	for i, d := range degree {
+		position[i] = next[d]
+		order[position[i]] = i
+		next[d]++
+	}
+
+	for _, v := range order {
+		for u := range neighbors[v] {
+			if degree[u] <= degree[v] {
+				continue
+			}
+			// Move u to the front of its bucket, then shrink the bucket by one
+			var du = degree[u]
+			var first = order[bins[du]]
+			if first != u {
+				order[position[u]], order[bins[du]] = first, u
+				position[first], position[u] = position[u], bins[du]
+			}
+			bins[du]++
+			degree[u]--
+		}
+	}
+
+	var cores = make(map[string]int, n)
+	for index, node := range graph.Nodes {
+		cores[node.ID] = degree[index]
+	}
+	return cores
+}
+
+// KTruss returns the k-truss of an undirected graph: the maximal subgraph in which every edge
+// belongs to at least k-2 triangles. The subgraph contains copies of the nodes that keep at
+// least one edge, in the order of the graph, and the values of the edges kept.
+func KTruss(graph Graph, k int) Graph {
+	var neighbors = neighborSets(graph)
+	var support = map[[2]int]int{}
+	var queue [][2]int
+	for u, set := range neighbors {
+		for v := range set {
+			if u < v {
+				var edge = [2]int{u, v}
+				for w := range set {
+					if neighbors[v][w] {
+						support[edge]++
+					}
+				}
+				if support[edge] < k-2 {
+					queue = append(queue, edge)
+				}
+			}
+		}
+	}
+
+	// Peel edges with too little support, reducing the support of their triangles' other edges
+	var removed = map[[2]int]bool{}
+	var edgeKey = func(u int, v int) [2]int {
+		if u < v {
+			return [2]int{u, v}
+		}
+		return [2]int{v, u}
+	}
+	for len(queue) > 0 {
+		var edge = queue[0]
+		queue = queue[1:]
+		if removed[edge] {
+			continue
+		}
+		removed[edge] = true
+		var u, v = edge[0], edge[1]
+		delete(neighbors[u], v)
+		delete(neighbors[v], u)
+		for w := range neighbors[u] {
+			if !neighbors[v][w] {
+				continue
+			}
+			for _, other := range [][2]int{edgeKey(u, w), edgeKey(v, w)} {
+				support[other]--
+				if support[other] == k-3 {
+					queue = append(queue, other)
+				}
+			}
+		}
+	}
+
+	var truss = Graph{}
+	var copies = map[int]*Node{}
+	for i, node := range graph.Nodes {
+		if len(neighbors[i]) > 0 {
+			copies[i] = &Node{ID: node.ID, Values: node.Values}
+			truss.Nodes = append(truss.Nodes, copies[i])
+		}
+	}
+	for u := range graph.Nodes {
+		var adjacent = make([]int, 0, len(neighbors[u]))
+		for v := range neighbors[u] {
+			if u < v {
+				adjacent = append(adjacent, v)
+			}
+		}
+		sort.Ints(adjacent)
+		for _, v := range adjacent {
+			truss, _, _ = CreateEdge(truss, copies[u], copies[v])
+			for key, value := range graph.Nodes[u].EdgeValues[graph.Nodes[v].ID] {
+				SetEdgeValue(copies[u], copies[v], key, value)
+			}
+		}
+	}
+	return truss
+}
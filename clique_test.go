@@ -0,0 +1,88 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// createCliqueChainGraph builds a complete graph on nodes 0-3, a triangle 3-4-5 and a pendant node 6:
+//
+//	K4(0,1,2,3) - 4
+//	          \   |
+//	            - 5 - 6
+func createCliqueChainGraph() Graph {
+	return createWeightedGraph(7, [][3]float64{
+		{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {1, 2, 1}, {1, 3, 1}, {2, 3, 1},
+		{3, 4, 1}, {4, 5, 1}, {5, 3, 1}, {5, 6, 1},
+	})
+}
+
+func TestMaximalCliques(t *testing.T) {
+	describe("MaximalCliques", t)
+	var graph = createCliqueChainGraph()
+	var cliques = MaximalCliques(graph)
+
+	it("returns every maximal clique", t)
+	expectEqualInts(len(cliques), 3, t)
+	var sizes = map[int]int{}
+	for _, clique := range cliques {
+		sizes[len(clique)]++
+	}
+	expectEqualInts(sizes[4], 1, t)
+	expectEqualInts(sizes[3], 1, t)
+	expectEqualInts(sizes[2], 1, t)
+
+	context("the graph has an isolated node", t)
+	graph = createWeightedGraph(1, nil)
+
+	it("returns the node as a clique of its own", t)
+	expectEqualInts(len(MaximalCliques(graph)), 1, t)
+
+	context("the graph has no nodes", t)
+
+	it("returns no cliques", t)
+	expectEqualInts(len(MaximalCliques(Graph{})), 0, t)
+}
+
+func TestMaximumClique(t *testing.T) {
+	describe("MaximumClique", t)
+	var graph = createCliqueChainGraph()
+	var clique = MaximumClique(graph)
+
+	it("returns the largest clique in graph order", t)
+	expectEqualInts(len(clique), 4, t)
+	for index, node := range clique {
+		expectEqualStrings(node.ID, graph.Nodes[index].ID, t)
+	}
+}
+
+func TestCoreNumbers(t *testing.T) {
+	describe("CoreNumbers", t)
+	var graph = createCliqueChainGraph()
+	var cores = CoreNumbers(graph)
+
+	it("returns the core number of every node", t)
+	var expected = []int{3, 3, 3, 3, 2, 2, 1}
+	for index, node := range graph.Nodes {
+		expectEqualInts(cores[node.ID], expected[index], t)
+	}
+}
+
+func TestKTruss(t *testing.T) {
+	describe("KTruss", t)
+	var graph = createCliqueChainGraph()
+
+	context("k is 4", t)
+	var truss = KTruss(graph, 4)
+
+	it("keeps only the edges in at least two triangles", t)
+	expectEqualInts(len(truss.Nodes), 4, t)
+	expectEqualInts(countUndirectedEdges(truss), 6, t)
+	expectEqualStrings(truss.Nodes[3].ID, graph.Nodes[3].ID, t)
+
+	context("k is 3", t)
+	truss = KTruss(graph, 3)
+
+	it("drops the edges outside of any triangle", t)
+	expectEqualInts(len(truss.Nodes), 6, t)
+	expectEqualInts(countUndirectedEdges(truss), 9, t)
+}
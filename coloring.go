@@ -0,0 +1,211 @@
+package gograph
+
+import (
+	"errors"
+	"sort"
+)
+
+// ColoringStrategy selects the order in which GreedyColoring colors nodes
+type ColoringStrategy int
+
+const (
+	// LargestFirst colors nodes in order of decreasing degree
+	LargestFirst ColoringStrategy = iota
+	// SmallestLast colors nodes in the reverse of the order in which nodes of smallest
+	// remaining degree are removed from the graph
+	SmallestLast
+	// DSatur colors next the node with the most distinctly colored neighbors, breaking ties
+	// by degree
+	DSatur
+)
+
+// Coloring assigns every node of a graph a color numbered from 0 such that no two adjacent
+// nodes share a color.
+type Coloring struct {
+	Colors    map[string]int
+	Classes   [][]*Node // nodes of each color, in the order of the graph
+	NumColors int
+}
+
+// coloringResult groups the nodes of a graph by the colors they were assigned
+func coloringResult(graph Graph, colors []int) Coloring {
+	var coloring = Coloring{Colors: make(map[string]int, len(colors))}
+	for index, node := range graph.Nodes {
+		var color = colors[index]
+		coloring.Colors[node.ID] = color
+		for len(coloring.Classes) <= color {
+			coloring.Classes = append(coloring.Classes, nil)
+		}
+		coloring.Classes[color] = append(coloring.Classes[color], node)
+	}
+	coloring.NumColors = len(coloring.Classes)
+	return coloring
+}
+
+// errSelfLoop is returned when a graph cannot be colored because a node is its own neighbor
+var errSelfLoop = errors.New("graph has a self-loop and cannot be colored")
+
+func hasSelfLoop(graph Graph) bool {
+	for _, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if neighbor == node {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// smallestColor returns the smallest color not used by any colored neighbor of a node
+func smallestColor(neighbors []int, colors []int) int {
+	var used = map[int]bool{}
+	for _, v := range neighbors {
+		if colors[v] >= 0 {
+			used[colors[v]] = true
+		}
+	}
+	var color = 0
+	for used[color] {
+		color++
+	}
+	return color
+}
+
+// smallestLastOrder repeatedly removes a node of smallest remaining degree, returning the
+// nodes in the reverse order of removal.
+func smallestLastOrder(neighbors []map[int]bool) []int {
+	var n = len(neighbors)
+	var degree = make([]int, n)
+	var removed = make([]bool, n)
+	for i, set := range neighbors {
+		degree[i] = len(set)
+	}
+	var order = make([]int, n)
+	for position := n - 1; position >= 0; position-- {
+		var selected = -1
+		for i := range degree {
+			if !removed[i] && (selected == -1 || degree[i] < degree[selected]) {
+				selected = i
+			}
+		}
+		removed[selected] = true
+		order[position] = selected
+		for v := range neighbors[selected] {
+			degree[v]--
+		}
+	}
+	return order
+}
+
+// GreedyColoring colors an undirected graph by visiting its nodes in the order given by the
+// strategy and assigning each the smallest color unused by its neighbors. The number of
+// colors used is an upper bound on the chromatic number. An error is returned if the graph
+// has a self-loop.
+func GreedyColoring(graph Graph, strategy ColoringStrategy) (Coloring, error) {
+	if hasSelfLoop(graph) {
+		return Coloring{}, errSelfLoop
+	}
+	var adjacency = adjacencyLists(graph)
+	var neighbors = neighborSets(graph)
+	var n = len(graph.Nodes)
+	var colors = make([]int, n)
+	for i := range colors {
+		colors[i] = -1
+	}
+
+	switch strategy {
+	case LargestFirst, SmallestLast:
+		var order = identityPartition(n)
+		if strategy == LargestFirst {
+			sort.SliceStable(order, func(a, b int) bool { return len(neighbors[order[a]]) > len(neighbors[order[b]]) })
+		} else {
+			order = smallestLastOrder(neighbors)
+		}
+		for _, i := range order {
+			colors[i] = smallestColor(adjacency[i], colors)
+		}
+	case DSatur:
+		var saturation = make([]map[int]bool, n)
+		for i := range saturation {
+			saturation[i] = map[int]bool{}
+		}
+		for colored := 0; colored < n; colored++ {
+			var selected = -1
+			for i := range colors {
+				if colors[i] != -1 {
+					continue
+				}
+				if selected == -1 || len(saturation[i]) > len(saturation[selected]) ||
+					(len(saturation[i]) == len(saturation[selected]) && len(neighbors[i]) > len(neighbors[selected])) {
+					selected = i
+				}
+			}
+			colors[selected] = smallestColor(adjacency[selected], colors)
+			for v := range neighbors[selected] {
+				saturation[v][colors[selected]] = true
+			}
+		}
+	default:
+		return Coloring{}, errors.New("unknown coloring strategy")
+	}
+	return coloringResult(graph, colors), nil
+}
+
+// ExactColoring colors an undirected graph with the fewest possible colors, its chromatic
+// number, by backtracking over colorings with an increasing number of colors. Its running
+// time grows exponentially, so it is only suited to small graphs. An error is returned if
+// the graph has a self-loop.
+func ExactColoring(graph Graph) (Coloring, error) {
+	var greedy, err = GreedyColoring(graph, DSatur)
+	if err != nil {
+		return Coloring{}, err
+	}
+
+	var neighbors = neighborSets(graph)
+	var n = len(graph.Nodes)
+	var order = identityPartition(n)
+	sort.SliceStable(order, func(a, b int) bool { return len(neighbors[order[a]]) > len(neighbors[order[b]]) })
+	var colors = make([]int, n)
+
+	// assign colors the position-th node of the order, allowing at most one new color
+	// beyond those already in use so that permutations of colors are not revisited.
+	var assign func(position int, used int, limit int) bool
+	assign = func(position int, used int, limit int) bool {
+		if position == n {
+			return true
+		}
+		var i = order[position]
+		for color := 0; color < limit && color <= used; color++ {
+			var conflict = false
+			for v := range neighbors[i] {
+				if colors[v] == color {
+					conflict = true
+					break
+				}
+			}
+			if conflict {
+				continue
+			}
+			colors[i] = color
+			var nextUsed = used
+			if color == used {
+				nextUsed++
+			}
+			if assign(position+1, nextUsed, limit) {
+				return true
+			}
+		}
+		colors[i] = -1
+		return false
+	}
+
+	for limit := 1; limit < greedy.NumColors; limit++ {
+		for i := range colors {
+			colors[i] = -1
+		}
+		if assign(0, 0, limit) {
+			return coloringResult(graph, colors), nil
+		}
+	}
+	return greedy, nil
+}
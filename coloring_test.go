@@ -0,0 +1,115 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// expectProperColoring checks that no edge joins two nodes of the same color
+func expectProperColoring(graph Graph, coloring Coloring, t *testing.T) {
+	for _, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if coloring.Colors[node.ID] == coloring.Colors[neighbor.ID] {
+				t.Errorf("Failed: expected adjacent nodes %s and %s to differ in color", node.ID, neighbor.ID)
+			}
+		}
+	}
+}
+
+// createCrownGraph builds the complete bipartite graph on 2n nodes without the edges i - n+i,
+// with the two sides interleaved so that coloring in graph order uses n colors.
+func createCrownGraph(n int) Graph {
+	var edges [][3]float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				edges = append(edges, [3]float64{float64(2 * i), float64(2*j + 1), 1})
+			}
+		}
+	}
+	return createWeightedGraph(2*n, edges)
+}
+
+func TestGreedyColoring(t *testing.T) {
+	var graph = createWeightedGraph(10, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1},
+		{0, 5, 1}, {1, 6, 1}, {2, 7, 1}, {3, 8, 1}, {4, 9, 1},
+		{5, 7, 1}, {7, 9, 1}, {9, 6, 1}, {6, 8, 1}, {8, 5, 1},
+	})
+	var strategies = map[string]ColoringStrategy{"LargestFirst": LargestFirst, "SmallestLast": SmallestLast, "DSatur": DSatur}
+
+	for name, strategy := range strategies {
+		describe("GreedyColoring with "+name, t)
+		var coloring, err = GreedyColoring(graph, strategy)
+		if err != nil {
+			t.Errorf("Failed: expected no error, but found %s", err)
+			return
+		}
+
+		it("colors the Petersen graph properly", t)
+		expectProperColoring(graph, coloring, t)
+		expectEqualBools(coloring.NumColors >= 3, true, t)
+
+		it("groups the nodes into color classes", t)
+		expectEqualInts(len(coloring.Classes), coloring.NumColors, t)
+		var total = 0
+		for color, class := range coloring.Classes {
+			total += len(class)
+			for _, node := range class {
+				expectEqualInts(coloring.Colors[node.ID], color, t)
+			}
+		}
+		expectEqualInts(total, 10, t)
+	}
+
+	describe("GreedyColoring with DSatur", t)
+	context("the graph is a crown graph", t)
+	var crown = createCrownGraph(4)
+	var coloring, _ = GreedyColoring(crown, DSatur)
+
+	it("colors the bipartite graph with two colors", t)
+	expectEqualInts(coloring.NumColors, 2, t)
+	expectProperColoring(crown, coloring, t)
+
+	context("the graph has a self-loop", t)
+	graph = createWeightedGraph(1, [][3]float64{{0, 0, 1}})
+	_, err := GreedyColoring(graph, DSatur)
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
+
+func TestExactColoring(t *testing.T) {
+	describe("ExactColoring", t)
+
+	context("the graph is a wheel with an odd rim", t)
+	// A wheel with a five-node rim needs four colors
+	var graph = createWeightedGraph(6, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1},
+		{5, 0, 1}, {5, 1, 1}, {5, 2, 1}, {5, 3, 1}, {5, 4, 1},
+	})
+	var coloring, err = ExactColoring(graph)
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("uses the chromatic number of colors", t)
+	expectEqualInts(coloring.NumColors, 4, t)
+	expectProperColoring(graph, coloring, t)
+
+	context("the graph is a crown graph", t)
+	graph = createCrownGraph(5)
+	coloring, _ = ExactColoring(graph)
+
+	it("uses two colors", t)
+	expectEqualInts(coloring.NumColors, 2, t)
+	expectProperColoring(graph, coloring, t)
+
+	context("the graph is empty", t)
+	coloring, _ = ExactColoring(Graph{})
+
+	it("uses no colors", t)
+	expectEqualInts(coloring.NumColors, 0, t)
+}
@@ -0,0 +1,486 @@
+package gograph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// CommunityResult assigns every node to a community, numbered from 0 in order of first
+// appearance in the graph, along with the modularity of that assignment.
+type CommunityResult struct {
+	Communities map[string]int
+	Modularity  float64
+}
+
+// communityEpsilon is the smallest modularity gain considered an improvement
+const communityEpsilon = 1e-12
+
+// neighborWeight is the total weight of the edges between a node and one of its neighbors
+type neighborWeight struct {
+	node   int
+	weight float64
+}
+
+// communityGraph is a weighted undirected graph over node indices as used by modularity
+// optimization. Self-loops count twice towards the strength of their node.
+type communityGraph struct {
+	neighbors [][]neighborWeight // excludes self-loops, in increasing node order
+	loops     []float64
+	strength  []float64
+	total     float64 // twice the total edge weight
+}
+
+// newCommunityGraph builds a community graph of n nodes from the total edge weight between
+// every pair of nodes, keyed with the lower index first.
+func newCommunityGraph(n int, pairs map[[2]int]float64, loops []float64) *communityGraph {
+	var graph = &communityGraph{
+		neighbors: make([][]neighborWeight, n),
+		loops:     loops,
+		strength:  make([]float64, n),
+	}
+	// Pairs are taken in order so that strengths are summed the same way on every run
+	var keys = make([][2]int, 0, len(pairs))
+	for pair := range pairs {
+		keys = append(keys, pair)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return keys[a][0] < keys[b][0] || (keys[a][0] == keys[b][0] && keys[a][1] < keys[b][1])
+	})
+	for _, pair := range keys {
+		var weight = pairs[pair]
+		graph.neighbors[pair[0]] = append(graph.neighbors[pair[0]], neighborWeight{node: pair[1], weight: weight})
+		graph.neighbors[pair[1]] = append(graph.neighbors[pair[1]], neighborWeight{node: pair[0], weight: weight})
+		graph.strength[pair[0]] += weight
+		graph.strength[pair[1]] += weight
+	}
+	for i := range graph.neighbors {
+		var list = graph.neighbors[i]
+		sort.Slice(list, func(a, b int) bool { return list[a].node < list[b].node })
+		graph.strength[i] += loops[i]
+		graph.total += graph.strength[i]
+	}
+	return graph
+}
+
+// addPair records the weight of an edge between two node indices
+func addPair(pairs map[[2]int]float64, loops []float64, i int, j int, weight float64) {
+	if i == j {
+		loops[i] += 2 * weight
+	} else if i < j {
+		pairs[[2]int{i, j}] += weight
+	} else {
+		pairs[[2]int{j, i}] += weight
+	}
+}
+
+func communityGraphFromGraph(graph Graph) *communityGraph {
+	var indices = indexNodes(graph)
+	var pairs = map[[2]int]float64{}
+	var loops = make([]float64, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			// Every edge between distinct nodes is listed by both of them
+			if j, ok := indices[neighbor]; ok && i <= j {
+				addPair(pairs, loops, i, j, EdgeWeight(node, neighbor))
+			}
+		}
+	}
+	return newCommunityGraph(len(graph.Nodes), pairs, loops)
+}
+
+// communityGraphFromDirectedGraph treats every edge of a directed graph as undirected,
+// summing the weights of opposing edges.
+func communityGraphFromDirectedGraph(graph DirectedGraph) *communityGraph {
+	var indices = indexDirectedNodes(graph)
+	var pairs = map[[2]int]float64{}
+	var loops = make([]float64, len(graph.DirectedNodes))
+	for i, parent := range graph.DirectedNodes {
+		for _, child := range parent.Children {
+			if j, ok := indices[child]; ok {
+				addPair(pairs, loops, i, j, DirectedEdgeWeight(parent, child))
+			}
+		}
+	}
+	return newCommunityGraph(len(graph.DirectedNodes), pairs, loops)
+}
+
+// modularity computes the modularity of a partition of the community graph's nodes, whose
+// communities are numbered below the number of nodes
+func (graph *communityGraph) modularity(membership []int) float64 {
+	if graph.total == 0 {
+		return 0
+	}
+	var internal = make([]float64, len(membership))
+	var totals = make([]float64, len(membership))
+	for i, c := range membership {
+		internal[c] += graph.loops[i]
+		totals[c] += graph.strength[i]
+		for _, neighbor := range graph.neighbors[i] {
+			if membership[neighbor.node] == c {
+				internal[c] += neighbor.weight
+			}
+		}
+	}
+	var q float64
+	for c, total := range totals {
+		q += internal[c]/graph.total - (total/graph.total)*(total/graph.total)
+	}
+	return q
+}
+
+// communityLinks sums the weights from a node to each neighboring community, listing the
+// communities in order of first appearance among the node's neighbors.
+func (graph *communityGraph) communityLinks(i int, membership []int) (map[int]float64, []int) {
+	var links = map[int]float64{}
+	var order []int
+	for _, neighbor := range graph.neighbors[i] {
+		var c = membership[neighbor.node]
+		if _, ok := links[c]; !ok {
+			order = append(order, c)
+		}
+		links[c] += neighbor.weight
+	}
+	return links, order
+}
+
+// bestCommunity moves a node into the neighboring community with the greatest modularity
+// gain, updating the community totals, and returns the community it ends up in.
+func (graph *communityGraph) bestCommunity(i int, membership []int, totals []float64) int {
+	var links, order = graph.communityLinks(i, membership)
+	var current = membership[i]
+	var strength = graph.strength[i]
+	totals[current] -= strength
+	var best, bestGain = current, links[current] - totals[current]*strength/graph.total
+	for _, c := range order {
+		var gain = links[c] - totals[c]*strength/graph.total
+		if gain > bestGain+communityEpsilon {
+			best, bestGain = c, gain
+		}
+	}
+	totals[best] += strength
+	membership[i] = best
+	return best
+}
+
+func (graph *communityGraph) communityTotals(membership []int) []float64 {
+	var totals = make([]float64, len(membership))
+	for i, c := range membership {
+		totals[c] += graph.strength[i]
+	}
+	return totals
+}
+
+// moveNodes repeatedly sweeps the nodes in random order, moving each to its best community,
+// until a sweep moves none. It reports whether any node moved.
+func (graph *communityGraph) moveNodes(membership []int, random *rand.Rand) bool {
+	var totals = graph.communityTotals(membership)
+	var order = random.Perm(len(membership))
+	var improved = false
+	for {
+		var moved = false
+		for _, i := range order {
+			var previous = membership[i]
+			if graph.bestCommunity(i, membership, totals) != previous {
+				moved, improved = true, true
+			}
+		}
+		if !moved {
+			return improved
+		}
+	}
+}
+
+// moveNodesFast visits the nodes from a queue, moving each to its best community and
+// requeueing the neighbors left outside of the community it moved to.
+func (graph *communityGraph) moveNodesFast(membership []int, random *rand.Rand) {
+	var totals = graph.communityTotals(membership)
+	var queue = random.Perm(len(membership))
+	var queued = make([]bool, len(membership))
+	for i := range queued {
+		queued[i] = true
+	}
+	for len(queue) > 0 {
+		var i = queue[0]
+		queue = queue[1:]
+		queued[i] = false
+		var previous = membership[i]
+		var best = graph.bestCommunity(i, membership, totals)
+		if best == previous {
+			continue
+		}
+		for _, neighbor := range graph.neighbors[i] {
+			if !queued[neighbor.node] && membership[neighbor.node] != best {
+				queued[neighbor.node] = true
+				queue = append(queue, neighbor.node)
+			}
+		}
+	}
+}
+
+// refine splits every community of a partition into well-connected subcommunities, merging
+// nodes greedily starting from singletons, as in the refinement phase of Leiden.
+func (graph *communityGraph) refine(membership []int, random *rand.Rand) []int {
+	var n = len(membership)
+	var refined = identityPartition(n)
+	var sizes = make([]int, n)
+	var refinedTotals = make([]float64, n)
+	var communityTotals = graph.communityTotals(membership)
+	var external = make([]float64, n) // weight from each refined community to the rest of its community
+	for i := range refined {
+		sizes[i] = 1
+		refinedTotals[i] = graph.strength[i]
+		for _, neighbor := range graph.neighbors[i] {
+			if membership[neighbor.node] == membership[i] {
+				external[i] += neighbor.weight
+			}
+		}
+	}
+
+	for _, i := range random.Perm(n) {
+		var c = membership[i]
+		var strength = graph.strength[i]
+		var remainder = communityTotals[c] - strength
+		if sizes[refined[i]] > 1 || external[i] < strength*remainder/graph.total {
+			continue
+		}
+
+		var links = map[int]float64{}
+		var order []int
+		for _, neighbor := range graph.neighbors[i] {
+			if membership[neighbor.node] != c {
+				continue
+			}
+			var r = refined[neighbor.node]
+			if _, ok := links[r]; !ok {
+				order = append(order, r)
+			}
+			links[r] += neighbor.weight
+		}
+
+		var best, bestGain = refined[i], 0.0
+		for _, r := range order {
+			if r == refined[i] || external[r] < refinedTotals[r]*(communityTotals[c]-refinedTotals[r])/graph.total {
+				continue
+			}
+			var gain = links[r] - refinedTotals[r]*strength/graph.total
+			if gain > bestGain+communityEpsilon {
+				best, bestGain = r, gain
+			}
+		}
+		if best == refined[i] {
+			continue
+		}
+		sizes[refined[i]]--
+		refinedTotals[refined[i]] -= strength
+		refined[i] = best
+		sizes[best]++
+		refinedTotals[best] += strength
+		external[best] += external[i] - 2*links[best]
+	}
+	return refined
+}
+
+// aggregate returns the community graph whose nodes are the communities of a partition
+func (graph *communityGraph) aggregate(membership []int, count int) *communityGraph {
+	var pairs = map[[2]int]float64{}
+	var loops = make([]float64, count)
+	for i, neighbors := range graph.neighbors {
+		loops[membership[i]] += graph.loops[i]
+		for _, neighbor := range neighbors {
+			if i < neighbor.node {
+				addPair(pairs, loops, membership[i], membership[neighbor.node], neighbor.weight)
+			}
+		}
+	}
+	return newCommunityGraph(count, pairs, loops)
+}
+
+func identityPartition(n int) []int {
+	var membership = make([]int, n)
+	for i := range membership {
+		membership[i] = i
+	}
+	return membership
+}
+
+// renumber relabels the communities of a partition from 0 in order of first appearance
+// and returns the number of communities.
+func renumber(membership []int) ([]int, int) {
+	var labels = map[int]int{}
+	var renumbered = make([]int, len(membership))
+	for i, c := range membership {
+		if _, ok := labels[c]; !ok {
+			labels[c] = len(labels)
+		}
+		renumbered[i] = labels[c]
+	}
+	return renumbered, len(labels)
+}
+
+func louvain(graph *communityGraph, random *rand.Rand) []int {
+	var assignment = identityPartition(len(graph.strength))
+	for {
+		var membership = identityPartition(len(graph.strength))
+		if !graph.moveNodes(membership, random) {
+			return assignment
+		}
+		var count int
+		membership, count = renumber(membership)
+		for v, node := range assignment {
+			assignment[v] = membership[node]
+		}
+		graph = graph.aggregate(membership, count)
+	}
+}
+
+func leiden(graph *communityGraph, random *rand.Rand) []int {
+	var assignment = identityPartition(len(graph.strength))
+	var membership = identityPartition(len(graph.strength))
+	for {
+		graph.moveNodesFast(membership, random)
+		var count int
+		membership, count = renumber(membership)
+		if count == len(membership) {
+			break
+		}
+
+		// Aggregate the refined partition, keeping the unrefined one as the starting point
+		var refined, refinedCount = renumber(graph.refine(membership, random))
+		if refinedCount == len(membership) {
+			break
+		}
+		for v, node := range assignment {
+			assignment[v] = refined[node]
+		}
+		var next = make([]int, refinedCount)
+		for i, r := range refined {
+			next[r] = membership[i]
+		}
+		graph, membership = graph.aggregate(refined, refinedCount), next
+	}
+	for v, node := range assignment {
+		assignment[v] = membership[node]
+	}
+	return assignment
+}
+
+func labelPropagation(graph *communityGraph, random *rand.Rand) []int {
+	var n = len(graph.strength)
+	var labels = identityPartition(n)
+	for iteration := 0; iteration < 100*n+1; iteration++ {
+		var changed = false
+		for _, i := range random.Perm(n) {
+			if len(graph.neighbors[i]) == 0 {
+				continue
+			}
+			var links, order = graph.communityLinks(i, labels)
+			var heaviest float64
+			for _, label := range order {
+				if links[label] > heaviest {
+					heaviest = links[label]
+				}
+			}
+			var candidates []int
+			var keep = false
+			for _, label := range order {
+				if links[label] >= heaviest-communityEpsilon {
+					candidates = append(candidates, label)
+					keep = keep || label == labels[i]
+				}
+			}
+			if !keep {
+				labels[i] = candidates[random.Intn(len(candidates))]
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return labels
+}
+
+// communityResult numbers the communities found by a detection algorithm and scores them
+func communityResult(graph *communityGraph, IDs []string, detect func(*communityGraph, *rand.Rand) []int, seed int64) CommunityResult {
+	var membership, _ = renumber(detect(graph, rand.New(rand.NewSource(seed))))
+	var result = CommunityResult{Communities: make(map[string]int, len(IDs)), Modularity: graph.modularity(membership)}
+	for index, ID := range IDs {
+		result.Communities[ID] = membership[index]
+	}
+	return result
+}
+
+func nodeIDs(graph Graph) []string {
+	var IDs = make([]string, len(graph.Nodes))
+	for index, node := range graph.Nodes {
+		IDs[index] = node.ID
+	}
+	return IDs
+}
+
+func directedNodeIDs(graph DirectedGraph) []string {
+	var IDs = make([]string, len(graph.DirectedNodes))
+	for index, node := range graph.DirectedNodes {
+		IDs[index] = node.ID
+	}
+	return IDs
+}
+
+// Louvain detects communities in an undirected graph by greedily moving nodes between
+// communities to increase modularity, then aggregating each community into a single node and
+// repeating. The seed fixes the order in which nodes are visited.
+func Louvain(graph Graph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromGraph(graph), nodeIDs(graph), louvain, seed)
+}
+
+// DirectedLouvain applies Louvain to a directed graph treated as undirected
+func DirectedLouvain(graph DirectedGraph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromDirectedGraph(graph), directedNodeIDs(graph), louvain, seed)
+}
+
+// Leiden detects communities in an undirected graph like Louvain, but refines every
+// community into well-connected subcommunities before aggregating, which guarantees that the
+// communities found are connected. The seed fixes the order in which nodes are visited.
+func Leiden(graph Graph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromGraph(graph), nodeIDs(graph), leiden, seed)
+}
+
+// DirectedLeiden applies Leiden to a directed graph treated as undirected
+func DirectedLeiden(graph DirectedGraph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromDirectedGraph(graph), directedNodeIDs(graph), leiden, seed)
+}
+
+// LabelPropagation detects communities in an undirected graph by repeatedly relabeling every
+// node with the label carrying the most edge weight among its neighbors until no label
+// changes. The seed fixes the visiting order and how ties are broken.
+func LabelPropagation(graph Graph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromGraph(graph), nodeIDs(graph), labelPropagation, seed)
+}
+
+// DirectedLabelPropagation applies label propagation to a directed graph treated as undirected
+func DirectedLabelPropagation(graph DirectedGraph, seed int64) CommunityResult {
+	return communityResult(communityGraphFromDirectedGraph(graph), directedNodeIDs(graph), labelPropagation, seed)
+}
+
+// Modularity computes the modularity of an assignment of the nodes of an undirected graph to
+// communities: the fraction of edge weight within communities, less the fraction expected if
+// edges were placed at random while preserving node strengths.
+func Modularity(graph Graph, communities map[string]int) float64 {
+	var membership = make([]int, len(graph.Nodes))
+	for index, node := range graph.Nodes {
+		membership[index] = communities[node.ID]
+	}
+	membership, _ = renumber(membership)
+	return communityGraphFromGraph(graph).modularity(membership)
+}
+
+// DirectedModularity computes the modularity of an assignment of the nodes of a directed
+// graph to communities, with the graph treated as undirected.
+func DirectedModularity(graph DirectedGraph, communities map[string]int) float64 {
+	var membership = make([]int, len(graph.DirectedNodes))
+	for index, node := range graph.DirectedNodes {
+		membership[index] = communities[node.ID]
+	}
+	membership, _ = renumber(membership)
+	return communityGraphFromDirectedGraph(graph).modularity(membership)
+}
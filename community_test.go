@@ -0,0 +1,90 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// createTwoCliqueEdges joins two complete graphs on four nodes by a single edge
+func createTwoCliqueEdges() [][3]float64 {
+	var edges [][3]float64
+	for _, offset := range []float64{0, 4} {
+		for i := 0.0; i < 4; i++ {
+			for j := i + 1; j < 4; j++ {
+				edges = append(edges, [3]float64{offset + i, offset + j, 1})
+			}
+		}
+	}
+	return append(edges, [3]float64{3, 4, 1})
+}
+
+func TestCommunityDetection(t *testing.T) {
+	var algorithms = map[string]func(Graph, int64) CommunityResult{
+		"Louvain":          Louvain,
+		"Leiden":           Leiden,
+		"LabelPropagation": LabelPropagation,
+	}
+	var directedAlgorithms = map[string]func(DirectedGraph, int64) CommunityResult{
+		"Louvain":          DirectedLouvain,
+		"Leiden":           DirectedLeiden,
+		"LabelPropagation": DirectedLabelPropagation,
+	}
+	var expectedModularity = 2 * (12.0/26 - 0.25)
+
+	for name, algorithm := range algorithms {
+		describe(name, t)
+		var graph = createWeightedGraph(8, createTwoCliqueEdges())
+		var result = algorithm(graph, 42)
+
+		it("separates the two cliques", t)
+		for index, node := range graph.Nodes {
+			expectEqualInts(result.Communities[node.ID], index/4, t)
+		}
+
+		it("returns the modularity of the communities", t)
+		expectEqualFloats(result.Modularity, expectedModularity, t)
+
+		it("returns the same communities for the same seed", t)
+		var repeated = algorithm(graph, 42)
+		for _, node := range graph.Nodes {
+			expectEqualInts(repeated.Communities[node.ID], result.Communities[node.ID], t)
+		}
+
+		describe("Directed"+name, t)
+		var directed = createWeightedDirectedGraph(8, createTwoCliqueEdges())
+		result = directedAlgorithms[name](directed, 7)
+
+		it("separates the two cliques with the graph treated as undirected", t)
+		for index, node := range directed.DirectedNodes {
+			expectEqualInts(result.Communities[node.ID], index/4, t)
+		}
+		expectEqualFloats(result.Modularity, expectedModularity, t)
+	}
+}
+
+func TestModularity(t *testing.T) {
+	describe("Modularity", t)
+	var graph = createWeightedGraph(8, createTwoCliqueEdges())
+	var communities = map[string]int{}
+
+	context("every node is in the same community", t)
+	it("returns zero", t)
+	expectEqualFloats(Modularity(graph, communities), 0, t)
+
+	context("each clique is a community", t)
+	for index, node := range graph.Nodes {
+		communities[node.ID] = 10 + index/4
+	}
+
+	it("weighs internal edges against the expectation", t)
+	expectEqualFloats(Modularity(graph, communities), 2*(12.0/26-0.25), t)
+
+	context("the graph is directed", t)
+	var directed = createWeightedDirectedGraph(8, createTwoCliqueEdges())
+	communities = map[string]int{}
+	for index, node := range directed.DirectedNodes {
+		communities[node.ID] = index / 4
+	}
+
+	it("treats the graph as undirected", t)
+	expectEqualFloats(DirectedModularity(directed, communities), 2*(12.0/26-0.25), t)
+}
@@ -0,0 +1,262 @@
+package gograph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiagramOptions configure the Mermaid and PlantUML diagrams of a graph
+type DiagramOptions struct {
+	Direction    string         // layout direction: "TB", the default, "LR", "BT" or "RL"
+	LabelKeys    []string       // node value keys whose values label each node, one per line, or its ID if it has none
+	EdgeLabelKey string         // edge value key whose value is shown as the edge's label, such as WeightKey
+	GroupKey     string         // node value key whose nonempty values group nodes into subgraphs, or none if empty
+	Styles       []DiagramStyle // styles given to the nodes whose values they match
+}
+
+// DiagramStyle colors the nodes whose values match a predicate. Colors are given as CSS
+// colors, such as "#f96" or "red", and are left unset if empty.
+type DiagramStyle struct {
+	Name   string // name of the style, a letter followed by letters, digits and underscores
+	Fill   string // background color
+	Stroke string // border color
+	Color  string // text color
+	Match  func(values map[string]string) bool
+}
+
+// diagramName matches the names of styles, which both Mermaid and PlantUML accept as is
+var diagramName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// diagramColor matches colors that cannot break the syntax of a diagram
+var diagramColor = regexp.MustCompile(`^#?[A-Za-z0-9]*$`)
+
+// diagram is a directed graph prepared for drawing, with its nodes named by number
+type diagram struct {
+	IDs    []string
+	labels []string
+	groups []string // distinct groups in order of their first node
+	group  []string // group of each node, if it has one
+	styles [][]int  // indexes of the nodes matching each style
+	edges  [][2]int // edges by node number
+	edge   []string // label of each edge, if it has one
+}
+
+// createDiagram checks the options and gathers the labels, groups and styles of a graph
+func createDiagram(graph DirectedGraph, options DiagramOptions) (diagram, error) {
+	var drawn = diagram{
+		IDs:    directedNodeIDs(graph),
+		labels: make([]string, len(graph.DirectedNodes)),
+		group:  make([]string, len(graph.DirectedNodes)),
+		styles: make([][]int, len(options.Styles)),
+	}
+	for _, style := range options.Styles {
+		if !diagramName.MatchString(style.Name) {
+			return diagram{}, fmt.Errorf("style name %q is not a letter followed by letters, digits and underscores", style.Name)
+		}
+		for _, color := range []string{style.Fill, style.Stroke, style.Color} {
+			if !diagramColor.MatchString(color) {
+				return diagram{}, fmt.Errorf("style %q has color %q, which is not a name or a hex code", style.Name, color)
+			}
+		}
+		if style.Match == nil {
+			return diagram{}, fmt.Errorf("style %q has no predicate to match nodes", style.Name)
+		}
+	}
+
+	var seen = map[string]bool{}
+	for index, node := range graph.DirectedNodes {
+		var lines []string
+		for _, key := range options.LabelKeys {
+			if value, ok := node.Values[key]; ok {
+				lines = append(lines, value)
+			}
+		}
+		drawn.labels[index] = node.ID
+		if len(lines) > 0 {
+			drawn.labels[index] = strings.Join(lines, "\n")
+		}
+		if group := node.Values[options.GroupKey]; group != "" && options.GroupKey != "" {
+			drawn.group[index] = group
+			if !seen[group] {
+				seen[group] = true
+				drawn.groups = append(drawn.groups, group)
+			}
+		}
+		for k, style := range options.Styles {
+			if style.Match(node.Values) {
+				drawn.styles[k] = append(drawn.styles[k], index)
+			}
+		}
+	}
+
+	var indices = indexDirectedNodes(graph)
+	for i, node := range graph.DirectedNodes {
+		for _, child := range node.Children {
+			if j, ok := indices[child]; ok {
+				drawn.edges = append(drawn.edges, [2]int{i, j})
+				var label string
+				if options.EdgeLabelKey != "" {
+					label = node.EdgeValues[child.ID][options.EdgeLabelKey]
+				}
+				drawn.edge = append(drawn.edge, label)
+			}
+		}
+	}
+	return drawn, nil
+}
+
+// diagramNode names the node of a diagram with a number
+func diagramNode(index int) string {
+	return "n" + strconv.Itoa(index)
+}
+
+// escapeMermaid escapes the text of a quoted Mermaid label with entity codes, breaking lines
+// with <br/>
+func escapeMermaid(text string) string {
+	return strings.NewReplacer("#", "#35;", `"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r\n", "<br/>", "\r", "<br/>", "\n", "<br/>").Replace(text)
+}
+
+// WriteMermaid writes a directed graph as a Mermaid flowchart. Nodes are named n0, n1 and so on
+// in order, labeled with their values under options.LabelKeys or else their IDs, and gathered
+// into a subgraph for each distinct value under options.GroupKey. Edges are labeled with their
+// value under options.EdgeLabelKey, and every style becomes a class of the nodes it matches.
+// An error is returned if the direction, a style name or a color is not valid.
+func WriteMermaid(writer io.Writer, graph DirectedGraph, options DiagramOptions) error {
+	var direction = options.Direction
+	if direction == "" {
+		direction = "TB"
+	}
+	switch direction {
+	case "TB", "TD", "BT", "LR", "RL":
+	default:
+		return fmt.Errorf("direction %q is not one of TB, LR, BT and RL", options.Direction)
+	}
+	var drawn, err = createDiagram(graph, options)
+	if err != nil {
+		return err
+	}
+
+	var buffered = bufio.NewWriter(writer)
+	buffered.WriteString("flowchart " + direction + "\n")
+	var declare = func(index int, indent string) {
+		buffered.WriteString(indent + diagramNode(index) + `["` + escapeMermaid(drawn.labels[index]) + "\"]\n")
+	}
+	for index := range drawn.IDs {
+		if drawn.group[index] == "" {
+			declare(index, "    ")
+		}
+	}
+	for k, group := range drawn.groups {
+		buffered.WriteString("    subgraph g" + strconv.Itoa(k) + `["` + escapeMermaid(group) + "\"]\n")
+		for index := range drawn.IDs {
+			if drawn.group[index] == group {
+				declare(index, "        ")
+			}
+		}
+		buffered.WriteString("    end\n")
+	}
+	for k, edge := range drawn.edges {
+		var arrow = " --> "
+		if drawn.edge[k] != "" {
+			arrow = ` -->|"` + escapeMermaid(drawn.edge[k]) + `"| `
+		}
+		buffered.WriteString("    " + diagramNode(edge[0]) + arrow + diagramNode(edge[1]) + "\n")
+	}
+	for k, style := range options.Styles {
+		var properties []string
+		for _, property := range [][2]string{{"fill", style.Fill}, {"stroke", style.Stroke}, {"color", style.Color}} {
+			if property[1] != "" {
+				properties = append(properties, property[0]+":"+property[1])
+			}
+		}
+		if len(properties) > 0 {
+			buffered.WriteString("    classDef " + style.Name + " " + strings.Join(properties, ",") + "\n")
+		}
+		if len(drawn.styles[k]) > 0 {
+			var names = make([]string, len(drawn.styles[k]))
+			for i, index := range drawn.styles[k] {
+				names[i] = diagramNode(index)
+			}
+			buffered.WriteString("    class " + strings.Join(names, ",") + " " + style.Name + "\n")
+		}
+	}
+	return buffered.Flush()
+}
+
+// escapePlantUML escapes the text of a PlantUML label, writing quotes and backslashes as
+// Unicode code points and line breaks as \n
+func escapePlantUML(text string) string {
+	return strings.NewReplacer(`\`, "<U+005C>", `"`, "<U+0022>", "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// WritePlantUML writes a directed graph as a PlantUML diagram of rectangles. Nodes are named n0,
+// n1 and so on in order, labeled with their values under options.LabelKeys or else their IDs,
+// and gathered into a package for each distinct value under options.GroupKey. Edges are
+// labeled with their value under options.EdgeLabelKey, and every style becomes a stereotype of
+// the nodes it matches, colored by skin parameters; a node matching several styles takes the
+// first. PlantUML lays out diagrams from top to bottom or from left to right only, so an error
+// is returned for the directions BT and RL, and if a style name or a color is not valid.
+func WritePlantUML(writer io.Writer, graph DirectedGraph, options DiagramOptions) error {
+	var layout string
+	switch options.Direction {
+	case "", "TB", "TD":
+	case "LR":
+		layout = "left to right direction\n"
+	default:
+		return fmt.Errorf("direction %q is not one of TB and LR, which PlantUML lays out", options.Direction)
+	}
+	var drawn, err = createDiagram(graph, options)
+	if err != nil {
+		return err
+	}
+	var stereotypes = make([]string, len(drawn.IDs))
+	for k := len(options.Styles) - 1; k >= 0; k-- {
+		for _, index := range drawn.styles[k] {
+			stereotypes[index] = " <<" + options.Styles[k].Name + ">>"
+		}
+	}
+
+	var buffered = bufio.NewWriter(writer)
+	buffered.WriteString("@startuml\n" + layout)
+	for _, style := range options.Styles {
+		var parameters []string
+		for _, parameter := range [][2]string{{"BackgroundColor", style.Fill}, {"BorderColor", style.Stroke}, {"FontColor", style.Color}} {
+			if parameter[1] != "" {
+				parameters = append(parameters, "  "+parameter[0]+" "+parameter[1]+"\n")
+			}
+		}
+		if len(parameters) > 0 {
+			buffered.WriteString("skinparam rectangle<<" + style.Name + ">> {\n" + strings.Join(parameters, "") + "}\n")
+		}
+	}
+	var declare = func(index int, indent string) {
+		buffered.WriteString(indent + `rectangle "` + escapePlantUML(drawn.labels[index]) + `" as ` + diagramNode(index) + stereotypes[index] + "\n")
+	}
+	for index := range drawn.IDs {
+		if drawn.group[index] == "" {
+			declare(index, "")
+		}
+	}
+	for k, group := range drawn.groups {
+		buffered.WriteString(`package "` + escapePlantUML(group) + `" as g` + strconv.Itoa(k) + " {\n")
+		for index := range drawn.IDs {
+			if drawn.group[index] == group {
+				declare(index, "  ")
+			}
+		}
+		buffered.WriteString("}\n")
+	}
+	for k, edge := range drawn.edges {
+		buffered.WriteString(diagramNode(edge[0]) + " --> " + diagramNode(edge[1]))
+		if drawn.edge[k] != "" {
+			buffered.WriteString(" : " + escapePlantUML(drawn.edge[k]))
+		}
+		buffered.WriteString("\n")
+	}
+	buffered.WriteString("@enduml\n")
+	return buffered.Flush()
+}
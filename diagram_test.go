@@ -0,0 +1,103 @@
+package gograph
+
+import (
+	"bytes"
+	"testing"
+)
+
+// createDiagramTestOptions labels nodes by name and color, groups them, and styles red nodes
+func createDiagramTestOptions() DiagramOptions {
+	return DiagramOptions{
+		Direction:    "LR",
+		LabelKeys:    []string{"name", "color"},
+		EdgeLabelKey: WeightKey,
+		GroupKey:     "group",
+		Styles: []DiagramStyle{
+			{Name: "hot", Fill: "#f96", Stroke: "red", Match: func(values map[string]string) bool { return values["color"] == "red" }},
+			{Name: "valued", Color: "white", Match: func(values map[string]string) bool { return len(values) > 0 }},
+		},
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	describe("WriteMermaid", t)
+	var graph = createDOTTestGraph()
+	graph.DirectedNodes[2].Values = map[string]string{"name": "Sink \"#1\"\nend"}
+
+	it("writes a flowchart of labeled nodes in subgraphs with styled classes", t)
+	var buffer bytes.Buffer
+	var err = WriteMermaid(&buffer, graph, createDiagramTestOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `flowchart LR
+    n2["Sink #quot;#35;1#quot;<br/>end"]
+    subgraph g0["left"]
+        n0["red"]
+        n1["b"]
+    end
+    n0 -->|"2.5"| n1
+    n0 --> n2
+    n1 --> n2
+    classDef hot fill:#f96,stroke:red
+    class n0 hot
+    classDef valued color:white
+    class n0,n1,n2 valued
+`, t)
+
+	it("labels nodes by ID and lays them out from top to bottom by default", t)
+	buffer.Reset()
+	WriteMermaid(&buffer, graph, DiagramOptions{})
+	expectEqualStrings(buffer.String(), "flowchart TB\n    n0[\"a\"]\n    n1[\"b\"]\n    n2[\"c\"]\n    n0 --> n1\n    n0 --> n2\n    n1 --> n2\n", t)
+
+	context("the options are not valid", t)
+
+	it("returns an error", t)
+	for _, options := range []DiagramOptions{
+		{Direction: "up"},
+		{Styles: []DiagramStyle{{Name: "two words", Match: func(map[string]string) bool { return true }}}},
+		{Styles: []DiagramStyle{{Name: "bad", Fill: "red;stroke:blue", Match: func(map[string]string) bool { return true }}}},
+		{Styles: []DiagramStyle{{Name: "unmatched"}}},
+	} {
+		expectEqualBools(WriteMermaid(&buffer, graph, options) == nil, false, t)
+	}
+}
+
+func TestWritePlantUML(t *testing.T) {
+	describe("WritePlantUML", t)
+	var graph = createDOTTestGraph()
+	graph.DirectedNodes[2].Values = map[string]string{"name": "Sink \"1\" \\ end\nline"}
+
+	it("writes rectangles in packages with stereotypes colored by skin parameters", t)
+	var buffer bytes.Buffer
+	var err = WritePlantUML(&buffer, graph, createDiagramTestOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `@startuml
+left to right direction
+skinparam rectangle<<hot>> {
+  BackgroundColor #f96
+  BorderColor red
+}
+skinparam rectangle<<valued>> {
+  FontColor white
+}
+rectangle "Sink <U+0022>1<U+0022> <U+005C> end\nline" as n2 <<valued>>
+package "left" as g0 {
+  rectangle "red" as n0 <<hot>>
+  rectangle "b" as n1 <<valued>>
+}
+n0 --> n1 : 2.5
+n0 --> n2
+n1 --> n2
+@enduml
+`, t)
+
+	it("lays nodes out from top to bottom by default", t)
+	buffer.Reset()
+	WritePlantUML(&buffer, graph, DiagramOptions{})
+	expectEqualStrings(buffer.String(), "@startuml\nrectangle \"a\" as n0\nrectangle \"b\" as n1\nrectangle \"c\" as n2\nn0 --> n1\nn0 --> n2\nn1 --> n2\n@enduml\n", t)
+
+	context("the direction is one PlantUML cannot lay out", t)
+
+	it("returns an error", t)
+	expectEqualBools(WritePlantUML(&buffer, graph, DiagramOptions{Direction: "BT"}) == nil, false, t)
+	expectEqualBools(WritePlantUML(&buffer, graph, DiagramOptions{Direction: "RL"}) == nil, false, t)
+}
@@ -0,0 +1,698 @@
+package gograph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DOTOptions controls how graphs are written to and read from the Graphviz DOT language
+type DOTOptions struct {
+	Name       string // name of the graph, or none if empty
+	ClusterKey string // node value key whose values group nodes into clusters, or none if empty
+	LabelKey   string // edge value key whose value is shown as the edge's label, such as WeightKey
+}
+
+// dotIdentifier matches the IDs that need no quotes in DOT
+var dotIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*$`)
+
+// dotKeywords are the words reserved by the DOT language, in any case
+var dotKeywords = map[string]bool{"strict": true, "graph": true, "digraph": true, "node": true, "edge": true, "subgraph": true}
+
+// quoteDOT writes a string as a DOT ID, quoting it unless it is a plain identifier. Quotes,
+// backslashes and line breaks are escaped so that ReadDOT gives back the string.
+func quoteDOT(text string) string {
+	if dotIdentifier.MatchString(text) && !dotKeywords[strings.ToLower(text)] {
+		return text
+	}
+	var replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(text) + `"`
+}
+
+// dotAttributes writes a set of values as a DOT attribute list in order of key, or nothing if
+// there are none
+func dotAttributes(values map[string]string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	var keys = make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var attributes = make([]string, len(keys))
+	for index, key := range keys {
+		attributes[index] = quoteDOT(key) + "=" + quoteDOT(values[key])
+	}
+	return " [" + strings.Join(attributes, ", ") + "]"
+}
+
+// dotNode is a node to write, by ID with its values
+type dotNode struct {
+	ID     string
+	values map[string]string
+}
+
+// writeDOT writes the statements of a graph: its attributes, every node in order, the clusters
+// the nodes belong to and every edge
+func writeDOT(writer io.Writer, directed bool, attributes map[string]string, nodes []dotNode, edges []Edge, edgeValues []map[string]string, options DOTOptions) error {
+	var buffered = bufio.NewWriter(writer)
+	var keyword, connector = "graph", " -- "
+	if directed {
+		keyword, connector = "digraph", " -> "
+	}
+	buffered.WriteString(keyword)
+	if options.Name != "" {
+		buffered.WriteString(" " + quoteDOT(options.Name))
+	}
+	buffered.WriteString(" {\n")
+	var keys = make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buffered.WriteString("\t" + quoteDOT(key) + "=" + quoteDOT(attributes[key]) + ";\n")
+	}
+
+	var clusters []string
+	var members = map[string][]string{}
+	for _, node := range nodes {
+		buffered.WriteString("\t" + quoteDOT(node.ID) + dotAttributes(node.values) + ";\n")
+		if cluster, ok := node.values[options.ClusterKey]; ok && options.ClusterKey != "" {
+			if members[cluster] == nil {
+				clusters = append(clusters, cluster)
+			}
+			members[cluster] = append(members[cluster], node.ID)
+		}
+	}
+	for _, cluster := range clusters {
+		buffered.WriteString("\tsubgraph " + quoteDOT("cluster_"+cluster) + " {\n")
+		buffered.WriteString("\t\tlabel=" + quoteDOT(cluster) + ";\n")
+		for _, ID := range members[cluster] {
+			buffered.WriteString("\t\t" + quoteDOT(ID) + ";\n")
+		}
+		buffered.WriteString("\t}\n")
+	}
+
+	for index, edge := range edges {
+		var values = edgeValues[index]
+		if label, ok := values[options.LabelKey]; ok && options.LabelKey != "" && values["label"] == "" {
+			var labeled = map[string]string{"label": label}
+			for key, value := range values {
+				labeled[key] = value
+			}
+			values = labeled
+		}
+		buffered.WriteString("\t" + quoteDOT(edge.From) + connector + quoteDOT(edge.To) + dotAttributes(values) + ";\n")
+	}
+	buffered.WriteString("}\n")
+	return buffered.Flush()
+}
+
+// WriteDOT writes a directed graph in the Graphviz DOT language. Every node is declared by its
+// ID in order, with its Values as attributes, and every edge is written with its edge values as
+// attributes, so that weights appear as weight attributes. The root is recorded as the graph's
+// root attribute. Nodes with a value under options.ClusterKey are gathered into a cluster
+// subgraph for each distinct value, and the value under options.LabelKey becomes each edge's
+// label. ReadDOT with the same options gives back the graph.
+func WriteDOT(writer io.Writer, graph DirectedGraph, options DOTOptions) error {
+	var indices = indexDirectedNodes(graph)
+	var nodes = make([]dotNode, len(graph.DirectedNodes))
+	var edges []Edge
+	var edgeValues []map[string]string
+	for index, node := range graph.DirectedNodes {
+		nodes[index] = dotNode{ID: node.ID, values: node.Values}
+		for _, child := range node.Children {
+			if _, ok := indices[child]; ok {
+				edges = append(edges, Edge{From: node.ID, To: child.ID})
+				edgeValues = append(edgeValues, node.EdgeValues[child.ID])
+			}
+		}
+	}
+	var attributes = map[string]string{}
+	if graph.RootDirectedNode != nil {
+		attributes["root"] = graph.RootDirectedNode.ID
+	}
+	return writeDOT(writer, true, attributes, nodes, edges, edgeValues, options)
+}
+
+// WriteUndirectedDOT writes an undirected graph in the Graphviz DOT language, like WriteDOT.
+// Every edge is written once, from the earlier of its two nodes as in EdgeList.
+func WriteUndirectedDOT(writer io.Writer, graph Graph, options DOTOptions) error {
+	var indices = indexNodes(graph)
+	var nodes = make([]dotNode, len(graph.Nodes))
+	var edges []Edge
+	var edgeValues []map[string]string
+	for i, node := range graph.Nodes {
+		nodes[i] = dotNode{ID: node.ID, values: node.Values}
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i <= j {
+				edges = append(edges, Edge{From: node.ID, To: neighbor.ID})
+				edgeValues = append(edgeValues, node.EdgeValues[neighbor.ID])
+			}
+		}
+	}
+	return writeDOT(writer, false, nil, nodes, edges, edgeValues, options)
+}
+
+// dotToken is a word or symbol of the DOT language. IDs of every form, whether identifiers,
+// numerals, quoted strings or HTML strings, have the kind "ID".
+type dotToken struct {
+	kind   string // "ID", "->", "--" or a single punctuation character
+	text   string
+	quoted bool // whether the ID was a quoted or HTML string, which is never a keyword
+	line   int
+}
+
+// keyword reports whether the token is the given DOT keyword
+func (token dotToken) keyword(word string) bool {
+	return token.kind == "ID" && !token.quoted && strings.EqualFold(token.text, word)
+}
+
+// tokenizeDOT splits DOT source into tokens, dropping comments and preprocessor lines
+func tokenizeDOT(source string) ([]dotToken, error) {
+	var runes = []rune(source)
+	var tokens []dotToken
+	var line = 1
+	var atLineStart = true
+	for i := 0; i < len(runes); {
+		var r = runes[i]
+		switch {
+		case r == '\n':
+			line++
+			atLineStart = true
+			i++
+			continue
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '#' && atLineStart:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+		}
+		atLineStart = false
+		var start = line
+		switch {
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("DOT comment starting on line %d is never closed", start)
+			}
+			i += 2
+		case r == '"':
+			var text strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					switch runes[i+1] {
+					case '"', '\\':
+						text.WriteRune(runes[i+1])
+						i++
+						continue
+					case 'n':
+						text.WriteRune('\n')
+						i++
+						continue
+					case '\n':
+						line++
+						i++
+						continue
+					}
+				}
+				text.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("DOT string starting on line %d is never closed", start)
+			}
+			i++
+			tokens = append(tokens, dotToken{kind: "ID", text: text.String(), quoted: true, line: start})
+		case r == '<':
+			var depth = 0
+			var begin = i + 1
+			for ; i < len(runes); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+				if runes[i] == '<' {
+					depth++
+				} else if runes[i] == '>' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("DOT HTML string starting on line %d is never closed", start)
+			}
+			tokens = append(tokens, dotToken{kind: "ID", text: string(runes[begin:i]), quoted: true, line: start})
+			i++
+		case r == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-'):
+			tokens = append(tokens, dotToken{kind: string(runes[i : i+2]), line: start})
+			i += 2
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			var begin = i
+			i++
+			for i < len(runes) && (runes[i] == '.' || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, dotToken{kind: "ID", text: string(runes[begin:i]), line: start})
+		case r == '_' || unicode.IsLetter(r) || r >= 0x80:
+			var begin = i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] >= 0x80) {
+				i++
+			}
+			tokens = append(tokens, dotToken{kind: "ID", text: string(runes[begin:i]), line: start})
+		case strings.ContainsRune("{}[]=;,:+", r):
+			tokens = append(tokens, dotToken{kind: string(r), line: start})
+			i++
+		default:
+			return nil, fmt.Errorf("DOT syntax error on line %d: unexpected character %q", line, r)
+		}
+	}
+	return tokens, nil
+}
+
+// dotEdge is a parsed edge between the nodes at two positions, with its attributes
+type dotEdge struct {
+	from, to int
+	values   map[string]string
+}
+
+// dotScope holds the defaults in effect within a graph or subgraph
+type dotScope struct {
+	nodeDefaults map[string]string
+	edgeDefaults map[string]string
+	cluster      string // name of the innermost cluster, without its "cluster" prefix
+	inCluster    bool
+	top          bool // whether this is the graph itself rather than a subgraph
+}
+
+// dotParser parses DOT tokens into nodes, edges and graph attributes
+type dotParser struct {
+	tokens     []dotToken
+	position   int
+	directed   bool
+	strict     bool
+	clusterKey string
+	IDs        []string
+	index      map[string]int
+	values     []map[string]string
+	edges      []dotEdge
+	attributes map[string]string
+}
+
+// peek returns the next token without consuming it, or false at the end of the input
+func (parser *dotParser) peek() (dotToken, bool) {
+	if parser.position >= len(parser.tokens) {
+		return dotToken{}, false
+	}
+	return parser.tokens[parser.position], true
+}
+
+// at reports whether the next token has the given kind
+func (parser *dotParser) at(kind string) bool {
+	var token, ok = parser.peek()
+	return ok && token.kind == kind
+}
+
+// expect consumes a token of the given kind, or returns an error naming what was found instead
+func (parser *dotParser) expect(kind string) (dotToken, error) {
+	var token, ok = parser.peek()
+	if !ok {
+		return dotToken{}, fmt.Errorf("DOT syntax error: expected %q, but the input ended", kind)
+	}
+	if token.kind != kind {
+		var found = token.kind
+		if found == "ID" {
+			found = token.text
+		}
+		return dotToken{}, fmt.Errorf("DOT syntax error on line %d: expected %q, but found %q", token.line, kind, found)
+	}
+	parser.position++
+	return token, nil
+}
+
+// parseID consumes an ID, joining quoted strings concatenated with +
+func (parser *dotParser) parseID() (string, error) {
+	var token, err = parser.expect("ID")
+	if err != nil {
+		return "", err
+	}
+	var text = token.text
+	for parser.at("+") {
+		parser.position++
+		var next, err = parser.expect("ID")
+		if err != nil {
+			return "", err
+		}
+		text += next.text
+	}
+	return text, nil
+}
+
+// parseAttributes consumes one or more bracketed attribute lists, adding them to a copy of the
+// defaults. An attribute without a value is set to "true".
+func (parser *dotParser) parseAttributes(defaults map[string]string) (map[string]string, error) {
+	var attributes = map[string]string{}
+	for key, value := range defaults {
+		attributes[key] = value
+	}
+	for parser.at("[") {
+		parser.position++
+		for !parser.at("]") {
+			var key, err = parser.parseID()
+			if err != nil {
+				return nil, err
+			}
+			var value = "true"
+			if parser.at("=") {
+				parser.position++
+				if value, err = parser.parseID(); err != nil {
+					return nil, err
+				}
+			}
+			attributes[key] = value
+			if parser.at(";") || parser.at(",") {
+				parser.position++
+			}
+		}
+		parser.position++
+	}
+	return attributes, nil
+}
+
+// node returns the position of the node with an ID, creating it with the node defaults of the
+// scope if it is new, and records the scope's cluster on it
+func (parser *dotParser) node(ID string, scope dotScope) int {
+	var position, ok = parser.index[ID]
+	if !ok {
+		position = len(parser.IDs)
+		parser.index[ID] = position
+		parser.IDs = append(parser.IDs, ID)
+		var values map[string]string
+		if len(scope.nodeDefaults) > 0 {
+			values = map[string]string{}
+			for key, value := range scope.nodeDefaults {
+				values[key] = value
+			}
+		}
+		parser.values = append(parser.values, values)
+	}
+	if scope.inCluster && parser.clusterKey != "" {
+		if _, set := parser.values[position][parser.clusterKey]; !set {
+			if parser.values[position] == nil {
+				parser.values[position] = map[string]string{}
+			}
+			parser.values[position][parser.clusterKey] = scope.cluster
+		}
+	}
+	return position
+}
+
+// parseOperand consumes a node ID with an optional port, or a subgraph, returning the nodes it
+// names and whether it was a single node
+func (parser *dotParser) parseOperand(scope dotScope) ([]int, bool, error) {
+	var token, _ = parser.peek()
+	if token.keyword("subgraph") || token.kind == "{" {
+		var name string
+		if token.keyword("subgraph") {
+			parser.position++
+			if parser.at("ID") {
+				var err error
+				if name, err = parser.parseID(); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+		if _, err := parser.expect("{"); err != nil {
+			return nil, false, err
+		}
+		var inner = scope
+		inner.top = false
+		if strings.HasPrefix(strings.ToLower(name), "cluster") {
+			inner.cluster, inner.inCluster = strings.TrimPrefix(name[len("cluster"):], "_"), true
+		}
+		var members, err = parser.parseStatements(inner)
+		return members, false, err
+	}
+	var ID, err = parser.parseID()
+	if err != nil {
+		return nil, false, err
+	}
+	// Ports and compass points place edge ends on a node and are not kept
+	for count := 0; count < 2 && parser.at(":"); count++ {
+		parser.position++
+		if _, err := parser.parseID(); err != nil {
+			return nil, false, err
+		}
+	}
+	return []int{parser.node(ID, scope)}, true, nil
+}
+
+// parseStatements consumes the statements of a graph or subgraph up to its closing brace,
+// returning every node the statements name
+func (parser *dotParser) parseStatements(scope dotScope) ([]int, error) {
+	var members []int
+	var seen = map[int]bool{}
+	var include = func(nodes []int) {
+		for _, node := range nodes {
+			if !seen[node] {
+				seen[node] = true
+				members = append(members, node)
+			}
+		}
+	}
+	for {
+		var token, ok = parser.peek()
+		if !ok {
+			return nil, errors.New("DOT syntax error: expected \"}\", but the input ended")
+		}
+		switch {
+		case token.kind == "}":
+			parser.position++
+			return members, nil
+		case token.kind == ";":
+			parser.position++
+		case token.keyword("graph") || token.keyword("node") || token.keyword("edge"):
+			parser.position++
+			var attributes, err = parser.parseAttributes(nil)
+			if err != nil {
+				return nil, err
+			}
+			if token.keyword("node") {
+				scope.nodeDefaults = mergeValues(scope.nodeDefaults, attributes)
+			} else if token.keyword("edge") {
+				scope.edgeDefaults = mergeValues(scope.edgeDefaults, attributes)
+			} else if scope.top {
+				parser.attributes = mergeValues(parser.attributes, attributes)
+			}
+		case token.kind == "ID" && parser.position+1 < len(parser.tokens) && parser.tokens[parser.position+1].kind == "=":
+			var key, _ = parser.parseID()
+			parser.position++
+			var value, err = parser.parseID()
+			if err != nil {
+				return nil, err
+			}
+			if scope.top {
+				parser.attributes = mergeValues(parser.attributes, map[string]string{key: value})
+			}
+		default:
+			var operand, single, err = parser.parseOperand(scope)
+			if err != nil {
+				return nil, err
+			}
+			include(operand)
+			if !parser.at("->") && !parser.at("--") {
+				if single && parser.at("[") {
+					var attributes, err = parser.parseAttributes(nil)
+					if err != nil {
+						return nil, err
+					}
+					if len(attributes) > 0 {
+						parser.values[operand[0]] = mergeValues(parser.values[operand[0]], attributes)
+					}
+				}
+				continue
+			}
+			if err := parser.parseEdges(scope, operand, include); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// parseEdges consumes the rest of an edge statement after its first operand, adding an edge
+// from every node of each operand to every node of the next
+func (parser *dotParser) parseEdges(scope dotScope, first []int, include func([]int)) error {
+	var connector = "--"
+	if parser.directed {
+		connector = "->"
+	}
+	var operands = [][]int{first}
+	for parser.at("->") || parser.at("--") {
+		var token, _ = parser.peek()
+		if token.kind != connector {
+			return fmt.Errorf("DOT syntax error on line %d: expected %q between nodes, but found %q", token.line, connector, token.kind)
+		}
+		parser.position++
+		var operand, _, err = parser.parseOperand(scope)
+		if err != nil {
+			return err
+		}
+		include(operand)
+		operands = append(operands, operand)
+	}
+	var attributes, err = parser.parseAttributes(scope.edgeDefaults)
+	if err != nil {
+		return err
+	}
+	for k := 0; k+1 < len(operands); k++ {
+		for _, from := range operands[k] {
+			for _, to := range operands[k+1] {
+				parser.edges = append(parser.edges, dotEdge{from: from, to: to, values: attributes})
+			}
+		}
+	}
+	return nil
+}
+
+// mergeValues returns a copy of a set of values with other values added over them
+func mergeValues(values map[string]string, added map[string]string) map[string]string {
+	var merged = make(map[string]string, len(values)+len(added))
+	for key, value := range values {
+		merged[key] = value
+	}
+	for key, value := range added {
+		merged[key] = value
+	}
+	return merged
+}
+
+// parseDOT reads a whole DOT graph
+func parseDOT(reader io.Reader, options DOTOptions) (*dotParser, error) {
+	var source, err = ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeDOT(string(source))
+	if err != nil {
+		return nil, err
+	}
+	var parser = &dotParser{tokens: tokens, clusterKey: options.ClusterKey, index: map[string]int{}}
+	if token, ok := parser.peek(); ok && token.keyword("strict") {
+		parser.strict = true
+		parser.position++
+	}
+	var token, ok = parser.peek()
+	if !ok || !(token.keyword("graph") || token.keyword("digraph")) {
+		return nil, errors.New("DOT syntax error: expected the graph to start with \"graph\" or \"digraph\"")
+	}
+	parser.directed = token.keyword("digraph")
+	parser.position++
+	if parser.at("ID") {
+		if _, err := parser.parseID(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := parser.expect("{"); err != nil {
+		return nil, err
+	}
+	if _, err := parser.parseStatements(dotScope{top: true}); err != nil {
+		return nil, err
+	}
+	if token, ok := parser.peek(); ok {
+		return nil, fmt.Errorf("DOT syntax error on line %d: unexpected input after the graph", token.line)
+	}
+	if options.LabelKey != "" && options.LabelKey != "label" {
+		for _, edge := range parser.edges {
+			if label, ok := edge.values["label"]; ok && label == edge.values[options.LabelKey] {
+				delete(edge.values, "label")
+			}
+		}
+	}
+	return parser, nil
+}
+
+// ReadDOT builds a directed graph from a digraph in the Graphviz DOT language, such as one
+// written by WriteDOT. Nodes are created in the order they first appear, with their attributes,
+// including those set by node defaults, as Values; edges carry their attributes as edge values,
+// so that a weight attribute sets the edge's weight. An edge between subgraphs joins every node
+// of one to every node of the other. The node named by the graph's root attribute becomes the
+// root, or else the first node. Nodes in a subgraph whose name starts with "cluster" get its
+// name, without that prefix, as their value under options.ClusterKey unless they already have
+// one. An edge label equal to the edge's value under options.LabelKey is dropped, as it was
+// written from that value. Parallel edges share their values, as always, and are merged in a
+// strict graph. Ports are ignored. An error is returned if the source is not valid DOT or is an undirected graph.
+func ReadDOT(reader io.Reader, options DOTOptions) (DirectedGraph, error) {
+	var parser, err = parseDOT(reader, options)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	if !parser.directed {
+		return DirectedGraph{}, errors.New("DOT graph is undirected; read it with ReadUndirectedDOT")
+	}
+	var graph = createDirectedNodes(parser.IDs, MatrixGraphOptions{Values: parser.values})
+	if root, ok := parser.index[parser.attributes["root"]]; ok {
+		graph.RootDirectedNode = graph.DirectedNodes[root]
+	}
+	var seen = map[[2]int]bool{}
+	for _, edge := range parser.edges {
+		if parser.strict && seen[[2]int{edge.from, edge.to}] {
+			continue
+		}
+		seen[[2]int{edge.from, edge.to}] = true
+		var parent, child = graph.DirectedNodes[edge.from], graph.DirectedNodes[edge.to]
+		graph, _, _ = CreateDirectedEdge(graph, parent, child)
+		for key, value := range edge.values {
+			SetDirectedEdgeValue(parent, child, key, value)
+		}
+	}
+	return graph, nil
+}
+
+// ReadUndirectedDOT builds an undirected graph from a graph in the Graphviz DOT language, such
+// as one written by WriteUndirectedDOT, like ReadDOT. The edges of a digraph become undirected
+// edges.
+func ReadUndirectedDOT(reader io.Reader, options DOTOptions) (Graph, error) {
+	var parser, err = parseDOT(reader, options)
+	if err != nil {
+		return Graph{}, err
+	}
+	var graph = createNodes(parser.IDs, MatrixGraphOptions{Values: parser.values})
+	var seen = map[[2]int]bool{}
+	for _, edge := range parser.edges {
+		if parser.strict && (seen[[2]int{edge.from, edge.to}] || seen[[2]int{edge.to, edge.from}]) {
+			continue
+		}
+		seen[[2]int{edge.from, edge.to}] = true
+		var a, b = graph.Nodes[edge.from], graph.Nodes[edge.to]
+		graph, _, _ = CreateEdge(graph, a, b)
+		for key, value := range edge.values {
+			SetEdgeValue(a, b, key, value)
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,160 @@
+package gograph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// createDOTTestGraph builds a directed graph a -> b -> c, a -> c with named and grouped nodes
+func createDOTTestGraph() DirectedGraph {
+	var graph = createDirectedNodes([]string{"a", "b", "c"}, MatrixGraphOptions{Values: []map[string]string{
+		{"group": "left", "color": "red"},
+		{"group": "left"},
+		nil,
+	}})
+	var a, b, c = graph.DirectedNodes[0], graph.DirectedNodes[1], graph.DirectedNodes[2]
+	graph, _, _ = CreateDirectedEdge(graph, a, b)
+	graph, _, _ = CreateDirectedEdge(graph, b, c)
+	graph, _, _ = CreateDirectedEdge(graph, a, c)
+	SetDirectedEdgeValue(a, b, WeightKey, "2.5")
+	return graph
+}
+
+func TestWriteDOT(t *testing.T) {
+	describe("WriteDOT", t)
+	var graph = createDOTTestGraph()
+
+	it("declares every node with its values, then every edge with its values", t)
+	var buffer bytes.Buffer
+	var err = WriteDOT(&buffer, graph, DOTOptions{Name: "example"})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `digraph example {
+	root=a;
+	a [color=red, group=left];
+	b [group=left];
+	c;
+	a -> b [weight="2.5"];
+	a -> c;
+	b -> c;
+}
+`, t)
+
+	it("gathers nodes into clusters and labels edges", t)
+	buffer.Reset()
+	WriteDOT(&buffer, graph, DOTOptions{ClusterKey: "group", LabelKey: WeightKey})
+	expectEqualBools(strings.Contains(buffer.String(), "\tsubgraph cluster_left {\n\t\tlabel=left;\n\t\ta;\n\t\tb;\n\t}\n"), true, t)
+	expectEqualBools(strings.Contains(buffer.String(), `a -> b [label="2.5", weight="2.5"];`), true, t)
+
+	it("quotes and escapes IDs that are not plain identifiers", t)
+	expectEqualStrings(quoteDOT("node"), `"node"`, t)
+	expectEqualStrings(quoteDOT(`say "hi"\`+"\n"), `"say \"hi\"\\\n"`, t)
+	expectEqualStrings(quoteDOT("-1.5"), `"-1.5"`, t)
+}
+
+func TestWriteUndirectedDOT(t *testing.T) {
+	describe("WriteUndirectedDOT", t)
+	var graph = createNodes([]string{"x", "y"}, MatrixGraphOptions{})
+	graph, _, _ = CreateEdge(graph, graph.Nodes[0], graph.Nodes[1])
+	graph, _, _ = CreateEdge(graph, graph.Nodes[1], graph.Nodes[1])
+
+	it("writes every edge once", t)
+	var buffer bytes.Buffer
+	WriteUndirectedDOT(&buffer, graph, DOTOptions{})
+	expectEqualStrings(buffer.String(), "graph {\n\tx;\n\ty;\n\tx -- y;\n\ty -- y;\n}\n", t)
+}
+
+func TestReadDOT(t *testing.T) {
+	describe("ReadDOT", t)
+
+	it("gives back a graph written by WriteDOT", t)
+	var graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 0.5}, {2, 0, 3}, {3, 4, 1}, {3, 4, 2}, {4, 4, 1}})
+	graph.DirectedNodes[2].Values["label"] = "line one\nsaid \"two\" \\ three"
+	graph.RootDirectedNode = graph.DirectedNodes[3]
+	var buffer bytes.Buffer
+	WriteDOT(&buffer, graph, DOTOptions{ClusterKey: "name"})
+	var read, err = ReadDOT(&buffer, DOTOptions{ClusterKey: "name"})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("gives back a graph whose edges were labeled by a value", t)
+	buffer.Reset()
+	WriteDOT(&buffer, graph, DOTOptions{LabelKey: WeightKey})
+	read, err = ReadDOT(&buffer, DOTOptions{LabelKey: WeightKey})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("reads the DOT language of hand-written graphs", t)
+	read, err = ReadDOT(strings.NewReader(`
+		/* A small pipeline */
+		strict digraph "pipeline" {
+			# preprocessor lines are skipped
+			rankdir=LR; // graph attributes are ignored
+			node [shape=box]
+			fetch [label="Fe" + "tch"]
+			edge [color=gray]
+			fetch:out -> { parse lint } -> build [weight=2]
+			fetch -> parse
+			subgraph cluster_deploy {
+				label=<<b>Deploy</b>>
+				ship; notify [urgent]
+			}
+			build -> ship -> notify
+			node [shape=circle]
+			42 -> ship
+		}`), DOTOptions{ClusterKey: "stage"})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), strings.Join([]string{
+		"root:fetch",
+		"fetch map[label:Fetch shape:box] -> [parse lint] map[lint:map[color:gray weight:2] parse:map[color:gray weight:2]]",
+		"parse map[shape:box] -> [build] map[build:map[color:gray weight:2]]",
+		"lint map[shape:box] -> [build] map[build:map[color:gray weight:2]]",
+		"build map[shape:box] -> [ship] map[ship:map[color:gray]]",
+		"ship map[shape:box stage:deploy] -> [notify] map[notify:map[color:gray]]",
+		"notify map[shape:box stage:deploy urgent:true] -> [] map[]",
+		"42 map[shape:circle] -> [ship] map[ship:map[color:gray]]",
+	}, "\n"), t)
+
+	context("the graph is undirected", t)
+
+	it("returns an error", t)
+	_, err = ReadDOT(strings.NewReader("graph { a -- b }"), DOTOptions{})
+	expectEqualBools(err == nil, false, t)
+
+	context("the source is not valid DOT", t)
+
+	it("returns an error naming the line", t)
+	_, err = ReadDOT(strings.NewReader("digraph {\n a -> b\n a -- c\n}"), DOTOptions{})
+	expectEqualStrings(err.Error(), `DOT syntax error on line 3: expected "->" between nodes, but found "--"`, t)
+	_, err = ReadDOT(strings.NewReader("digraph { a -> b"), DOTOptions{})
+	expectEqualBools(err == nil, false, t)
+	_, err = ReadDOT(strings.NewReader("digraph { a [label=\"open] }"), DOTOptions{})
+	expectEqualBools(err == nil, false, t)
+	_, err = ReadDOT(strings.NewReader("digraph { } extra"), DOTOptions{})
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestReadUndirectedDOT(t *testing.T) {
+	describe("ReadUndirectedDOT", t)
+
+	it("gives back a graph written by WriteUndirectedDOT", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}, {0, 1, 4}})
+	var buffer bytes.Buffer
+	WriteUndirectedDOT(&buffer, graph, DOTOptions{})
+	var read, err = ReadUndirectedDOT(&buffer, DOTOptions{})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(read), describeGraph(graph), t)
+
+	context("the graph is strict", t)
+
+	it("merges parallel edges in either direction", t)
+	read, _ = ReadUndirectedDOT(strings.NewReader("strict graph { a -- b; b -- a; a -- b }"), DOTOptions{})
+	expectEqualInts(len(read.Nodes[0].Edges), 1, t)
+
+	context("the graph is directed", t)
+
+	it("makes its edges undirected", t)
+	read, err = ReadUndirectedDOT(strings.NewReader("digraph { a -> b -> c }"), DOTOptions{})
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(len(read.Nodes[1].Edges), 2, t)
+}
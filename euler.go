@@ -0,0 +1,391 @@
+package gograph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// eulerEdge is an edge of the multigraph walked by Hierholzer's algorithm
+type eulerEdge struct {
+	from int
+	to   int
+}
+
+// hierholzer walks every edge exactly once starting from a given node, following each edge
+// only from its from end if directed. It returns the indices of the nodes visited, or nil if
+// some edge could not be reached from the start.
+func hierholzer(n int, edges []eulerEdge, directed bool, start int) []int {
+	var incident = make([][]int, n)
+	for index, edge := range edges {
+		incident[edge.from] = append(incident[edge.from], index)
+		if !directed && edge.from != edge.to {
+			incident[edge.to] = append(incident[edge.to], index)
+		}
+	}
+	var used = make([]bool, len(edges))
+	var next = make([]int, n) // position of the next unexamined incident edge of every node
+
+	var trail = make([]int, 0, len(edges)+1)
+	var stack = []int{start}
+	for len(stack) > 0 {
+		var v = stack[len(stack)-1]
+		for next[v] < len(incident[v]) && used[incident[v][next[v]]] {
+			next[v]++
+		}
+		if next[v] == len(incident[v]) {
+			trail = append(trail, v)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		var index = incident[v][next[v]]
+		used[index] = true
+		var w = edges[index].to
+		if w == v {
+			w = edges[index].from
+		}
+		stack = append(stack, w)
+	}
+	if len(trail) != len(edges)+1 {
+		return nil
+	}
+	for i, j := 0, len(trail)-1; i < j; i, j = i+1, j-1 {
+		trail[i], trail[j] = trail[j], trail[i]
+	}
+	return trail
+}
+
+// joinIDs lists node IDs for an error message
+func joinIDs(IDs []string) string {
+	var quoted = make([]string, len(IDs))
+	for index, ID := range IDs {
+		quoted[index] = fmt.Sprintf("%q", ID)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// eulerianTrail checks the degree conditions for an Eulerian trail, then walks it from the
+// appropriate node. The imbalance of a node is its out-degree less its in-degree when
+// directed, or its degree modulo two otherwise.
+func eulerianTrail(IDs []string, edges []eulerEdge, directed bool, circuit bool) ([]int, error) {
+	var n = len(IDs)
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	var imbalance = make([]int, n)
+	for _, edge := range edges {
+		if directed {
+			imbalance[edge.from]++
+			imbalance[edge.to]--
+		} else if edge.from != edge.to {
+			imbalance[edge.from] ^= 1
+			imbalance[edge.to] ^= 1
+		}
+	}
+
+	var start = edges[0].from
+	var unbalanced []string
+	var starts, ends []string
+	for v := 0; v < n; v++ {
+		switch {
+		case imbalance[v] == 0:
+		case !directed:
+			unbalanced = append(unbalanced, IDs[v])
+			if len(unbalanced) == 1 {
+				start = v
+			}
+		case imbalance[v] == 1:
+			starts = append(starts, IDs[v])
+			start = v
+		case imbalance[v] == -1:
+			ends = append(ends, IDs[v])
+		case imbalance[v] > 0:
+			return nil, fmt.Errorf("node %q has %d more outgoing than incoming edges", IDs[v], imbalance[v])
+		default:
+			return nil, fmt.Errorf("node %q has %d more incoming than outgoing edges", IDs[v], -imbalance[v])
+		}
+	}
+	if directed {
+		if circuit && len(starts)+len(ends) > 0 {
+			return nil, fmt.Errorf("nodes with more outgoing than incoming edges: %s; with more incoming than outgoing edges: %s",
+				joinIDs(starts), joinIDs(ends))
+		}
+		if len(starts) > 1 || len(ends) > 1 {
+			return nil, fmt.Errorf("a trail has one start and one end, but nodes %s have an extra outgoing edge and nodes %s an extra incoming edge",
+				joinIDs(starts), joinIDs(ends))
+		}
+	} else {
+		if circuit && len(unbalanced) > 0 {
+			return nil, fmt.Errorf("nodes %s have odd degree", joinIDs(unbalanced))
+		}
+		if len(unbalanced) > 2 {
+			return nil, fmt.Errorf("a trail has two ends, but %d nodes have odd degree: %s", len(unbalanced), joinIDs(unbalanced))
+		}
+	}
+
+	var trail = hierholzer(n, edges, directed, start)
+	if trail == nil {
+		return nil, errors.New("the edges of the graph are not all connected to each other")
+	}
+	return trail, nil
+}
+
+// undirectedEulerEdges lists every edge of an undirected graph once, including self-loops and
+// parallel edges
+func undirectedEulerEdges(graph Graph) []eulerEdge {
+	var indices = indexNodes(graph)
+	var edges []eulerEdge
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i <= j {
+				edges = append(edges, eulerEdge{from: i, to: j})
+			}
+		}
+	}
+	return edges
+}
+
+// directedEulerEdges lists every edge of a directed graph from parent to child
+func directedEulerEdges(graph DirectedGraph) []eulerEdge {
+	var edges []eulerEdge
+	for i, list := range childLists(graph) {
+		for _, j := range list {
+			edges = append(edges, eulerEdge{from: i, to: j})
+		}
+	}
+	return edges
+}
+
+// indexedNodes returns the nodes of a graph at the given indices, in order
+func indexedNodes(graph Graph, positions []int) []*Node {
+	var nodes = make([]*Node, len(positions))
+	for index, i := range positions {
+		nodes[index] = graph.Nodes[i]
+	}
+	return nodes
+}
+
+// indexedDirectedNodes returns the nodes of a directed graph at the given indices, in order
+func indexedDirectedNodes(graph DirectedGraph, positions []int) []*DirectedNode {
+	var nodes = make([]*DirectedNode, len(positions))
+	for index, i := range positions {
+		nodes[index] = graph.DirectedNodes[i]
+	}
+	return nodes
+}
+
+// EulerianPath implements Hierholzer's algorithm to find a trail through an undirected graph
+// that uses every edge exactly once, listed as the sequence of nodes visited. The trail is a
+// circuit, ending where it starts, whenever the graph has one. A graph without edges has an
+// empty trail. If there is no such trail, the error explains why: more than two nodes of odd
+// degree, or edges that are not all connected.
+func EulerianPath(graph Graph) ([]*Node, error) {
+	var trail, err = eulerianTrail(nodeIDs(graph), undirectedEulerEdges(graph), false, false)
+	if err != nil {
+		return nil, err
+	}
+	return indexedNodes(graph, trail), nil
+}
+
+// EulerianCircuit finds a closed trail through an undirected graph that uses every edge exactly
+// once, like EulerianPath, and returns an error naming the nodes of odd degree if there is none.
+func EulerianCircuit(graph Graph) ([]*Node, error) {
+	var trail, err = eulerianTrail(nodeIDs(graph), undirectedEulerEdges(graph), false, true)
+	if err != nil {
+		return nil, err
+	}
+	return indexedNodes(graph, trail), nil
+}
+
+// DirectedEulerianPath implements Hierholzer's algorithm to find a trail through a directed
+// graph that follows every edge from parent to child exactly once. The trail is a circuit
+// whenever the graph has one. If there is no such trail, the error names the nodes whose
+// incoming and outgoing edges are out of balance, or reports edges that are not all connected.
+func DirectedEulerianPath(graph DirectedGraph) ([]*DirectedNode, error) {
+	var trail, err = eulerianTrail(directedNodeIDs(graph), directedEulerEdges(graph), true, false)
+	if err != nil {
+		return nil, err
+	}
+	return indexedDirectedNodes(graph, trail), nil
+}
+
+// DirectedEulerianCircuit finds a closed trail through a directed graph that follows every edge
+// exactly once, like DirectedEulerianPath, and returns an error naming the unbalanced nodes if
+// there is none.
+func DirectedEulerianCircuit(graph DirectedGraph) ([]*DirectedNode, error) {
+	var trail, err = eulerianTrail(directedNodeIDs(graph), directedEulerEdges(graph), true, true)
+	if err != nil {
+		return nil, err
+	}
+	return indexedDirectedNodes(graph, trail), nil
+}
+
+// hamiltonian searches for a path through every node by backtracking, extending the path only
+// along the successors of its last node. Searches of at most 64 nodes remember which sets of
+// visited nodes and last nodes cannot be completed, so no such state is explored twice. With
+// cycle set, the path starts at the first node and must have an edge back to it.
+func hamiltonian(successors [][]int, cycle bool) []int {
+	var n = len(successors)
+	if n == 0 {
+		return nil
+	}
+	var adjacent = make([]map[int]bool, n)
+	for v, list := range successors {
+		adjacent[v] = make(map[int]bool, len(list))
+		for _, w := range list {
+			adjacent[v][w] = true
+		}
+	}
+
+	type state struct {
+		visited uint64
+		last    int
+	}
+	var memoize = n <= 64
+	var failed = map[state]bool{}
+	var visited = make([]bool, n)
+	var mask uint64
+	var path = make([]int, 0, n)
+
+	var extend func(v int) bool
+	extend = func(v int) bool {
+		visited[v] = true
+		path = append(path, v)
+		if memoize {
+			mask |= 1 << uint(v)
+		}
+		if len(path) == n && (!cycle || adjacent[v][path[0]]) {
+			return true
+		}
+		if len(path) < n && !(memoize && failed[state{mask, v}]) {
+			for _, w := range successors[v] {
+				if !visited[w] && extend(w) {
+					return true
+				}
+			}
+			if memoize {
+				failed[state{mask, v}] = true
+			}
+		}
+		visited[v] = false
+		path = path[:len(path)-1]
+		if memoize {
+			mask &^= 1 << uint(v)
+		}
+		return false
+	}
+
+	if cycle {
+		if extend(0) {
+			return path
+		}
+		return nil
+	}
+	for start := 0; start < n; start++ {
+		if extend(start) {
+			return path
+		}
+	}
+	return nil
+}
+
+// HamiltonianPath searches an undirected graph for a path that visits every node exactly once,
+// returning the nodes in the order visited. The search backtracks and its running time grows
+// exponentially, so it is only suited to small graphs.
+func HamiltonianPath(graph Graph) ([]*Node, bool) {
+	var path = hamiltonian(adjacencyLists(graph), false)
+	if path == nil {
+		return nil, false
+	}
+	return indexedNodes(graph, path), true
+}
+
+// HamiltonianCycle searches an undirected graph for a cycle that visits every node exactly
+// once, returning the nodes in the order visited starting from the first node of the graph.
+// Graphs of fewer than three nodes have no Hamiltonian cycle. Like HamiltonianPath, it is only
+// suited to small graphs.
+func HamiltonianCycle(graph Graph) ([]*Node, bool) {
+	if len(graph.Nodes) < 3 {
+		return nil, false
+	}
+	var cycle = hamiltonian(adjacencyLists(graph), true)
+	if cycle == nil {
+		return nil, false
+	}
+	return indexedNodes(graph, cycle), true
+}
+
+// DirectedHamiltonianPath searches a directed graph for a path from parent to child that
+// visits every node exactly once. It is only suited to small graphs; for directed acyclic
+// graphs, DAGHamiltonianPath answers in linear time.
+func DirectedHamiltonianPath(graph DirectedGraph) ([]*DirectedNode, bool) {
+	var path = hamiltonian(childLists(graph), false)
+	if path == nil {
+		return nil, false
+	}
+	return indexedDirectedNodes(graph, path), true
+}
+
+// DirectedHamiltonianCycle searches a directed graph for a cycle that follows edges from parent
+// to child and visits every node exactly once, starting from the first node of the graph. It
+// is only suited to small graphs.
+func DirectedHamiltonianCycle(graph DirectedGraph) ([]*DirectedNode, bool) {
+	var cycle = hamiltonian(childLists(graph), true)
+	if cycle == nil {
+		return nil, false
+	}
+	return indexedDirectedNodes(graph, cycle), true
+}
+
+// topologicalOrder sorts a directed graph, given by the children of every node, with Kahn's
+// algorithm so that every parent comes before its children, and reports whether the graph is
+// acyclic, in which case the order lists every node
+func topologicalOrder(children [][]int) ([]int, bool) {
+	var inDegree = make([]int, len(children))
+	for _, list := range children {
+		for _, j := range list {
+			inDegree[j]++
+		}
+	}
+	var order = make([]int, 0, len(children))
+	for v, degree := range inDegree {
+		if degree == 0 {
+			order = append(order, v)
+		}
+	}
+	for next := 0; next < len(order); next++ {
+		for _, w := range children[order[next]] {
+			inDegree[w]--
+			if inDegree[w] == 0 {
+				order = append(order, w)
+			}
+		}
+	}
+	return order, len(order) == len(children)
+}
+
+// DAGHamiltonianPath decides in linear time whether a directed acyclic graph has a path from
+// parent to child through every node. Such a path exists exactly when the graph has a single
+// topological ordering, so it is found by sorting the graph topologically and checking that
+// consecutive nodes are joined by edges. An error is returned if the graph has a cycle.
+func DAGHamiltonianPath(graph DirectedGraph) ([]*DirectedNode, bool, error) {
+	var children = childLists(graph)
+	var order, acyclic = topologicalOrder(children)
+	if !acyclic {
+		return nil, false, errors.New("graph has a cycle")
+	}
+	if len(order) == 0 {
+		return nil, false, nil
+	}
+
+	for index := 1; index < len(order); index++ {
+		var joined = false
+		for _, child := range children[order[index-1]] {
+			joined = joined || child == order[index]
+		}
+		if !joined {
+			return nil, false, nil
+		}
+	}
+	return indexedDirectedNodes(graph, order), true, nil
+}
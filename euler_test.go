@@ -0,0 +1,255 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// expectEulerianTrail checks that a trail of node IDs uses every edge of a list exactly once
+func expectEulerianTrail(trail []string, edges [][2]string, directed bool, t *testing.T) {
+	expectEqualInts(len(trail), len(edges)+1, t)
+	var remaining = map[[2]string]int{}
+	for _, edge := range edges {
+		remaining[edge]++
+	}
+	for index := 1; index < len(trail); index++ {
+		var edge = [2]string{trail[index-1], trail[index]}
+		if remaining[edge] == 0 && !directed {
+			edge = [2]string{trail[index], trail[index-1]}
+		}
+		if remaining[edge] == 0 {
+			t.Errorf("Failed: trail follows a missing or used edge from %s to %s", trail[index-1], trail[index])
+			return
+		}
+		remaining[edge]--
+	}
+}
+
+func TestEulerianPath(t *testing.T) {
+	describe("EulerianPath", t)
+	// A square with a diagonal and a roof, leaving nodes 1 and 2 of odd degree
+	var pairs = [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {2, 4}, {3, 4}, {1, 3}}
+	var triples = make([][3]float64, len(pairs))
+	for index, pair := range pairs {
+		triples[index] = [3]float64{float64(pair[0]), float64(pair[1]), 1}
+	}
+	var graph = createWeightedGraph(5, triples)
+	var edges = make([][2]string, len(pairs))
+	for index, pair := range pairs {
+		edges[index] = [2]string{graph.Nodes[pair[0]].ID, graph.Nodes[pair[1]].ID}
+	}
+	var trail, err = EulerianPath(graph)
+
+	it("walks every edge once between the nodes of odd degree", t)
+	expectEqualBools(err == nil, true, t)
+	var IDs = make([]string, len(trail))
+	for index, node := range trail {
+		IDs[index] = node.ID
+	}
+	expectEulerianTrail(IDs, edges, false, t)
+	var ends = map[*Node]bool{trail[0]: true, trail[len(trail)-1]: true}
+	expectEqualBools(ends[graph.Nodes[1]] && ends[graph.Nodes[2]], true, t)
+
+	context("more than two nodes have odd degree", t)
+	graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}})
+	_, err = EulerianPath(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+
+	context("the edges are not connected", t)
+	graph = createWeightedGraph(6, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {3, 4, 1}, {4, 5, 1}, {5, 3, 1}})
+	_, err = EulerianPath(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestEulerianCircuit(t *testing.T) {
+	describe("EulerianCircuit", t)
+	// Two triangles sharing a node, with a self-loop
+	var graph = createWeightedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {0, 3, 1}, {3, 4, 1}, {4, 0, 1}, {4, 4, 1}})
+	var circuit, err = EulerianCircuit(graph)
+
+	it("returns to the node it starts from after walking every edge", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(len(circuit), 8, t)
+	expectEqualStrings(circuit[0].ID, circuit[len(circuit)-1].ID, t)
+
+	context("the graph has nodes of odd degree", t)
+	graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	_, err = EulerianCircuit(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestDirectedEulerianPath(t *testing.T) {
+	describe("DirectedEulerianPath", t)
+	var pairs = [][2]int{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {3, 4}, {4, 0}, {1, 3}}
+	var triples = make([][3]float64, len(pairs))
+	for index, pair := range pairs {
+		triples[index] = [3]float64{float64(pair[0]), float64(pair[1]), 1}
+	}
+	var graph = createWeightedDirectedGraph(5, triples)
+	var edges = make([][2]string, len(pairs))
+	for index, pair := range pairs {
+		edges[index] = [2]string{graph.DirectedNodes[pair[0]].ID, graph.DirectedNodes[pair[1]].ID}
+	}
+	var trail, err = DirectedEulerianPath(graph)
+
+	it("follows every edge once from the node with an extra outgoing edge", t)
+	expectEqualBools(err == nil, true, t)
+	var IDs = make([]string, len(trail))
+	for index, node := range trail {
+		IDs[index] = node.ID
+	}
+	expectEulerianTrail(IDs, edges, true, t)
+	expectEqualStrings(trail[0].ID, graph.DirectedNodes[1].ID, t)
+	expectEqualStrings(trail[len(trail)-1].ID, graph.DirectedNodes[3].ID, t)
+
+	context("two nodes have extra outgoing edges", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 2, 1}, {1, 2, 1}})
+	_, err = DirectedEulerianPath(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestDirectedEulerianCircuit(t *testing.T) {
+	describe("DirectedEulerianCircuit", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {0, 2, 1}, {2, 0, 1}})
+	var circuit, err = DirectedEulerianCircuit(graph)
+
+	it("returns to the node it starts from after following every edge", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(len(circuit), 6, t)
+	expectEqualStrings(circuit[0].ID, circuit[len(circuit)-1].ID, t)
+
+	context("the graph is a chain", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	_, err = DirectedEulerianCircuit(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	describe("HamiltonianPath", t)
+	// A star with one extended arm has no Hamiltonian path
+	var graph = createWeightedGraph(5, [][3]float64{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {3, 4, 1}})
+	var _, ok = HamiltonianPath(graph)
+
+	it("reports when there is none", t)
+	expectEqualBools(ok, false, t)
+
+	context("an edge joins two arms of the star", t)
+	graph, _, _ = CreateEdge(graph, graph.Nodes[1], graph.Nodes[2])
+	var path []*Node
+	path, ok = HamiltonianPath(graph)
+
+	it("visits every node once along edges", t)
+	expectEqualBools(ok, true, t)
+	expectEqualInts(len(path), 5, t)
+	var visited = map[*Node]bool{}
+	for index, node := range path {
+		visited[node] = true
+		if index > 0 {
+			expectEqualBools(neighborSets(graph)[FindNode(graph, path[index-1].ID)][FindNode(graph, node.ID)], true, t)
+		}
+	}
+	expectEqualInts(len(visited), 5, t)
+}
+
+func TestHamiltonianCycle(t *testing.T) {
+	describe("HamiltonianCycle", t)
+	var graph = createCrownGraph(4)
+	var cycle, ok = HamiltonianCycle(graph)
+
+	it("visits every node once and returns to the first", t)
+	expectEqualBools(ok, true, t)
+	expectEqualInts(len(cycle), 8, t)
+	expectEqualStrings(cycle[0].ID, graph.Nodes[0].ID, t)
+	var neighbors = neighborSets(graph)
+	for index := range cycle {
+		var a, b = FindNode(graph, cycle[index].ID), FindNode(graph, cycle[(index+1)%len(cycle)].ID)
+		expectEqualBools(neighbors[a][b], true, t)
+	}
+
+	context("the graph is a tree", t)
+	graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}})
+	_, ok = HamiltonianCycle(graph)
+
+	it("reports that there is none", t)
+	expectEqualBools(ok, false, t)
+}
+
+func TestDirectedHamiltonianPath(t *testing.T) {
+	describe("DirectedHamiltonianPath", t)
+	var graph = createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {2, 0, 1}, {1, 3, 1}, {3, 2, 1}})
+	var path, ok = DirectedHamiltonianPath(graph)
+
+	it("follows edges from parent to child through every node", t)
+	expectEqualBools(ok, true, t)
+	expectEqualInts(len(path), 4, t)
+	for index := 1; index < len(path); index++ {
+		expectEqualBools(reaches(path[index-1], path[index]), true, t)
+	}
+
+	context("two nodes have no parents", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 2, 1}, {1, 2, 1}})
+	_, ok = DirectedHamiltonianPath(graph)
+
+	it("reports that there is none", t)
+	expectEqualBools(ok, false, t)
+}
+
+func TestDirectedHamiltonianCycle(t *testing.T) {
+	describe("DirectedHamiltonianCycle", t)
+	var graph = createWeightedDirectedGraph(4, [][3]float64{{0, 2, 1}, {2, 1, 1}, {1, 3, 1}, {3, 0, 1}, {0, 1, 1}})
+	var cycle, ok = DirectedHamiltonianCycle(graph)
+
+	it("follows edges around every node", t)
+	expectEqualBools(ok, true, t)
+	expectEqualInts(len(cycle), 4, t)
+	expectEqualStrings(cycle[1].ID, graph.DirectedNodes[2].ID, t)
+	expectEqualStrings(cycle[3].ID, graph.DirectedNodes[3].ID, t)
+
+	context("an edge of the cycle is reversed", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {0, 2, 1}})
+	_, ok = DirectedHamiltonianCycle(graph)
+
+	it("reports that there is none", t)
+	expectEqualBools(ok, false, t)
+}
+
+func TestDAGHamiltonianPath(t *testing.T) {
+	describe("DAGHamiltonianPath", t)
+	var graph = createWeightedDirectedGraph(4, [][3]float64{{0, 2, 1}, {2, 1, 1}, {1, 3, 1}, {0, 3, 1}})
+	var path, ok, err = DAGHamiltonianPath(graph)
+
+	it("returns the only topological ordering", t)
+	expectEqualBools(err == nil && ok, true, t)
+	expectEqualInts(len(path), 4, t)
+	expectEqualStrings(path[0].ID, graph.DirectedNodes[0].ID, t)
+	expectEqualStrings(path[1].ID, graph.DirectedNodes[2].ID, t)
+	expectEqualStrings(path[2].ID, graph.DirectedNodes[1].ID, t)
+	expectEqualStrings(path[3].ID, graph.DirectedNodes[3].ID, t)
+
+	it("leaves the graph intact", t)
+	expectEqualInts(len(graph.DirectedNodes[0].Children), 2, t)
+	expectEqualInts(len(graph.DirectedNodes[3].Parents), 2, t)
+
+	context("the graph has more than one topological ordering", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}})
+	_, ok, err = DAGHamiltonianPath(graph)
+
+	it("reports that there is no Hamiltonian path", t)
+	expectEqualBools(err == nil && !ok, true, t)
+
+	context("the graph has a cycle", t)
+	graph = createWeightedDirectedGraph(2, [][3]float64{{0, 1, 1}, {1, 0, 1}})
+	_, _, err = DAGHamiltonianPath(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
@@ -34,17 +34,34 @@ func main() {
 		}
 	}
 
+	var IDs = gograph.DirectedNodeIDs(linkedList)
 	var adjMatrix = gograph.CreateAdjecencyMatrix(linkedList)
 	fmt.Println("Adjacency Matrix:")
-	gograph.PrintMatrix(adjMatrix)
+	var table, _ = adjMatrix.Format(IDs, nil)
+	fmt.Print(table)
 
 	var incMatrix = gograph.CreateIncidenceMatrix(linkedList)
 	fmt.Println("Incidence Matrix:")
 	gograph.PrintMatrix(incMatrix)
 
 	fmt.Println("The adjacency matrix is not necessarily asymmetric.")
-	fmt.Printf("%+v\n", gograph.IsAntisymmetricMatrix(adjMatrix))
+	var antisymmetric, _ = adjMatrix.IsAntisymmetric()
+	fmt.Printf("%+v\n", antisymmetric)
 
-	fmt.Println("The incidence matrix is not necessarily asymmetric.")
-	fmt.Printf("%+v\n", gograph.IsAntisymmetricMatrix(incMatrix))
+	var walks, _ = adjMatrix.Power(2)
+	var count = 0
+	for _, row := range walks {
+		for _, entry := range row {
+			count += entry
+		}
+	}
+	fmt.Printf("The directed graph has %d walks of two edges.\n", count)
+
+	var laplacian = gograph.CreateUndirectedLaplacianMatrix(gograph.CreateUndirectedGraph(linkedList))
+	fmt.Println("Laplacian Matrix of the undirected graph:")
+	gograph.PrintMatrix(laplacian)
+
+	fmt.Println("The Laplacian matrix of an undirected graph is symmetric.")
+	var symmetric, _ = laplacian.IsSymmetric()
+	fmt.Printf("%+v\n", symmetric)
 }
@@ -0,0 +1,387 @@
+package gograph
+
+import (
+	"errors"
+	"math"
+)
+
+// CapacityKey is the edge value key under which edge capacities are stored
+const CapacityKey = "capacity"
+
+// flowEpsilon is the residual capacity below which an arc is considered saturated
+const flowEpsilon = 1e-9
+
+// FlowResult describes a maximum flow from a source to a sink node, along with the
+// minimum cut separating them.
+type FlowResult struct {
+	Value      float64
+	Flow       map[Edge]float64 // flow along every edge of the graph
+	SourceSide []*DirectedNode  // nodes reachable from the source in the residual network
+	SinkSide   []*DirectedNode
+	CutEdges   []Edge // saturated edges from the source side to the sink side
+}
+
+// DirectedEdgeCapacity returns the capacity of the directed edge from a parent to a child
+// node. Edges without a capacity are treated as having a capacity of 1.
+func DirectedEdgeCapacity(parent *DirectedNode, child *DirectedNode) float64 {
+	return edgeFloat(parent.EdgeValues, child.ID, CapacityKey, 1)
+}
+
+// flowArc is an arc of a residual network. Every edge of the graph is paired with a
+// reverse arc of zero capacity.
+type flowArc struct {
+	to       int
+	capacity float64
+	cost     float64
+	flow     float64
+	reverse  int  // index of the paired arc in the arcs of the node this arc points to
+	original bool // whether the arc is an edge of the graph rather than a reverse arc
+}
+
+func (arc *flowArc) residual() float64 {
+	return arc.capacity - arc.flow
+}
+
+// flowNetwork is the residual network of a directed graph between a source and a sink
+type flowNetwork struct {
+	graph  DirectedGraph
+	arcs   [][]flowArc
+	source int
+	sink   int
+}
+
+func newFlowNetwork(graph DirectedGraph, source *DirectedNode, sink *DirectedNode) (*flowNetwork, error) {
+	var indices = indexDirectedNodes(graph)
+	var sourceIndex, sourceOk = indices[source]
+	var sinkIndex, sinkOk = indices[sink]
+	if !sourceOk || !sinkOk {
+		return nil, errors.New("source and sink nodes must be in the graph")
+	}
+	if sourceIndex == sinkIndex {
+		return nil, errors.New("source and sink nodes must differ")
+	}
+
+	var network = &flowNetwork{
+		graph:  graph,
+		arcs:   make([][]flowArc, len(graph.DirectedNodes)),
+		source: sourceIndex,
+		sink:   sinkIndex,
+	}
+	for i, parent := range graph.DirectedNodes {
+		for _, child := range parent.Children {
+			if j, ok := indices[child]; ok && i != j {
+				network.addArc(i, j, DirectedEdgeCapacity(parent, child), DirectedEdgeCost(parent, child))
+			}
+		}
+	}
+	return network, nil
+}
+
+func (network *flowNetwork) addArc(from int, to int, capacity float64, cost float64) {
+	network.arcs[from] = append(network.arcs[from], flowArc{
+		to: to, capacity: capacity, cost: cost, reverse: len(network.arcs[to]), original: true,
+	})
+	network.arcs[to] = append(network.arcs[to], flowArc{
+		to: from, capacity: 0, cost: -cost, reverse: len(network.arcs[from]) - 1,
+	})
+}
+
+// push sends flow along the index-th arc leaving a node
+func (network *flowNetwork) push(from int, index int, amount float64) {
+	var arc = &network.arcs[from][index]
+	arc.flow += amount
+	network.arcs[arc.to][arc.reverse].flow -= amount
+}
+
+// result reads the flow and the minimum cut out of the residual network
+func (network *flowNetwork) result() FlowResult {
+	var nodes = network.graph.DirectedNodes
+	var result = FlowResult{Flow: map[Edge]float64{}}
+
+	for _, arc := range network.arcs[network.source] {
+		result.Value += arc.flow
+	}
+
+	var reachable = make([]bool, len(nodes))
+	var queue = []int{network.source}
+	reachable[network.source] = true
+	for len(queue) > 0 {
+		var u = queue[0]
+		queue = queue[1:]
+		for _, arc := range network.arcs[u] {
+			if !reachable[arc.to] && arc.residual() > flowEpsilon {
+				reachable[arc.to] = true
+				queue = append(queue, arc.to)
+			}
+		}
+	}
+
+	for u, arcs := range network.arcs {
+		if reachable[u] {
+			result.SourceSide = append(result.SourceSide, nodes[u])
+		} else {
+			result.SinkSide = append(result.SinkSide, nodes[u])
+		}
+		for _, arc := range arcs {
+			if !arc.original {
+				continue
+			}
+			var edge = Edge{From: nodes[u].ID, To: nodes[arc.to].ID}
+			result.Flow[edge] += arc.flow
+			if reachable[u] && !reachable[arc.to] {
+				result.CutEdges = append(result.CutEdges, edge)
+			}
+		}
+	}
+	return result
+}
+
+// EdmondsKarp computes the maximum flow from a source to a sink node by repeatedly
+// augmenting along shortest paths in the residual network. Edge capacities are read from
+// the CapacityKey edge value.
+func EdmondsKarp(graph DirectedGraph, source *DirectedNode, sink *DirectedNode) (FlowResult, error) {
+	var network, err = newFlowNetwork(graph, source, sink)
+	if err != nil {
+		return FlowResult{}, err
+	}
+
+	var n = len(network.arcs)
+	for {
+		// Record the arc used to reach every node on a breadth-first search from the source
+		var previousNode = make([]int, n)
+		var previousArc = make([]int, n)
+		for i := range previousNode {
+			previousNode[i] = -1
+		}
+		previousNode[network.source] = network.source
+		var queue = []int{network.source}
+		for len(queue) > 0 && previousNode[network.sink] == -1 {
+			var u = queue[0]
+			queue = queue[1:]
+			for index, arc := range network.arcs[u] {
+				if previousNode[arc.to] == -1 && arc.residual() > flowEpsilon {
+					previousNode[arc.to] = u
+					previousArc[arc.to] = index
+					queue = append(queue, arc.to)
+				}
+			}
+		}
+		if previousNode[network.sink] == -1 {
+			break
+		}
+
+		var bottleneck = math.Inf(1)
+		for v := network.sink; v != network.source; v = previousNode[v] {
+			bottleneck = math.Min(bottleneck, network.arcs[previousNode[v]][previousArc[v]].residual())
+		}
+		for v := network.sink; v != network.source; v = previousNode[v] {
+			network.push(previousNode[v], previousArc[v], bottleneck)
+		}
+	}
+	return network.result(), nil
+}
+
+// Dinic computes the maximum flow from a source to a sink node by saturating blocking
+// flows in the level graph of the residual network. Edge capacities are read from the
+// CapacityKey edge value.
+func Dinic(graph DirectedGraph, source *DirectedNode, sink *DirectedNode) (FlowResult, error) {
+	var network, err = newFlowNetwork(graph, source, sink)
+	if err != nil {
+		return FlowResult{}, err
+	}
+
+	var n = len(network.arcs)
+	var level = make([]int, n)
+	var next = make([]int, n)
+
+	var augment func(u int, limit float64) float64
+	augment = func(u int, limit float64) float64 {
+		if u == network.sink {
+			return limit
+		}
+		for ; next[u] < len(network.arcs[u]); next[u]++ {
+			var arc = network.arcs[u][next[u]]
+			if arc.residual() <= flowEpsilon || level[arc.to] != level[u]+1 {
+				continue
+			}
+			var pushed = augment(arc.to, math.Min(limit, arc.residual()))
+			if pushed > flowEpsilon {
+				network.push(u, next[u], pushed)
+				return pushed
+			}
+		}
+		return 0
+	}
+
+	for {
+		for i := range level {
+			level[i] = -1
+		}
+		level[network.source] = 0
+		var queue = []int{network.source}
+		for len(queue) > 0 {
+			var u = queue[0]
+			queue = queue[1:]
+			for _, arc := range network.arcs[u] {
+				if level[arc.to] == -1 && arc.residual() > flowEpsilon {
+					level[arc.to] = level[u] + 1
+					queue = append(queue, arc.to)
+				}
+			}
+		}
+		if level[network.sink] == -1 {
+			break
+		}
+
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			if augment(network.source, math.Inf(1)) <= flowEpsilon {
+				break
+			}
+		}
+	}
+	return network.result(), nil
+}
+
+// PushRelabel computes the maximum flow from a source to a sink node with the FIFO
+// push-relabel algorithm. Edge capacities are read from the CapacityKey edge value.
+func PushRelabel(graph DirectedGraph, source *DirectedNode, sink *DirectedNode) (FlowResult, error) {
+	var network, err = newFlowNetwork(graph, source, sink)
+	if err != nil {
+		return FlowResult{}, err
+	}
+
+	var n = len(network.arcs)
+	var height = make([]int, n)
+	var excess = make([]float64, n)
+	var current = make([]int, n)
+	var active []int
+
+	var push = func(u int, index int, amount float64) {
+		var to = network.arcs[u][index].to
+		network.push(u, index, amount)
+		excess[u] -= amount
+		if excess[to] <= flowEpsilon && to != network.source && to != network.sink {
+			active = append(active, to)
+		}
+		excess[to] += amount
+	}
+
+	height[network.source] = n
+	for index, arc := range network.arcs[network.source] {
+		if arc.residual() > flowEpsilon {
+			push(network.source, index, arc.residual())
+		}
+	}
+
+	for len(active) > 0 {
+		var u = active[0]
+		active = active[1:]
+		for excess[u] > flowEpsilon {
+			if current[u] == len(network.arcs[u]) {
+				// Relabel the node just above its lowest residual neighbor
+				var lowest = math.MaxInt32
+				for _, arc := range network.arcs[u] {
+					if arc.residual() > flowEpsilon && height[arc.to] < lowest {
+						lowest = height[arc.to]
+					}
+				}
+				height[u] = lowest + 1
+				current[u] = 0
+				continue
+			}
+			var arc = network.arcs[u][current[u]]
+			if arc.residual() > flowEpsilon && height[u] == height[arc.to]+1 {
+				push(u, current[u], math.Min(excess[u], arc.residual()))
+			} else {
+				current[u]++
+			}
+		}
+	}
+	return network.result(), nil
+}
+
+// StoerWagnerMinimumCut finds a global minimum cut of an undirected graph, returning its
+// weight and the two sides of the partition. Edge weights are read from the WeightKey edge
+// value. An error is returned if the graph has fewer than two nodes.
+func StoerWagnerMinimumCut(graph Graph) (float64, []*Node, []*Node, error) {
+	var n = len(graph.Nodes)
+	if n < 2 {
+		return 0, nil, nil, errors.New("graph must have at least two nodes to be cut")
+	}
+
+	var weights = make([][]float64, n)
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+	for _, edge := range undirectedEdges(graph) {
+		weights[edge.from][edge.to] += edge.weight
+		weights[edge.to][edge.from] += edge.weight
+	}
+
+	// Each remaining vertex stands for the group of original nodes merged into it
+	var groups = make([][]int, n)
+	var vertices = make([]int, n)
+	for i := range groups {
+		groups[i] = []int{i}
+		vertices[i] = i
+	}
+
+	var bestWeight = math.Inf(1)
+	var bestGroup []int
+	for len(vertices) > 1 {
+		// Order the vertices by maximum adjacency to those already added
+		var added = make([]bool, n)
+		var connectivity = make([]float64, n)
+		var previous, last = -1, -1
+		for range vertices {
+			var selected = -1
+			for _, v := range vertices {
+				if !added[v] && (selected == -1 || connectivity[v] > connectivity[selected]) {
+					selected = v
+				}
+			}
+			added[selected] = true
+			previous, last = last, selected
+			for _, v := range vertices {
+				connectivity[v] += weights[selected][v]
+			}
+		}
+
+		// The cut separating the last vertex from the rest is a candidate minimum cut
+		var phaseWeight = connectivity[last] - weights[last][last]
+		if phaseWeight < bestWeight {
+			bestWeight = phaseWeight
+			bestGroup = append([]int{}, groups[last]...)
+		}
+
+		// Merge the last two vertices of the ordering
+		groups[previous] = append(groups[previous], groups[last]...)
+		for _, v := range vertices {
+			weights[previous][v] += weights[last][v]
+			weights[v][previous] = weights[previous][v]
+		}
+		for index, v := range vertices {
+			if v == last {
+				vertices = append(vertices[:index], vertices[index+1:]...)
+				break
+			}
+		}
+	}
+
+	var inBest = make([]bool, n)
+	for _, v := range bestGroup {
+		inBest[v] = true
+	}
+	var side, rest []*Node
+	for index, node := range graph.Nodes {
+		if inBest[index] {
+			side = append(side, node)
+		} else {
+			rest = append(rest, node)
+		}
+	}
+	return bestWeight, side, rest, nil
+}
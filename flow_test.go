@@ -0,0 +1,121 @@
+package gograph
+
+import (
+	"testing"
+)
+
+func TestMaximumFlow(t *testing.T) {
+	var algorithms = map[string]func(DirectedGraph, *DirectedNode, *DirectedNode) (FlowResult, error){
+		"EdmondsKarp": EdmondsKarp,
+		"Dinic":       Dinic,
+		"PushRelabel": PushRelabel,
+	}
+
+	for name, algorithm := range algorithms {
+		describe(name, t)
+
+		context("the source and sink are connected", t)
+		// s=0, v1=1, v2=2, v3=3, v4=4, t=5
+		var graph = createValuedDirectedGraph(6, CapacityKey, [][3]float64{
+			{0, 1, 16}, {0, 2, 13}, {1, 3, 12}, {2, 1, 4}, {2, 4, 14},
+			{3, 2, 9}, {3, 5, 20}, {4, 3, 7}, {4, 5, 4},
+		})
+		var nodes = graph.DirectedNodes
+		var result, err = algorithm(graph, nodes[0], nodes[5])
+		if err != nil {
+			t.Errorf("Failed: expected no error, but found %s", err)
+			return
+		}
+
+		it("returns the maximum flow value", t)
+		expectEqualFloats(result.Value, 23, t)
+
+		it("returns a flow that respects capacities and is conserved", t)
+		var balance = make(map[string]float64)
+		for edge, flow := range result.Flow {
+			if flow < -flowEpsilon {
+				t.Errorf("Failed: expected non-negative flow, but found %f", flow)
+			}
+			balance[edge.From] -= flow
+			balance[edge.To] += flow
+		}
+		expectEqualFloats(result.Flow[Edge{From: nodes[4].ID, To: nodes[5].ID}], 4, t)
+		for _, node := range nodes[1:5] {
+			expectEqualFloats(balance[node.ID], 0, t)
+		}
+		expectEqualFloats(balance[nodes[5].ID], 23, t)
+
+		it("returns the minimum cut partition and its edges", t)
+		expectEqualInts(len(result.SourceSide), 4, t)
+		expectEqualInts(len(result.SinkSide), 2, t)
+		expectEqualInts(len(result.CutEdges), 3, t)
+		var cutCapacity float64
+		for _, edge := range result.CutEdges {
+			var _, parent = FindDirectedNode(graph, edge.From)
+			var index, _ = FindDirectedNode(graph, edge.To)
+			cutCapacity += DirectedEdgeCapacity(&parent, graph.DirectedNodes[index])
+		}
+		expectEqualFloats(cutCapacity, 23, t)
+
+		context("the sink is unreachable", t)
+		graph = createValuedDirectedGraph(3, CapacityKey, [][3]float64{{0, 1, 5}})
+		result, _ = algorithm(graph, graph.DirectedNodes[0], graph.DirectedNodes[2])
+
+		it("returns a zero flow", t)
+		expectEqualFloats(result.Value, 0, t)
+		expectEqualInts(len(result.CutEdges), 0, t)
+
+		context("the source is the sink", t)
+		_, err = algorithm(graph, graph.DirectedNodes[0], graph.DirectedNodes[0])
+
+		it("returns an error", t)
+		if err == nil {
+			t.Errorf("Failed: expected an error, but found none")
+		}
+	}
+}
+
+func TestStoerWagnerMinimumCut(t *testing.T) {
+	describe("StoerWagnerMinimumCut", t)
+
+	context("the graph is connected", t)
+	var graph = createWeightedGraph(8, [][3]float64{
+		{0, 1, 2}, {0, 4, 3}, {1, 2, 3}, {1, 4, 2}, {1, 5, 2}, {2, 3, 4},
+		{2, 6, 2}, {3, 6, 2}, {3, 7, 2}, {4, 5, 3}, {5, 6, 1}, {6, 7, 3},
+	})
+	var weight, side, rest, err = StoerWagnerMinimumCut(graph)
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("returns the weight of the minimum cut", t)
+	expectEqualFloats(weight, 4, t)
+
+	it("partitions the nodes into the two sides of the cut", t)
+	expectEqualInts(len(side), 4, t)
+	expectEqualInts(len(rest), 4, t)
+	var inSide = map[*Node]bool{}
+	for _, node := range side {
+		inSide[node] = true
+	}
+	expectEqualBools(inSide[graph.Nodes[2]], inSide[graph.Nodes[7]], t)
+	expectEqualBools(inSide[graph.Nodes[0]], inSide[graph.Nodes[5]], t)
+	expectEqualBools(inSide[graph.Nodes[0]] == inSide[graph.Nodes[7]], false, t)
+
+	context("the graph is disconnected", t)
+	graph = createWeightedGraph(4, [][3]float64{{0, 1, 5}, {2, 3, 5}})
+	weight, _, _, _ = StoerWagnerMinimumCut(graph)
+
+	it("returns a cut of zero weight", t)
+	expectEqualFloats(weight, 0, t)
+
+	context("the graph has a single node", t)
+	graph = createWeightedGraph(1, nil)
+	_, _, _, err = StoerWagnerMinimumCut(graph)
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
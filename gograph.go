@@ -7,6 +7,9 @@ import (
 	"time"
 )
 
+// WeightKey is the edge value key under which edge weights are stored
+const WeightKey = "weight"
+
 // Graph has many nodes
 type Graph struct {
 	Nodes []*Node
@@ -14,14 +17,24 @@ type Graph struct {
 
 // Node is a generic recursive data structure that only has undirected edges.
 type Node struct {
-	Edges []*Node
-	ID    string
+	Edges      []*Node
+	Values     map[string]string
+	EdgeValues map[string]map[string]string // values of the edge to each neighbor, keyed by neighbor ID
+	ID         string
+}
+
+// Edge describes an edge by the IDs of its endpoints. For directed edges, From is the
+// parent and To is the child.
+type Edge struct {
+	From string
+	To   string
 }
 
 // DirectedGraph has many nodes with directed edges
 type DirectedGraph struct {
 	DirectedNodes    []*DirectedNode
 	RootDirectedNode *DirectedNode
+	Merkle           *MerkleIndex // content hashes of the nodes, if enabled by EnableMerkleHashing
 }
 
 // DirectedNode has a single parent node edges and a single child node edges
@@ -38,10 +51,11 @@ type DirectedGraph struct {
 //       able to safely determine if nodes meet the constraints before insertion
 //       into the graph.
 type DirectedNode struct {
-	Parents  []*DirectedNode
-	Children []*DirectedNode
-	Values   map[string]string
-	ID       string
+	Parents    []*DirectedNode
+	Children   []*DirectedNode
+	Values     map[string]string
+	EdgeValues map[string]map[string]string // values of the edge to each child, keyed by child ID
+	ID         string
 }
 
 // CreateGraph returns a null graph object with a single root node. Does not create edges.
@@ -51,13 +65,114 @@ func CreateGraph() DirectedGraph {
 	return DirectedGraph{DirectedNodes: graphDirectedNodes, RootDirectedNode: node}
 }
 
-// CreateDirectedEdge creates a parent-child relationship between two specified nodes.
+// CreateDirectedEdge creates a parent-child relationship between two specified nodes. On a
+// Merkle hashed graph the edge must not close a cycle; CreateMerkleEdge checks this.
 func CreateDirectedEdge(graph DirectedGraph, parent *DirectedNode, child *DirectedNode) (DirectedGraph, *DirectedNode, *DirectedNode) {
 	child.Parents = append(child.Parents, parent)
 	parent.Children = append(parent.Children, child)
+	if graph.Merkle != nil {
+		graph.Merkle.update(parent)
+	}
 	return graph, parent, child
 }
 
+// CreateNode returns a node with a random ID and appends it to the graph. Does not create edges.
+func CreateNode(graph Graph) (Graph, *Node) {
+	var node = &Node{ID: CreateDirectedNodeID()}
+	graph.Nodes = append(graph.Nodes, node)
+	return graph, node
+}
+
+// CreateEdge creates an undirected edge between two specified nodes.
+func CreateEdge(graph Graph, a *Node, b *Node) (Graph, *Node, *Node) {
+	a.Edges = append(a.Edges, b)
+	if a != b {
+		b.Edges = append(b.Edges, a)
+	}
+	return graph, a, b
+}
+
+// CreateUndirectedGraph returns an undirected graph with a copy of every node of a directed
+// graph, sharing its Values, and an edge between every parent and child, so that algorithms on undirected graphs
+// can be applied to directed graphs. Each directed edge is paired with an opposing one between
+// the same two nodes, if any is left, into a single edge, which carries the values of the first
+// directed edge encountered; parallel edges in the same direction and self-loops stay separate.
+func CreateUndirectedGraph(graph DirectedGraph) Graph {
+	var undirected = Graph{Nodes: make([]*Node, len(graph.DirectedNodes))}
+	var copies = make(map[*DirectedNode]*Node, len(graph.DirectedNodes))
+	for index, node := range graph.DirectedNodes {
+		undirected.Nodes[index] = &Node{ID: node.ID, Values: node.Values}
+		copies[node] = undirected.Nodes[index]
+	}
+
+	var unpaired = map[[2]*Node]int{} // count of edges from one node to another not yet paired
+	for _, parent := range graph.DirectedNodes {
+		for _, child := range parent.Children {
+			var a, b = copies[parent], copies[child]
+			if b == nil {
+				continue
+			}
+			if a != b && unpaired[[2]*Node{b, a}] > 0 {
+				unpaired[[2]*Node{b, a}]--
+				continue
+			}
+			unpaired[[2]*Node{a, b}]++
+			undirected, _, _ = CreateEdge(undirected, a, b)
+			for key, value := range parent.EdgeValues[child.ID] {
+				SetEdgeValue(a, b, key, value)
+			}
+		}
+	}
+	return undirected
+}
+
+// SetEdgeValue sets a value on the undirected edge between two nodes. The value is
+// recorded on both nodes so that it can be read from either end.
+func SetEdgeValue(a *Node, b *Node, key string, value string) {
+	setEdgeValue(&a.EdgeValues, b.ID, key, value)
+	setEdgeValue(&b.EdgeValues, a.ID, key, value)
+}
+
+// SetDirectedEdgeValue sets a value on the directed edge from a parent to a child node.
+func SetDirectedEdgeValue(parent *DirectedNode, child *DirectedNode, key string, value string) {
+	setEdgeValue(&parent.EdgeValues, child.ID, key, value)
+}
+
+// EdgeWeight returns the weight of the undirected edge between two nodes. Edges without
+// a weight are treated as having a weight of 1.
+func EdgeWeight(a *Node, b *Node) float64 {
+	return edgeFloat(a.EdgeValues, b.ID, WeightKey, 1)
+}
+
+// DirectedEdgeWeight returns the weight of the directed edge from a parent to a child node.
+// Edges without a weight are treated as having a weight of 1.
+func DirectedEdgeWeight(parent *DirectedNode, child *DirectedNode) float64 {
+	return edgeFloat(parent.EdgeValues, child.ID, WeightKey, 1)
+}
+
+func setEdgeValue(edgeValues *map[string]map[string]string, ID string, key string, value string) {
+	if *edgeValues == nil {
+		*edgeValues = map[string]map[string]string{}
+	}
+	if (*edgeValues)[ID] == nil {
+		(*edgeValues)[ID] = map[string]string{}
+	}
+	(*edgeValues)[ID][key] = value
+}
+
+// edgeFloat reads a numeric edge value, returning the fallback when it is absent or malformed
+func edgeFloat(edgeValues map[string]map[string]string, ID string, key string, fallback float64) float64 {
+	var value, ok = edgeValues[ID][key]
+	if !ok {
+		return fallback
+	}
+	var number, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return number
+}
+
 // CreateDirectedNode returns a node with a random ID. Does not create edges.
 func CreateDirectedNode(graph DirectedGraph, values map[string]string, parents []*DirectedNode, children []*DirectedNode) (DirectedGraph, *DirectedNode) {
 	var nodeID = CreateDirectedNodeID()
@@ -99,6 +214,9 @@ func CreateDirectedNode(graph DirectedGraph, values map[string]string, parents [
 		}
 		graph.RootDirectedNode = node
 	}
+	if graph.Merkle != nil {
+		graph.Merkle.update(node)
+	}
 
 	return graph, node
 }
@@ -127,6 +245,17 @@ func DeleteDirectedEdge(graph DirectedGraph, parent *DirectedNode, child *Direct
 			break
 		}
 	}
+	// Parallel edges between the same nodes share their edge values
+	var isParallel = false
+	for _, childNode := range parent.Children {
+		isParallel = isParallel || childNode.ID == child.ID
+	}
+	if !isParallel {
+		delete(parent.EdgeValues, child.ID)
+	}
+	if graph.Merkle != nil {
+		graph.Merkle.update(parent)
+	}
 
 	return graph, parent, child
 }
@@ -214,74 +343,117 @@ func TopologicalSort(graph DirectedGraph) []*DirectedNode {
 // CreateAdjecencyMatrix initial implementation, whether a directed edge exists from
 // j-th element to the i-th element. We define the parent to child direction as the
 // j-th to i-th direction.
-func CreateAdjecencyMatrix(graph DirectedGraph) [][]int {
-	var numNodes = len(graph.DirectedNodes)
-	var adjMatrix = make([][]int, numNodes)
-	for a := range graph.DirectedNodes {
-		adjMatrix[a] = make([]int, numNodes)
-	}
-
-	for i, inode := range graph.DirectedNodes {
-		for j, jnode := range graph.DirectedNodes {
-			for _, jNodeParent := range jnode.Parents {
-				if inode.ID == jNodeParent.ID {
-					adjMatrix[i][j] = 1
-				}
+func CreateAdjecencyMatrix(graph DirectedGraph) Matrix {
+	// Building from the sparse matrix takes time proportional to the size of the matrix
+	var adjMatrix = CreateCSRMatrix(graph).Dense()
+	for _, row := range adjMatrix {
+		for j := range row {
+			if row[j] > 1 {
+				row[j] = 1
 			}
 		}
 	}
-
 	return adjMatrix
 }
 
-// CreateIncidenceMatrix initial implementation, whether a directed edge exists from
-// j-th element to the i-th element. We define the parent to child direction as the
-// j-th to i-th direction.
-func CreateIncidenceMatrix(graph DirectedGraph) [][]int {
-	var numNodes = len(graph.DirectedNodes)
-	var adjMatrix = make([][]int, numNodes)
-	for a := range graph.DirectedNodes {
-		adjMatrix[a] = make([]int, numNodes)
-	}
-
-	for i, inode := range graph.DirectedNodes {
-		for j, jnode := range graph.DirectedNodes {
-			for _, jNodeParent := range jnode.Parents {
-				if inode.ID == jNodeParent.ID {
-					adjMatrix[i][j] = 1
-				}
-			}
-			for _, jNodeChild := range jnode.Children {
-				if inode.ID == jNodeChild.ID {
-					adjMatrix[i][j] = -1
-				}
+// DirectedEdgeList lists every edge of a directed graph, in the order of the graph's nodes and
+// then of each node's Children. Parallel edges are listed once for each edge. The position of
+// an edge in this list is its index, such as its column in the incidence matrix.
+func DirectedEdgeList(graph DirectedGraph) []Edge {
+	var indices = indexDirectedNodes(graph)
+	var edges []Edge
+	for _, node := range graph.DirectedNodes {
+		for _, child := range node.Children {
+			if _, ok := indices[child]; ok {
+				edges = append(edges, Edge{From: node.ID, To: child.ID})
 			}
 		}
 	}
-
-	return adjMatrix
+	return edges
 }
 
-// PrintMatrix prints a matrix of integers
-func PrintMatrix(matrix [][]int) {
-	for _, parent := range matrix {
-		fmt.Printf("%+v\n", parent)
+// EdgeList lists every edge of an undirected graph once, in the order of the graph's nodes and
+// then of each node's Edges, from the earlier of its two nodes. Parallel edges are listed once
+// for each edge and self-loops are included. The position of an edge in this list is its
+// index, such as its column in the incidence matrix.
+func EdgeList(graph Graph) []Edge {
+	var indices = indexNodes(graph)
+	var edges []Edge
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i <= j {
+				edges = append(edges, Edge{From: node.ID, To: neighbor.ID})
+			}
+		}
 	}
+	return edges
 }
 
-// IsAntisymmetricMatrix checks that
-func IsAntisymmetricMatrix(matrix [][]int) bool {
-	var jsize = len(matrix)
-	for j := 0; j < jsize; j++ {
-		var isize = len(matrix[j])
-		for i := j; i < isize; i++ {
-			if len(matrix[i]) != len(matrix[j]) {
-				panic("Provided matrix is not a square matrix!")
+// CreateIncidenceMatrix returns the n×m incidence matrix of a directed graph, whose i-th row
+// stands for the i-th node of the graph and whose k-th column stands for the k-th edge of
+// DirectedEdgeList. An entry is 1 if the edge leaves the node as its parent, -1 if it enters
+// the node as its child, and 0 otherwise, so the column of a self-loop is all zeros.
+func CreateIncidenceMatrix(graph DirectedGraph) Matrix {
+	var indices = indexDirectedNodes(graph)
+	var numEdges = 0
+	for _, list := range childLists(graph) {
+		numEdges += len(list)
+	}
+	var incMatrix = make(Matrix, len(graph.DirectedNodes))
+	for i := range incMatrix {
+		incMatrix[i] = make([]int, numEdges)
+	}
+
+	var k = 0
+	for i, node := range graph.DirectedNodes {
+		for _, child := range node.Children {
+			if j, ok := indices[child]; ok {
+				incMatrix[i][k]++
+				incMatrix[j][k]--
+				k++
 			}
-			if matrix[j][i] != -matrix[i][j] {
-				return false
+		}
+	}
+
+	return incMatrix
+}
+
+// CreateUndirectedIncidenceMatrix returns the n×m incidence matrix of an undirected graph, whose
+// i-th row stands for the i-th node of the graph and whose k-th column stands for the k-th edge
+// of EdgeList. An entry is 1 if the edge joins the node to another, 2 if the edge is a
+// self-loop on the node, and 0 otherwise.
+func CreateUndirectedIncidenceMatrix(graph Graph) Matrix {
+	var indices = indexNodes(graph)
+	var numEdges = len(EdgeList(graph))
+	var incMatrix = make(Matrix, len(graph.Nodes))
+	for i := range incMatrix {
+		incMatrix[i] = make([]int, numEdges)
+	}
+
+	var k = 0
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i <= j {
+				incMatrix[i][k]++
+				incMatrix[j][k]++
+				k++
 			}
 		}
 	}
-	return true
+
+	return incMatrix
+}
+
+// PrintMatrix prints a matrix of integers in right-aligned columns
+func PrintMatrix(matrix Matrix) {
+	fmt.Print(matrix)
+}
+
+// IsAntisymmetricMatrix checks that a matrix equals the negation of its transpose, and is
+// false for a matrix that is not square.
+//
+// Deprecated: use Matrix.IsAntisymmetric, which reports a matrix that is not square as an error
+func IsAntisymmetricMatrix(matrix Matrix) bool {
+	var antisymmetric, err = matrix.IsAntisymmetric()
+	return err == nil && antisymmetric
 }
@@ -387,15 +387,15 @@ func TestCreateAdjecencyMatrix(t *testing.T) {
 
 func TestCreateIncidenceMatrix(t *testing.T) {
 	describe("CreateIncidenceMatrix", t)
-	it("should correctly describe the edge relationships between nodes", t)
+	it("should correctly describe the edges leaving and entering each node", t)
 	var graph = CreateGraph()
 	var incidenceMatrix [][]int
 	var nodeA, nodeB, nodeC *DirectedNode
 	var expectedMatrix = [][]int{
-		{0, 1, 1, 0},
-		{-1, 0, 0, 1},
-		{-1, 0, 0, 1},
-		{0, -1, -1, 0},
+		{1, 1, 0, 0},
+		{-1, 0, 1, 0},
+		{0, -1, 0, 1},
+		{0, 0, -1, -1},
 	}
 
 	//    A
@@ -410,12 +410,111 @@ func TestCreateIncidenceMatrix(t *testing.T) {
 	graph, _ = CreateDirectedNode(graph, map[string]string{"name": "nodeD"}, []*DirectedNode{nodeB, nodeC}, []*DirectedNode{})
 	incidenceMatrix = CreateIncidenceMatrix(graph)
 
+	expectEqualInts(len(incidenceMatrix), len(expectedMatrix), t)
 	for i := range incidenceMatrix {
-		for j := range incidenceMatrix {
+		expectEqualInts(len(incidenceMatrix[i]), len(expectedMatrix[i]), t)
+		for j := range incidenceMatrix[i] {
 			if incidenceMatrix[i][j] != expectedMatrix[i][j] {
 				t.Errorf("Failed: expected %+v, but found %+v", expectedMatrix, incidenceMatrix)
 				return
 			}
 		}
 	}
+
+	it("should number the edges as DirectedEdgeList does", t)
+	var edges = DirectedEdgeList(graph)
+	expectEqualInts(len(edges), 4, t)
+	expectEqualStrings(edges[1].From, nodeA.ID, t)
+	expectEqualStrings(edges[1].To, nodeC.ID, t)
+	expectEqualStrings(edges[2].From, nodeB.ID, t)
+}
+
+func TestCreateUndirectedIncidenceMatrix(t *testing.T) {
+	describe("CreateUndirectedIncidenceMatrix", t)
+	var graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 2, 1}})
+	var incidenceMatrix = CreateUndirectedIncidenceMatrix(graph)
+	var expectedMatrix = [][]int{
+		{1, 0, 0},
+		{1, 1, 0},
+		{0, 1, 2},
+	}
+
+	it("should mark both ends of every edge, and a self-loop twice", t)
+	for i := range expectedMatrix {
+		for j := range expectedMatrix[i] {
+			expectEqualInts(incidenceMatrix[i][j], expectedMatrix[i][j], t)
+		}
+	}
+
+	it("should number the edges as EdgeList does", t)
+	var edges = EdgeList(graph)
+	expectEqualInts(len(edges), 3, t)
+	expectEqualStrings(edges[2].From, graph.Nodes[2].ID, t)
+	expectEqualStrings(edges[2].To, graph.Nodes[2].ID, t)
+}
+
+func TestCreateEdge(t *testing.T) {
+	describe("CreateEdge", t)
+	var graph = Graph{}
+	var nodeA, nodeB *Node
+	graph, nodeA = CreateNode(graph)
+	graph, nodeB = CreateNode(graph)
+	graph, nodeA, nodeB = CreateEdge(graph, nodeA, nodeB)
+
+	it("should make each node a neighbor of the other", t)
+	expectEqualInts(len(graph.Nodes), 2, t)
+	expectEqualStrings(nodeA.Edges[0].ID, nodeB.ID, t)
+	expectEqualStrings(nodeB.Edges[0].ID, nodeA.ID, t)
+
+	it("should default the edge weight to 1", t)
+	expectEqualFloats(EdgeWeight(nodeA, nodeB), 1, t)
+
+	it("should read edge values from either end", t)
+	SetEdgeValue(nodeA, nodeB, WeightKey, "2.5")
+	expectEqualFloats(EdgeWeight(nodeA, nodeB), 2.5, t)
+	expectEqualFloats(EdgeWeight(nodeB, nodeA), 2.5, t)
+}
+
+func TestSetDirectedEdgeValue(t *testing.T) {
+	describe("SetDirectedEdgeValue", t)
+	var graph = CreateGraph()
+	var parentNode, childNode *DirectedNode
+	graph, parentNode = CreateDirectedNode(graph, nil, []*DirectedNode{}, []*DirectedNode{})
+	graph, childNode = CreateDirectedNode(graph, nil, []*DirectedNode{}, []*DirectedNode{})
+	graph, parentNode, childNode = CreateDirectedEdge(graph, parentNode, childNode)
+	SetDirectedEdgeValue(parentNode, childNode, WeightKey, "3")
+
+	it("should set the weight of the edge from parent to child", t)
+	expectEqualFloats(DirectedEdgeWeight(parentNode, childNode), 3, t)
+
+	it("should forget the edge values once the edge is deleted", t)
+	DeleteDirectedEdge(graph, parentNode, childNode)
+	expectEqualFloats(DirectedEdgeWeight(parentNode, childNode), 1, t)
+}
+
+func TestCreateUndirectedGraph(t *testing.T) {
+	describe("CreateUndirectedGraph", t)
+	var directed = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 4}, {1, 0, 4}, {1, 2, 2}})
+	var graph = CreateUndirectedGraph(directed)
+
+	it("copies every node", t)
+	expectEqualInts(len(graph.Nodes), 3, t)
+	expectEqualStrings(graph.Nodes[2].ID, directed.DirectedNodes[2].ID, t)
+
+	it("merges opposing directed edges into one undirected edge", t)
+	expectEqualInts(countUndirectedEdges(graph), 2, t)
+
+	it("carries over edge values", t)
+	expectEqualFloats(EdgeWeight(graph.Nodes[2], graph.Nodes[1]), 2, t)
+
+	context("parallel directed edges run the same way", t)
+	directed = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 1, 1}, {1, 0, 1}, {1, 2, 1}, {2, 2, 1}, {2, 2, 1}})
+	graph = CreateUndirectedGraph(directed)
+
+	it("keeps them apart, merging only opposing pairs", t)
+	expectEqualInts(len(graph.Nodes[0].Edges), 2, t)
+	expectEqualInts(len(Bridges(graph)), 1, t)
+
+	it("keeps every self-loop", t)
+	expectEqualInts(len(graph.Nodes[2].Edges), 3, t)
 }
@@ -1,6 +1,10 @@
 package gograph
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"testing"
 )
 
@@ -27,3 +31,82 @@ func it(description string, t *testing.T) {
 func context(description string, t *testing.T) {
 	t.Logf(" - when %s", description)
 }
+
+func expectEqualFloats(value float64, expectation float64, t *testing.T) {
+	if math.Abs(value-expectation) > 1e-9 {
+		t.Errorf("Failed: expected %f, but found %f", expectation, value)
+	}
+}
+
+func expectEqualBools(value bool, expectation bool, t *testing.T) {
+	if value != expectation {
+		t.Errorf("Failed: expected %t, but found %t", expectation, value)
+	}
+}
+
+// createWeightedGraph builds an undirected graph of n nodes from {a, b, weight} triples of node indices
+func createWeightedGraph(n int, edges [][3]float64) Graph {
+	var graph = Graph{}
+	for i := 0; i < n; i++ {
+		graph, _ = CreateNode(graph)
+	}
+	for _, edge := range edges {
+		var a, b = graph.Nodes[int(edge[0])], graph.Nodes[int(edge[1])]
+		graph, _, _ = CreateEdge(graph, a, b)
+		SetEdgeValue(a, b, WeightKey, strconv.FormatFloat(edge[2], 'f', -1, 64))
+	}
+	return graph
+}
+
+// createWeightedDirectedGraph builds a directed graph of n nodes rooted at the first node from
+// {parent, child, weight} triples of node indices. Every node is named by its index.
+func createWeightedDirectedGraph(n int, edges [][3]float64) DirectedGraph {
+	return createValuedDirectedGraph(n, WeightKey, edges)
+}
+
+// createValuedDirectedGraph builds a directed graph like createWeightedDirectedGraph, storing
+// the third element of each triple under the given edge value key.
+func createValuedDirectedGraph(n int, key string, edges [][3]float64) DirectedGraph {
+	var graph = CreateGraph()
+	for i := 0; i < n; i++ {
+		graph, _ = CreateDirectedNode(graph, map[string]string{"name": strconv.Itoa(i)}, []*DirectedNode{}, []*DirectedNode{})
+	}
+	for _, edge := range edges {
+		var parent, child = graph.DirectedNodes[int(edge[0])], graph.DirectedNodes[int(edge[1])]
+		graph, _, _ = CreateDirectedEdge(graph, parent, child)
+		SetDirectedEdgeValue(parent, child, key, strconv.FormatFloat(edge[2], 'f', -1, 64))
+	}
+	return graph
+}
+
+// describeDirectedGraph summarizes a directed graph as text: its root, and every node in order
+// with its values, its children in order and the values of the edges to them
+func describeDirectedGraph(graph DirectedGraph) string {
+	var description = "root:"
+	if graph.RootDirectedNode != nil {
+		description += graph.RootDirectedNode.ID
+	}
+	for _, node := range graph.DirectedNodes {
+		var children []string
+		for _, child := range node.Children {
+			children = append(children, child.ID)
+		}
+		description += fmt.Sprintf("\n%s %v -> %v %v", node.ID, node.Values, children, node.EdgeValues)
+	}
+	return description
+}
+
+// describeGraph summarizes an undirected graph as text: every node in order with its values,
+// its sorted neighbors and the values of the edges to them
+func describeGraph(graph Graph) string {
+	var description = ""
+	for _, node := range graph.Nodes {
+		var neighbors []string
+		for _, neighbor := range node.Edges {
+			neighbors = append(neighbors, neighbor.ID)
+		}
+		sort.Strings(neighbors)
+		description += fmt.Sprintf("%s %v -- %v %v\n", node.ID, node.Values, neighbors, node.EdgeValues)
+	}
+	return description
+}
@@ -0,0 +1,562 @@
+package gograph
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// integerText and decimalText match the integers and decimal numbers of XML Schema, in which
+// GraphML and GEXF attribute values are typed
+var (
+	integerText = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	decimalText = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+)
+
+// attributeType returns the narrowest type of GraphML and GEXF attributes that every one of a
+// set of values has: "boolean", "long", "double" or else "string"
+func attributeType(values []string) string {
+	var boolean, long, double = true, true, true
+	for _, value := range values {
+		boolean = boolean && (value == "true" || value == "false")
+		if long {
+			var _, err = strconv.ParseInt(value, 10, 64)
+			long = integerText.MatchString(value) && err == nil
+		}
+		double = double && decimalText.MatchString(value)
+	}
+	switch {
+	case len(values) == 0:
+		return "string"
+	case boolean:
+		return "boolean"
+	case long:
+		return "long"
+	case double:
+		return "double"
+	}
+	return "string"
+}
+
+// attributeKey is a value key of nodes or edges declared with a type
+type attributeKey struct {
+	name      string
+	valueType string
+}
+
+// attributeKeys declares every key of a set of values, in order of name, with the type of its
+// values
+func attributeKeys(valueSets []map[string]string) []attributeKey {
+	var values = map[string][]string{}
+	for _, set := range valueSets {
+		for key, value := range set {
+			values[key] = append(values[key], value)
+		}
+	}
+	var keys = make([]attributeKey, 0, len(values))
+	for name, list := range values {
+		keys = append(keys, attributeKey{name: name, valueType: attributeType(list)})
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a].name < keys[b].name })
+	return keys
+}
+
+// graphmlKey declares a GraphML attribute
+type graphmlKey struct {
+	ID         string  `xml:"id,attr"`
+	For        string  `xml:"for,attr,omitempty"`
+	Name       *string `xml:"attr.name,attr"`
+	Type       string  `xml:"attr.type,attr,omitempty"`
+	YFilesType string  `xml:"yfiles.type,attr,omitempty"`
+	Default    *string `xml:"default"`
+}
+
+// graphmlData is the value of a GraphML attribute
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr,omitempty"`
+	Data     []graphmlData `xml:"data"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphmlData `xml:"data"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlDocument struct {
+	XMLName xml.Name      `xml:"graphml"`
+	XMLNS   string        `xml:"xmlns,attr,omitempty"`
+	Keys    []graphmlKey  `xml:"key"`
+	Graph   *graphmlGraph `xml:"graph"`
+}
+
+// graphmlValues returns the data of a node or edge under the declared keys
+func graphmlValues(values map[string]string, keyIDs map[string]string) []graphmlData {
+	var data []graphmlData
+	for key, value := range values {
+		data = append(data, graphmlData{Key: keyIDs[key], Value: value})
+	}
+	sort.Slice(data, func(a, b int) bool { return data[a].Key < data[b].Key })
+	return data
+}
+
+// writeGraphML writes a graph as a GraphML document, declaring a typed key for every value
+func writeGraphML(writer io.Writer, encoded jsonGraph, directed bool) error {
+	var nodeValues = make([]map[string]string, len(encoded.Nodes))
+	for index, node := range encoded.Nodes {
+		nodeValues[index] = node.Values
+	}
+	var edgeValues = make([]map[string]string, len(encoded.Edges))
+	for index, edge := range encoded.Edges {
+		edgeValues[index] = edge.Values
+	}
+
+	var document = graphmlDocument{XMLNS: "http://graphml.graphdrawing.org/xmlns", Graph: &graphmlGraph{ID: "G", EdgeDefault: "undirected"}}
+	var nodeKeyIDs, edgeKeyIDs = map[string]string{}, map[string]string{}
+	var declare = func(domain string, keys []attributeKey, IDs map[string]string) {
+		for _, key := range keys {
+			var name = key.name
+			IDs[name] = "d" + strconv.Itoa(len(document.Keys))
+			document.Keys = append(document.Keys, graphmlKey{ID: IDs[name], For: domain, Name: &name, Type: key.valueType})
+		}
+	}
+	declare("node", attributeKeys(nodeValues), nodeKeyIDs)
+	declare("edge", attributeKeys(edgeValues), edgeKeyIDs)
+	if directed {
+		document.Graph.EdgeDefault = "directed"
+	}
+	if encoded.Root != nil {
+		var name = "root"
+		var rootKey = graphmlKey{ID: "d" + strconv.Itoa(len(document.Keys)), For: "graph", Name: &name, Type: "string"}
+		document.Keys = append(document.Keys, rootKey)
+		document.Graph.Data = []graphmlData{{Key: rootKey.ID, Value: *encoded.Root}}
+	}
+	for _, node := range encoded.Nodes {
+		document.Graph.Nodes = append(document.Graph.Nodes, graphmlNode{ID: node.ID, Data: graphmlValues(node.Values, nodeKeyIDs)})
+	}
+	for _, edge := range encoded.Edges {
+		document.Graph.Edges = append(document.Graph.Edges, graphmlEdge{Source: edge.From, Target: edge.To, Data: graphmlValues(edge.Values, edgeKeyIDs)})
+	}
+
+	io.WriteString(writer, xml.Header)
+	var encoder = xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+// WriteGraphML writes a directed graph as a GraphML document, which yEd, Gephi and most graph
+// tools read. Every node keeps its ID, and its Values, like the values of every edge, are
+// written as attributes with a key declared for every distinct value key, typed as boolean,
+// long or double when all its values are, or else as string. Weights and labels are written
+// like any other value, under WeightKey and "label". The root is recorded in a graph attribute
+// named "root". ReadGraphML gives back the graph.
+func WriteGraphML(writer io.Writer, graph DirectedGraph) error {
+	return writeGraphML(writer, directedNodeLink(graph), true)
+}
+
+// WriteUndirectedGraphML writes an undirected graph as a GraphML document, like WriteGraphML,
+// with every edge written once
+func WriteUndirectedGraphML(writer io.Writer, graph Graph) error {
+	return writeGraphML(writer, nodeLink(graph), false)
+}
+
+// decodeGraphML reads a GraphML document into nodes and edges. Values are read under the names
+// of their keys, and keys with defaults give their default to every node or edge without a
+// value for them. Keys of yEd graphics are skipped.
+func decodeGraphML(reader io.Reader) (*decodedGraph, error) {
+	var document graphmlDocument
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, err
+	}
+	if document.Graph == nil {
+		return nil, errors.New("GraphML document has no graph")
+	}
+	var names = map[string]string{}
+	var skipped = map[string]bool{}
+	var defaults = map[string]map[string]string{"node": {}, "edge": {}, "graph": {}}
+	for _, key := range document.Keys {
+		names[key.ID] = key.ID
+		if key.Name != nil {
+			names[key.ID] = *key.Name
+		}
+		if key.YFilesType != "" {
+			skipped[key.ID] = true
+			continue
+		}
+		if key.Default != nil {
+			for _, domain := range []string{"node", "edge", "graph"} {
+				if key.For == domain || key.For == "all" {
+					defaults[domain][names[key.ID]] = *key.Default
+				}
+			}
+		}
+	}
+	var values = func(domain string, data []graphmlData) map[string]string {
+		if len(data) == 0 && len(defaults[domain]) == 0 {
+			return nil
+		}
+		var result = mergeValues(defaults[domain], nil)
+		for _, datum := range data {
+			if !skipped[datum.Key] {
+				if name, ok := names[datum.Key]; ok {
+					result[name] = datum.Value
+				} else {
+					result[datum.Key] = datum.Value
+				}
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	}
+
+	var graph = document.Graph
+	var decoded = &decodedGraph{directed: graph.EdgeDefault == "directed"}
+	if root, ok := values("graph", graph.Data)["root"]; ok {
+		decoded.root = &root
+	}
+	var index = map[string]int{}
+	for _, node := range graph.Nodes {
+		if err := decoded.addNode(node.ID, values("node", node.Data), index); err != nil {
+			return nil, err
+		}
+	}
+	var undirected = 0
+	for _, edge := range graph.Edges {
+		if edge.Directed == "false" || (edge.Directed == "" && !decoded.directed) {
+			undirected++
+		}
+		if err := decoded.addEdge(edge.Source, edge.Target, values("edge", edge.Data), index); err != nil {
+			return nil, err
+		}
+	}
+	decoded.directed = undirected == 0
+	return decoded, nil
+}
+
+// ReadGraphML builds a directed graph from a GraphML document, such as one written by
+// WriteGraphML or saved by yEd or Gephi. Nodes keep their IDs and the order they are listed
+// in, and the attributes of nodes and edges become their values under the names of their keys,
+// with defaults filled in; values are kept as text whatever their type. The node named by a
+// graph attribute named "root" becomes the root; without one, the graph has no root. An error
+// is returned if the document is malformed, if any edge is undirected, if node IDs repeat, or
+// if an edge or the root names a node that is not listed.
+func ReadGraphML(reader io.Reader) (DirectedGraph, error) {
+	var decoded, err = decodeGraphML(reader)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	if !decoded.directed {
+		return DirectedGraph{}, errors.New("GraphML graph has undirected edges; read it with ReadUndirectedGraphML")
+	}
+	return decoded.directedGraph()
+}
+
+// ReadUndirectedGraphML builds an undirected graph from a GraphML document, like ReadGraphML.
+// Directed edges become undirected edges.
+func ReadUndirectedGraphML(reader io.Reader) (Graph, error) {
+	var decoded, err = decodeGraphML(reader)
+	if err != nil {
+		return Graph{}, err
+	}
+	return decoded.undirectedGraph(), nil
+}
+
+// gexfRootID is the ID of the node attribute that marks the root in GEXF, which has no graph
+// attributes
+const gexfRootID = "root"
+
+type gexfAttribute struct {
+	ID      string  `xml:"id,attr"`
+	Title   *string `xml:"title,attr"`
+	Type    string  `xml:"type,attr"`
+	Default *string `xml:"default"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfValues lists the attribute values of a node or edge
+type gexfValues struct {
+	Values []gexfValue `xml:"attvalue"`
+}
+
+type gexfNode struct {
+	ID        string      `xml:"id,attr"`
+	Label     *string     `xml:"label,attr"`
+	AttValues *gexfValues `xml:"attvalues"`
+}
+
+type gexfEdge struct {
+	ID        string      `xml:"id,attr"`
+	Source    string      `xml:"source,attr"`
+	Target    string      `xml:"target,attr"`
+	Type      string      `xml:"type,attr,omitempty"`
+	Label     *string     `xml:"label,attr"`
+	Weight    *string     `xml:"weight,attr"`
+	AttValues *gexfValues `xml:"attvalues"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr,omitempty"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfDocument struct {
+	XMLName xml.Name   `xml:"gexf"`
+	XMLNS   string     `xml:"xmlns,attr,omitempty"`
+	Version string     `xml:"version,attr,omitempty"`
+	Graph   *gexfGraph `xml:"graph"`
+}
+
+// gexfAttValues returns the attribute values of a node or edge under the declared attributes,
+// or nil if it has none
+func gexfAttValues(values map[string]string, IDs map[string]string) *gexfValues {
+	if len(values) == 0 {
+		return nil
+	}
+	var attValues = &gexfValues{}
+	for key, value := range values {
+		attValues.Values = append(attValues.Values, gexfValue{For: IDs[key], Value: value})
+	}
+	sort.Slice(attValues.Values, func(a, b int) bool { return attValues.Values[a].For < attValues.Values[b].For })
+	return attValues
+}
+
+// list returns the attribute values of a list that may be missing
+func (attValues *gexfValues) list() []gexfValue {
+	if attValues == nil {
+		return nil
+	}
+	return attValues.Values
+}
+
+// writeGEXF writes a graph as a GEXF document, declaring a typed attribute for every value
+func writeGEXF(writer io.Writer, encoded jsonGraph, directed bool) error {
+	var graph = &gexfGraph{DefaultEdgeType: "undirected", Mode: "static"}
+	if directed {
+		graph.DefaultEdgeType = "directed"
+	}
+	// Labels and weights that are numbers have GEXF attributes of their own, and the other
+	// values are written as declared attributes
+	var nodeValues = make([]map[string]string, len(encoded.Nodes))
+	for index, node := range encoded.Nodes {
+		nodeValues[index] = mergeValues(node.Values, nil)
+		delete(nodeValues[index], "label")
+	}
+	var weights = make([]*string, len(encoded.Edges))
+	var edgeValues = make([]map[string]string, len(encoded.Edges))
+	for index, edge := range encoded.Edges {
+		edgeValues[index] = mergeValues(edge.Values, nil)
+		delete(edgeValues[index], "label")
+		if weight, ok := edge.Values[WeightKey]; ok && decimalText.MatchString(weight) {
+			weights[index] = &weight
+			delete(edgeValues[index], WeightKey)
+		}
+	}
+
+	var declare = func(class string, keys []attributeKey) map[string]string {
+		var IDs = map[string]string{}
+		var attributes = gexfAttributes{Class: class}
+		for index, key := range keys {
+			var title = key.name
+			IDs[title] = strconv.Itoa(index)
+			attributes.Attributes = append(attributes.Attributes, gexfAttribute{ID: IDs[title], Title: &title, Type: key.valueType})
+		}
+		if class == "node" && encoded.Root != nil {
+			var title = "root"
+			attributes.Attributes = append(attributes.Attributes, gexfAttribute{ID: gexfRootID, Title: &title, Type: "boolean"})
+		}
+		if len(attributes.Attributes) > 0 {
+			graph.Attributes = append(graph.Attributes, attributes)
+		}
+		return IDs
+	}
+	var nodeIDs = declare("node", attributeKeys(nodeValues))
+	var edgeIDs = declare("edge", attributeKeys(edgeValues))
+
+	for index, node := range encoded.Nodes {
+		var written = gexfNode{ID: node.ID, AttValues: gexfAttValues(nodeValues[index], nodeIDs)}
+		if label, ok := node.Values["label"]; ok {
+			written.Label = &label
+		}
+		if encoded.Root != nil && *encoded.Root == node.ID {
+			if written.AttValues == nil {
+				written.AttValues = &gexfValues{}
+			}
+			written.AttValues.Values = append(written.AttValues.Values, gexfValue{For: gexfRootID, Value: "true"})
+		}
+		graph.Nodes = append(graph.Nodes, written)
+	}
+	for index, edge := range encoded.Edges {
+		var written = gexfEdge{ID: strconv.Itoa(index), Source: edge.From, Target: edge.To, Weight: weights[index], AttValues: gexfAttValues(edgeValues[index], edgeIDs)}
+		if label, ok := edge.Values["label"]; ok {
+			written.Label = &label
+		}
+		graph.Edges = append(graph.Edges, written)
+	}
+
+	io.WriteString(writer, xml.Header)
+	var encoder = xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(gexfDocument{XMLNS: "http://www.gexf.net/1.2draft", Version: "1.2", Graph: graph}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+// WriteGEXF writes a directed graph as a GEXF 1.2 document, the native format of Gephi. Every
+// node keeps its ID, a "label" value becomes the label of its node or edge, and numeric
+// weights become the weights of edges. The other values of nodes and edges are written as
+// attributes, declared with the types of WriteGraphML. As GEXF has no graph attributes, the
+// root is marked by a boolean node attribute with the ID "root". ReadGEXF gives back the graph.
+func WriteGEXF(writer io.Writer, graph DirectedGraph) error {
+	return writeGEXF(writer, directedNodeLink(graph), true)
+}
+
+// WriteUndirectedGEXF writes an undirected graph as a GEXF 1.2 document, like WriteGEXF, with
+// every edge written once
+func WriteUndirectedGEXF(writer io.Writer, graph Graph) error {
+	return writeGEXF(writer, nodeLink(graph), false)
+}
+
+// decodeGEXF reads a GEXF document into nodes and edges
+func decodeGEXF(reader io.Reader) (*decodedGraph, error) {
+	var document gexfDocument
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, err
+	}
+	if document.Graph == nil {
+		return nil, errors.New("GEXF document has no graph")
+	}
+	var graph = document.Graph
+	var titles = map[string]map[string]string{"node": {}, "edge": {}}
+	var defaults = map[string]map[string]string{"node": {}, "edge": {}}
+	for _, attributes := range graph.Attributes {
+		if titles[attributes.Class] == nil {
+			continue
+		}
+		for _, attribute := range attributes.Attributes {
+			var title = attribute.ID
+			if attribute.Title != nil {
+				title = *attribute.Title
+			}
+			titles[attributes.Class][attribute.ID] = title
+			if attribute.Default != nil && !(attributes.Class == "node" && attribute.ID == gexfRootID) {
+				defaults[attributes.Class][title] = *attribute.Default
+			}
+		}
+	}
+	var values = func(class string, label *string, attValues []gexfValue) map[string]string {
+		var result = mergeValues(defaults[class], nil)
+		for _, value := range attValues {
+			if title, ok := titles[class][value.For]; ok {
+				result[title] = value.Value
+			} else {
+				result[value.For] = value.Value
+			}
+		}
+		if label != nil {
+			result["label"] = *label
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	}
+
+	var decoded = &decodedGraph{directed: graph.DefaultEdgeType == "directed"}
+	var index = map[string]int{}
+	for _, node := range graph.Nodes {
+		var attValues []gexfValue
+		for _, value := range node.AttValues.list() {
+			if _, declared := titles["node"][gexfRootID]; declared && value.For == gexfRootID {
+				if value.Value == "true" || value.Value == "1" {
+					var root = node.ID
+					decoded.root = &root
+				}
+				continue
+			}
+			attValues = append(attValues, value)
+		}
+		if err := decoded.addNode(node.ID, values("node", node.Label, attValues), index); err != nil {
+			return nil, err
+		}
+	}
+	var undirected = 0
+	for _, edge := range graph.Edges {
+		if edge.Type == "undirected" || (edge.Type == "" && !decoded.directed) {
+			undirected++
+		}
+		var edgeValues = values("edge", edge.Label, edge.AttValues.list())
+		if edge.Weight != nil {
+			edgeValues = mergeValues(edgeValues, map[string]string{WeightKey: *edge.Weight})
+		}
+		if err := decoded.addEdge(edge.Source, edge.Target, edgeValues, index); err != nil {
+			return nil, err
+		}
+	}
+	decoded.directed = undirected == 0
+	return decoded, nil
+}
+
+// ReadGEXF builds a directed graph from a GEXF document, such as one written by WriteGEXF or
+// exported by Gephi. Nodes keep their IDs and the order they are listed in; labels become
+// "label" values, edge weights become values under WeightKey, and attribute values become
+// values under the titles of their attributes, with defaults filled in. The node marked by the
+// boolean node attribute with the ID "root" becomes the root; without one, the graph has no
+// root. An error is returned if the document is malformed, if any edge is undirected, if node
+// IDs repeat, or if an edge names a node that is not listed.
+func ReadGEXF(reader io.Reader) (DirectedGraph, error) {
+	var decoded, err = decodeGEXF(reader)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	if !decoded.directed {
+		return DirectedGraph{}, errors.New("GEXF graph has undirected edges; read it with ReadUndirectedGEXF")
+	}
+	return decoded.directedGraph()
+}
+
+// ReadUndirectedGEXF builds an undirected graph from a GEXF document, like ReadGEXF. Directed
+// edges become undirected edges.
+func ReadUndirectedGEXF(reader io.Reader) (Graph, error) {
+	var decoded, err = decodeGEXF(reader)
+	if err != nil {
+		return Graph{}, err
+	}
+	return decoded.undirectedGraph(), nil
+}
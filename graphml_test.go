@@ -0,0 +1,251 @@
+package gograph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAttributeType(t *testing.T) {
+	describe("attributeType", t)
+
+	it("gives the narrowest type of every value", t)
+	expectEqualStrings(attributeType([]string{"true", "false"}), "boolean", t)
+	expectEqualStrings(attributeType([]string{"1", "-20"}), "long", t)
+	expectEqualStrings(attributeType([]string{"1", "2.5", "3e-2", ".5"}), "double", t)
+	expectEqualStrings(attributeType([]string{"1", "NaN"}), "string", t)
+	expectEqualStrings(attributeType([]string{"0x1F"}), "string", t)
+	expectEqualStrings(attributeType([]string{"99999999999999999999"}), "double", t)
+	expectEqualStrings(attributeType(nil), "string", t)
+}
+
+func TestWriteGraphML(t *testing.T) {
+	describe("WriteGraphML", t)
+	var graph = createDOTTestGraph()
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[2], WeightKey, "1")
+
+	it("declares a typed key for every value and records the root", t)
+	var buffer bytes.Buffer
+	var err = WriteGraphML(&buffer, graph)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string"></key>
+  <key id="d1" for="node" attr.name="group" attr.type="string"></key>
+  <key id="d2" for="edge" attr.name="weight" attr.type="double"></key>
+  <key id="d3" for="graph" attr.name="root" attr.type="string"></key>
+  <graph id="G" edgedefault="directed">
+    <data key="d3">a</data>
+    <node id="a">
+      <data key="d0">red</data>
+      <data key="d1">left</data>
+    </node>
+    <node id="b">
+      <data key="d1">left</data>
+    </node>
+    <node id="c"></node>
+    <edge source="a" target="b">
+      <data key="d2">2.5</data>
+    </edge>
+    <edge source="a" target="c">
+      <data key="d2">1</data>
+    </edge>
+    <edge source="b" target="c"></edge>
+  </graph>
+</graphml>
+`, t)
+}
+
+func TestReadGraphML(t *testing.T) {
+	describe("ReadGraphML", t)
+
+	it("gives back a graph written by WriteGraphML", t)
+	var graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 0.5}, {2, 0, 3}, {3, 4, 1}, {3, 4, 2}, {4, 4, 1}})
+	graph.DirectedNodes[2].Values["label"] = "line one\r\n<said> \"two\" & three"
+	graph.DirectedNodes[4].Values[""] = "unnamed"
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[1], "label", "first")
+	graph.RootDirectedNode = graph.DirectedNodes[3]
+	var buffer bytes.Buffer
+	WriteGraphML(&buffer, graph)
+	var read, err = ReadGraphML(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("fills in defaults and skips the graphics of yEd", t)
+	read, err = ReadGraphML(strings.NewReader(`<?xml version="1.0"?>
+		<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:y="http://www.yworks.com/xml/graphml">
+			<key id="shape" for="node" yfiles.type="nodegraphics"/>
+			<key id="kind" for="all" attr.name="kind" attr.type="string"><default>plain</default></key>
+			<key id="w" for="edge" attr.name="weight" attr.type="double"/>
+			<graph edgedefault="directed">
+				<node id="n1"><data key="shape"><y:ShapeNode><y:Fill color="#FFCC00"/></y:ShapeNode></data></node>
+				<node id="n0"><data key="kind">special</data></node>
+				<edge source="n1" target="n0" directed="true"><data key="w">4</data></edge>
+			</graph>
+		</graphml>`))
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:\n"+
+		"n1 map[kind:plain] -> [n0] map[n0:map[kind:plain weight:4]]\n"+
+		"n0 map[kind:special] -> [] map[]", t)
+
+	context("the graph has undirected edges", t)
+
+	it("returns an error", t)
+	_, err = ReadGraphML(strings.NewReader(`<graphml><graph edgedefault="undirected"><node id="a"/><edge source="a" target="a"/></graph></graphml>`))
+	expectEqualBools(err == nil, false, t)
+	_, err = ReadGraphML(strings.NewReader(`<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="a" directed="false"/></graph></graphml>`))
+	expectEqualBools(err == nil, false, t)
+
+	context("the document is not a valid graph", t)
+
+	it("returns an error", t)
+	for _, document := range []string{
+		`<graphml><graph edgedefault="directed">`,
+		`<graphml></graphml>`,
+		`<graphml><graph edgedefault="directed"><node id="a"/><node id="a"/></graph></graphml>`,
+		`<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="b"/></graph></graphml>`,
+		`<graphml><key id="r" for="graph" attr.name="root"/><graph edgedefault="directed"><data key="r">b</data><node id="a"/></graph></graphml>`,
+	} {
+		_, err = ReadGraphML(strings.NewReader(document))
+		expectEqualBools(err == nil, false, t)
+	}
+}
+
+func TestReadUndirectedGraphML(t *testing.T) {
+	describe("ReadUndirectedGraphML", t)
+
+	it("gives back a graph written by WriteUndirectedGraphML", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}, {0, 1, 4}})
+	graph.Nodes[3].Values = map[string]string{"color": "blue"}
+	var buffer bytes.Buffer
+	WriteUndirectedGraphML(&buffer, graph)
+	expectEqualBools(strings.Contains(buffer.String(), `<graph id="G" edgedefault="undirected">`), true, t)
+	var read, err = ReadUndirectedGraphML(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(read), describeGraph(graph), t)
+}
+
+func TestWriteGEXF(t *testing.T) {
+	describe("WriteGEXF", t)
+	var graph = createDOTTestGraph()
+	graph.DirectedNodes[2].Values = map[string]string{"label": "Sink"}
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[2], WeightKey, "heavy")
+
+	it("writes labels and numeric weights as GEXF attributes and marks the root", t)
+	var buffer bytes.Buffer
+	var err = WriteGEXF(&buffer, graph)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="node">
+      <attribute id="0" title="color" type="string"></attribute>
+      <attribute id="1" title="group" type="string"></attribute>
+      <attribute id="root" title="root" type="boolean"></attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="0" title="weight" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="a">
+        <attvalues>
+          <attvalue for="0" value="red"></attvalue>
+          <attvalue for="1" value="left"></attvalue>
+          <attvalue for="root" value="true"></attvalue>
+        </attvalues>
+      </node>
+      <node id="b">
+        <attvalues>
+          <attvalue for="1" value="left"></attvalue>
+        </attvalues>
+      </node>
+      <node id="c" label="Sink"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" weight="2.5"></edge>
+      <edge id="1" source="a" target="c">
+        <attvalues>
+          <attvalue for="0" value="heavy"></attvalue>
+        </attvalues>
+      </edge>
+      <edge id="2" source="b" target="c"></edge>
+    </edges>
+  </graph>
+</gexf>
+`, t)
+}
+
+func TestReadGEXF(t *testing.T) {
+	describe("ReadGEXF", t)
+
+	it("gives back a graph written by WriteGEXF", t)
+	var graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 0.5}, {2, 0, 3}, {3, 4, 1}, {3, 4, 2}, {4, 4, 1}})
+	graph.DirectedNodes[2].Values["label"] = "line one\r\n<said> \"two\" & three"
+	graph.DirectedNodes[4].Values["root"] = "false"
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[1], "label", "first")
+	graph.RootDirectedNode = graph.DirectedNodes[3]
+	var buffer bytes.Buffer
+	WriteGEXF(&buffer, graph)
+	var read, err = ReadGEXF(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("reads the GEXF exported by Gephi", t)
+	read, err = ReadGEXF(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+		<gexf xmlns="http://www.gexf.net/1.2draft" xmlns:viz="http://www.gexf.net/1.2draft/viz" version="1.2">
+			<meta lastmodifieddate="2024-01-01"><creator>Gephi 0.10</creator></meta>
+			<graph defaultedgetype="directed" mode="static">
+				<attributes class="node" mode="static">
+					<attribute id="modularity_class" title="Modularity Class" type="integer"><default>0</default></attribute>
+				</attributes>
+				<nodes>
+					<node id="0" label="Alpha"><viz:size value="10.0"/></node>
+					<node id="1" label="Beta">
+						<attvalues><attvalue for="modularity_class" value="2"/></attvalues>
+					</node>
+				</nodes>
+				<edges>
+					<edge id="0" source="1" target="0" weight="3.0" label="knows"/>
+				</edges>
+			</graph>
+		</gexf>`))
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:\n"+
+		"0 map[Modularity Class:0 label:Alpha] -> [] map[]\n"+
+		"1 map[Modularity Class:2 label:Beta] -> [0] map[0:map[label:knows weight:3.0]]", t)
+
+	context("the graph has undirected edges", t)
+
+	it("returns an error", t)
+	_, err = ReadGEXF(strings.NewReader(`<gexf><graph><nodes><node id="a"/></nodes><edges><edge id="0" source="a" target="a"/></edges></graph></gexf>`))
+	expectEqualBools(err == nil, false, t)
+	_, err = ReadGEXF(strings.NewReader(`<gexf><graph defaultedgetype="directed"><nodes><node id="a"/></nodes><edges><edge id="0" source="a" target="a" type="undirected"/></edges></graph></gexf>`))
+	expectEqualBools(err == nil, false, t)
+
+	context("the document is not a valid graph", t)
+
+	it("returns an error", t)
+	for _, document := range []string{
+		`<gexf><graph defaultedgetype="directed">`,
+		`<gexf></gexf>`,
+		`<gexf><graph defaultedgetype="directed"><nodes><node id="a"/><node id="a"/></nodes></graph></gexf>`,
+		`<gexf><graph defaultedgetype="directed"><nodes><node id="a"/></nodes><edges><edge id="0" source="a" target="b"/></edges></graph></gexf>`,
+	} {
+		_, err = ReadGEXF(strings.NewReader(document))
+		expectEqualBools(err == nil, false, t)
+	}
+}
+
+func TestReadUndirectedGEXF(t *testing.T) {
+	describe("ReadUndirectedGEXF", t)
+
+	it("gives back a graph written by WriteUndirectedGEXF", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}, {0, 1, 4}})
+	graph.Nodes[3].Values = map[string]string{"color": "blue", "label": "Three"}
+	var buffer bytes.Buffer
+	WriteUndirectedGEXF(&buffer, graph)
+	expectEqualBools(strings.Contains(buffer.String(), `<graph defaultedgetype="undirected" mode="static">`), true, t)
+	var read, err = ReadUndirectedGEXF(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(read), describeGraph(graph), t)
+}
@@ -0,0 +1,402 @@
+package gograph
+
+import (
+	"sort"
+)
+
+// NodeMatcher reports whether a node of one graph may be mapped onto a node of another
+type NodeMatcher func(a *DirectedNode, b *DirectedNode) bool
+
+// EdgeMatcher reports whether the edge from parent a to child b of one graph may be mapped
+// onto the edge from parent c to child d of another.
+type EdgeMatcher func(a *DirectedNode, b *DirectedNode, c *DirectedNode, d *DirectedNode) bool
+
+// IsomorphismOptions controls the isomorphism searches. Nil matchers accept any pair.
+type IsomorphismOptions struct {
+	NodeMatch NodeMatcher
+	EdgeMatch EdgeMatcher
+	Induced   bool // subgraph searches also require non-adjacent pattern nodes to map to non-adjacent nodes
+	Limit     int  // maximum number of mappings a subgraph search returns, or 0 for all of them
+}
+
+// MatchValues returns a NodeMatcher accepting nodes that have the same values for every key
+func MatchValues(keys ...string) NodeMatcher {
+	return func(a *DirectedNode, b *DirectedNode) bool {
+		for _, key := range keys {
+			if a.Values[key] != b.Values[key] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchEdgeValues returns an EdgeMatcher accepting edges that have the same edge values for
+// every key
+func MatchEdgeValues(keys ...string) EdgeMatcher {
+	return func(a *DirectedNode, b *DirectedNode, c *DirectedNode, d *DirectedNode) bool {
+		for _, key := range keys {
+			if a.EdgeValues[b.ID][key] != c.EdgeValues[d.ID][key] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// vf2Graph is the indexed structure of a directed graph used by the isomorphism search
+type vf2Graph struct {
+	nodes     []*DirectedNode
+	counts    map[[2]int]int // number of edges from parent to child index
+	inDegree  []int
+	outDegree []int
+	neighbors [][]int // parents and children without repetition
+	parents   [][]int // parents other than the node itself, without repetition
+	children  [][]int // children other than the node itself, without repetition
+}
+
+func newVF2Graph(graph DirectedGraph) *vf2Graph {
+	var children = childLists(graph)
+	var n = len(children)
+	var structure = &vf2Graph{
+		nodes:     graph.DirectedNodes,
+		counts:    map[[2]int]int{},
+		inDegree:  make([]int, n),
+		outDegree: make([]int, n),
+		neighbors: make([][]int, n),
+		parents:   make([][]int, n),
+		children:  make([][]int, n),
+	}
+	var seen = make([]map[int]bool, n)
+	for i := range seen {
+		seen[i] = map[int]bool{}
+	}
+	for u, list := range children {
+		for _, v := range list {
+			structure.counts[[2]int{u, v}]++
+			structure.outDegree[u]++
+			structure.inDegree[v]++
+			if u == v {
+				continue
+			}
+			if structure.counts[[2]int{u, v}] == 1 {
+				structure.children[u] = append(structure.children[u], v)
+				structure.parents[v] = append(structure.parents[v], u)
+			}
+			if !seen[u][v] {
+				seen[u][v], seen[v][u] = true, true
+				structure.neighbors[u] = append(structure.neighbors[u], v)
+				structure.neighbors[v] = append(structure.neighbors[v], u)
+			}
+		}
+	}
+	return structure
+}
+
+// vf2State is the partial mapping explored by the state-space search of VF2. Along with the
+// mapping it keeps the terminal sets of both graphs: the unmapped parents and children of
+// mapped nodes, marked with the depth at which they joined, which VF2 counts to look ahead.
+type vf2State struct {
+	pattern    *vf2Graph
+	target     *vf2Graph
+	order      []int
+	core       []int // target index mapped to by every pattern index, or -1
+	reverse    []int // pattern index mapped to every target index, or -1
+	patternIn  []int // depth at which each pattern node became a parent of a mapped node, or 0
+	patternOut []int // depth at which each pattern node became a child of a mapped node, or 0
+	targetIn   []int // depth at which each target node became a parent of a mapped node, or 0
+	targetOut  []int // depth at which each target node became a child of a mapped node, or 0
+	exact      bool  // whether edge multiplicities between mapped nodes must be equal
+	degrees    bool  // whether mapped nodes must have equal degrees
+	options    IsomorphismOptions
+	mappings   []map[string]string
+}
+
+// matchingOrder orders the pattern nodes as VF2++ does, so that each node is as connected as
+// possible to those before it, preferring nodes of higher degree.
+func matchingOrder(pattern *vf2Graph) []int {
+	var n = len(pattern.nodes)
+	var ordered = make([]bool, n)
+	var connections = make([]int, n)
+	var order = make([]int, 0, n)
+	for len(order) < n {
+		var selected = -1
+		for i := 0; i < n; i++ {
+			if ordered[i] {
+				continue
+			}
+			if selected == -1 || connections[i] > connections[selected] ||
+				(connections[i] == connections[selected] && len(pattern.neighbors[i]) > len(pattern.neighbors[selected])) {
+				selected = i
+			}
+		}
+		ordered[selected] = true
+		order = append(order, selected)
+		for _, v := range pattern.neighbors[selected] {
+			connections[v]++
+		}
+	}
+	return order
+}
+
+// compatible compares the multiplicity of edges between a pair of pattern nodes with that
+// between the target nodes they map to.
+func (state *vf2State) compatible(patternCount int, targetCount int) bool {
+	if state.exact {
+		return patternCount == targetCount
+	}
+	return targetCount >= patternCount
+}
+
+// edgesMatch applies the edge matcher to the edges from pattern node a to b, if there are any
+func (state *vf2State) edgesMatch(a int, b int, c int, d int) bool {
+	if state.options.EdgeMatch == nil || state.pattern.counts[[2]int{a, b}] == 0 {
+		return true
+	}
+	return state.options.EdgeMatch(state.pattern.nodes[a], state.pattern.nodes[b], state.target.nodes[c], state.target.nodes[d])
+}
+
+// feasible reports whether pattern node u can be mapped to target node v given the mapping so far
+func (state *vf2State) feasible(u int, v int) bool {
+	var pattern, target = state.pattern, state.target
+	if state.degrees {
+		if pattern.inDegree[u] != target.inDegree[v] || pattern.outDegree[u] != target.outDegree[v] {
+			return false
+		}
+	} else if pattern.inDegree[u] > target.inDegree[v] || pattern.outDegree[u] > target.outDegree[v] {
+		return false
+	}
+	if state.options.NodeMatch != nil && !state.options.NodeMatch(pattern.nodes[u], target.nodes[v]) {
+		return false
+	}
+	if !state.compatible(pattern.counts[[2]int{u, u}], target.counts[[2]int{v, v}]) || !state.edgesMatch(u, u, v, v) {
+		return false
+	}
+
+	for _, w := range pattern.neighbors[u] {
+		var x = state.core[w]
+		if x == -1 {
+			continue
+		}
+		if !state.compatible(pattern.counts[[2]int{w, u}], target.counts[[2]int{x, v}]) ||
+			!state.compatible(pattern.counts[[2]int{u, w}], target.counts[[2]int{v, x}]) {
+			return false
+		}
+		if !state.edgesMatch(w, u, x, v) || !state.edgesMatch(u, w, v, x) {
+			return false
+		}
+	}
+	if state.exact {
+		// Mapped target neighbors must have counterparts among the pattern neighbors
+		for _, x := range target.neighbors[v] {
+			var w = state.reverse[x]
+			if w == -1 {
+				continue
+			}
+			if pattern.counts[[2]int{w, u}] != target.counts[[2]int{x, v}] ||
+				pattern.counts[[2]int{u, w}] != target.counts[[2]int{v, x}] {
+				return false
+			}
+		}
+	}
+	return state.lookAhead(pattern.parents[u], target.parents[v]) && state.lookAhead(pattern.children[u], target.children[v])
+}
+
+// terminalCounts counts the unmapped nodes among some neighbors of a node that are in the in-
+// and out-terminal sets, in neither, and in all
+func terminalCounts(neighbors []int, mapped []int, in []int, out []int) [4]int {
+	var counts [4]int
+	for _, w := range neighbors {
+		if mapped[w] != -1 {
+			continue
+		}
+		if in[w] != 0 {
+			counts[0]++
+		}
+		if out[w] != 0 {
+			counts[1]++
+		}
+		if in[w] == 0 && out[w] == 0 {
+			counts[2]++
+		}
+		counts[3]++
+	}
+	return counts
+}
+
+// lookAhead applies the terminal set rules of VF2 to the parents, or the children, of a pattern
+// node and of the target node it would map to. The unmapped pattern neighbors in a terminal set
+// must map to target neighbors in the same set, and when non-adjacent nodes must stay so, those
+// in neither set to target neighbors in neither; otherwise they only need unmapped target
+// neighbors. An isomorphism needs the counts to be equal.
+func (state *vf2State) lookAhead(patternNeighbors []int, targetNeighbors []int) bool {
+	var p = terminalCounts(patternNeighbors, state.core, state.patternIn, state.patternOut)
+	var t = terminalCounts(targetNeighbors, state.reverse, state.targetIn, state.targetOut)
+	if state.degrees {
+		return p == t
+	}
+	if p[0] > t[0] || p[1] > t[1] {
+		return false
+	}
+	if state.exact {
+		return p[2] <= t[2]
+	}
+	return p[3] <= t[3]
+}
+
+// extend marks the terminal sets of both graphs as pattern node u is mapped to target node v
+// at the given depth, and unmark undoes it
+func (state *vf2State) extend(u int, v int, depth int) {
+	var mark = func(marks []int, nodes []int, self int) {
+		if marks[self] == 0 {
+			marks[self] = depth
+		}
+		for _, w := range nodes {
+			if marks[w] == 0 {
+				marks[w] = depth
+			}
+		}
+	}
+	mark(state.patternIn, state.pattern.parents[u], u)
+	mark(state.patternOut, state.pattern.children[u], u)
+	mark(state.targetIn, state.target.parents[v], v)
+	mark(state.targetOut, state.target.children[v], v)
+}
+
+func (state *vf2State) unmark(u int, v int, depth int) {
+	var unmark = func(marks []int, nodes []int, self int) {
+		if marks[self] == depth {
+			marks[self] = 0
+		}
+		for _, w := range nodes {
+			if marks[w] == depth {
+				marks[w] = 0
+			}
+		}
+	}
+	unmark(state.patternIn, state.pattern.parents[u], u)
+	unmark(state.patternOut, state.pattern.children[u], u)
+	unmark(state.targetIn, state.target.parents[v], v)
+	unmark(state.targetOut, state.target.children[v], v)
+}
+
+// candidates lists the target nodes a pattern node may be mapped to: the unmapped neighbors
+// of the image of a mapped pattern neighbor, or every unmapped target node if there is none.
+func (state *vf2State) candidates(u int) []int {
+	var result []int
+	for _, w := range state.pattern.neighbors[u] {
+		if state.core[w] == -1 {
+			continue
+		}
+		for _, x := range state.target.neighbors[state.core[w]] {
+			if state.reverse[x] == -1 {
+				result = append(result, x)
+			}
+		}
+		return result
+	}
+	for x, w := range state.reverse {
+		if w == -1 {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// match extends the mapping from the depth-th pattern node of the order, recording complete
+// mappings, and reports whether the search should go on.
+func (state *vf2State) match(depth int) bool {
+	if depth == len(state.order) {
+		var mapping = make(map[string]string, len(state.order))
+		for u, v := range state.core {
+			mapping[state.pattern.nodes[u].ID] = state.target.nodes[v].ID
+		}
+		state.mappings = append(state.mappings, mapping)
+		return state.options.Limit <= 0 || len(state.mappings) < state.options.Limit
+	}
+	var u = state.order[depth]
+	for _, v := range state.candidates(u) {
+		if !state.feasible(u, v) {
+			continue
+		}
+		state.core[u], state.reverse[v] = v, u
+		state.extend(u, v, depth+1)
+		var proceed = state.match(depth + 1)
+		state.unmark(u, v, depth+1)
+		state.core[u], state.reverse[v] = -1, -1
+		if !proceed {
+			return false
+		}
+	}
+	return true
+}
+
+func newVF2State(pattern DirectedGraph, target DirectedGraph, options IsomorphismOptions) *vf2State {
+	var state = &vf2State{
+		pattern: newVF2Graph(pattern),
+		target:  newVF2Graph(target),
+		options: options,
+	}
+	state.order = matchingOrder(state.pattern)
+	state.core = make([]int, len(pattern.DirectedNodes))
+	state.reverse = make([]int, len(target.DirectedNodes))
+	state.patternIn, state.patternOut = make([]int, len(pattern.DirectedNodes)), make([]int, len(pattern.DirectedNodes))
+	state.targetIn, state.targetOut = make([]int, len(target.DirectedNodes)), make([]int, len(target.DirectedNodes))
+	for i := range state.core {
+		state.core[i] = -1
+	}
+	for i := range state.reverse {
+		state.reverse[i] = -1
+	}
+	return state
+}
+
+// sortedDegrees returns the in- and out-degree pairs of every node in sorted order
+func sortedDegrees(structure *vf2Graph) [][2]int {
+	var degrees = make([][2]int, len(structure.nodes))
+	for i := range degrees {
+		degrees[i] = [2]int{structure.inDegree[i], structure.outDegree[i]}
+	}
+	sort.Slice(degrees, func(a, b int) bool {
+		return degrees[a][0] < degrees[b][0] || (degrees[a][0] == degrees[b][0] && degrees[a][1] < degrees[b][1])
+	})
+	return degrees
+}
+
+// FindIsomorphism searches for a one-to-one mapping between the nodes of two directed graphs
+// that preserves every edge, regardless of node IDs, using VF2 with the node ordering of
+// VF2++. The mapping is keyed by the IDs of the first graph's nodes. Node and edge matchers in
+// the options further restrict which nodes and edges may correspond.
+func FindIsomorphism(a DirectedGraph, b DirectedGraph, options IsomorphismOptions) (map[string]string, bool) {
+	if len(a.DirectedNodes) != len(b.DirectedNodes) {
+		return nil, false
+	}
+	var state = newVF2State(a, b, options)
+	state.exact, state.degrees = true, true
+	state.options.Limit = 1
+	var degreesA, degreesB = sortedDegrees(state.pattern), sortedDegrees(state.target)
+	for i := range degreesA {
+		if degreesA[i] != degreesB[i] {
+			return nil, false
+		}
+	}
+	state.match(0)
+	if len(state.mappings) == 0 {
+		return nil, false
+	}
+	return state.mappings[0], true
+}
+
+// FindSubgraphIsomorphisms searches for the ways a pattern graph occurs within a directed
+// graph, using VF2 with the node ordering of VF2++. Each mapping sends the pattern's node IDs
+// to distinct node IDs of the graph such that every pattern edge maps onto a graph edge; with
+// the Induced option, graph edges between mapped nodes must also exist in the pattern.
+func FindSubgraphIsomorphisms(graph DirectedGraph, pattern DirectedGraph, options IsomorphismOptions) []map[string]string {
+	if len(pattern.DirectedNodes) > len(graph.DirectedNodes) {
+		return nil
+	}
+	var state = newVF2State(pattern, graph, options)
+	state.exact = options.Induced
+	state.match(0)
+	return state.mappings
+}
@@ -0,0 +1,102 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// expectEdgePreservingMapping checks that every edge of the first graph maps onto an edge of the second
+func expectEdgePreservingMapping(a DirectedGraph, b DirectedGraph, mapping map[string]string, t *testing.T) {
+	for _, parent := range a.DirectedNodes {
+		for _, child := range parent.Children {
+			var index, _ = FindDirectedNode(b, mapping[parent.ID])
+			var found = false
+			for _, mappedChild := range b.DirectedNodes[index].Children {
+				found = found || mappedChild.ID == mapping[child.ID]
+			}
+			if !found {
+				t.Errorf("Failed: expected edge %s -> %s to be preserved", parent.ID, child.ID)
+			}
+		}
+	}
+}
+
+func TestFindIsomorphism(t *testing.T) {
+	describe("FindIsomorphism", t)
+
+	context("the graphs have the same structure under different IDs and node order", t)
+	//  0 -> 1 -> 2 -> 0,  2 -> 3
+	var a = createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}})
+	// The same graph with its nodes listed in reverse: 3 -> 2 -> 1 -> 3,  1 -> 0
+	var b = createWeightedDirectedGraph(4, [][3]float64{{3, 2, 1}, {2, 1, 1}, {1, 3, 1}, {1, 0, 1}})
+	var mapping, found = FindIsomorphism(a, b, IsomorphismOptions{})
+
+	it("returns a mapping that preserves every edge", t)
+	expectEqualBools(found, true, t)
+	expectEqualInts(len(mapping), 4, t)
+	expectEdgePreservingMapping(a, b, mapping, t)
+	expectEqualStrings(mapping[a.DirectedNodes[3].ID], b.DirectedNodes[0].ID, t)
+
+	context("node values must match", t)
+	a.DirectedNodes[0].Values["color"] = "red"
+	b.DirectedNodes[3].Values["color"] = "red"
+	mapping, found = FindIsomorphism(a, b, IsomorphismOptions{NodeMatch: MatchValues("color")})
+
+	it("maps nodes onto nodes of the same value", t)
+	expectEqualBools(found, true, t)
+	expectEqualStrings(mapping[a.DirectedNodes[0].ID], b.DirectedNodes[3].ID, t)
+
+	context("no mapping satisfies the node values", t)
+	b.DirectedNodes[3].Values["color"] = "blue"
+	_, found = FindIsomorphism(a, b, IsomorphismOptions{NodeMatch: MatchValues("color")})
+
+	it("reports that the graphs are not isomorphic", t)
+	expectEqualBools(found, false, t)
+
+	context("edge labels must match", t)
+	SetDirectedEdgeValue(a.DirectedNodes[2], a.DirectedNodes[3], "label", "calls")
+	SetDirectedEdgeValue(b.DirectedNodes[3], b.DirectedNodes[2], "label", "calls")
+	_, found = FindIsomorphism(a, b, IsomorphismOptions{EdgeMatch: MatchEdgeValues("label")})
+
+	it("rejects mappings of differently labeled edges", t)
+	expectEqualBools(found, false, t)
+
+	context("the graphs differ in structure", t)
+	var path = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	var star = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}})
+	_, found = FindIsomorphism(path, star, IsomorphismOptions{})
+
+	it("reports that the graphs are not isomorphic", t)
+	expectEqualBools(found, false, t)
+}
+
+func TestFindSubgraphIsomorphisms(t *testing.T) {
+	describe("FindSubgraphIsomorphisms", t)
+	// Two directed triangles sharing node 2, with a shortcut 0 -> 2
+	var graph = createWeightedDirectedGraph(5, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}, {3, 4, 1}, {4, 2, 1},
+	})
+	var cycle = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+
+	context("the pattern is a directed cycle", t)
+	var mappings = FindSubgraphIsomorphisms(graph, cycle, IsomorphismOptions{})
+
+	it("finds every rotation of every occurrence", t)
+	expectEqualInts(len(mappings), 6, t)
+	for _, mapping := range mappings {
+		expectEdgePreservingMapping(cycle, graph, mapping, t)
+	}
+
+	context("the number of mappings is limited", t)
+	it("stops searching at the limit", t)
+	expectEqualInts(len(FindSubgraphIsomorphisms(graph, cycle, IsomorphismOptions{Limit: 2})), 2, t)
+
+	context("the pattern is a path", t)
+	var triangle = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {0, 2, 1}})
+	var path = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+
+	it("finds the path within a transitive triangle", t)
+	expectEqualInts(len(FindSubgraphIsomorphisms(triangle, path, IsomorphismOptions{})), 1, t)
+
+	it("does not find it as an induced subgraph", t)
+	expectEqualInts(len(FindSubgraphIsomorphisms(triangle, path, IsomorphismOptions{Induced: true})), 0, t)
+}
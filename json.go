@@ -0,0 +1,400 @@
+package gograph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonNode is a node of the node-link JSON schema
+type jsonNode struct {
+	ID     string            `json:"id"`
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// jsonEdge is an edge of the node-link JSON schema, naming its nodes by ID
+type jsonEdge struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// jsonGraph is the node-link JSON schema of directed and undirected graphs
+type jsonGraph struct {
+	Directed *bool      `json:"directed"`
+	Root     *string    `json:"root,omitempty"`
+	Nodes    []jsonNode `json:"nodes"`
+	Edges    []jsonEdge `json:"edges"`
+}
+
+// MarshalJSON encodes a directed graph in a node-link schema, which names nodes by ID instead
+// of following the cycles of pointers between parents and children:
+//
+//	{"directed": true, "root": "a",
+//	 "nodes": [{"id": "a", "values": {"color": "red"}}, {"id": "b"}],
+//	 "edges": [{"from": "a", "to": "b", "values": {"weight": "2"}}]}
+//
+// Nodes are listed in order, and edges in the order of DirectedEdgeList, so that the encoding
+// of a graph is stable. Empty values are left out. Merkle hashing is not encoded.
+func (graph DirectedGraph) MarshalJSON() ([]byte, error) {
+	return json.Marshal(directedNodeLink(graph))
+}
+
+// MarshalJSON encodes an undirected graph in the node-link schema of DirectedGraph.MarshalJSON,
+// with "directed" false, no root, and every edge listed once in the order of EdgeList
+func (graph Graph) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeLink(graph))
+}
+
+// directedNodeLink lists the nodes and edges of a directed graph by ID
+func directedNodeLink(graph DirectedGraph) jsonGraph {
+	var indices = indexDirectedNodes(graph)
+	var directed = true
+	var encoded = jsonGraph{Directed: &directed, Nodes: make([]jsonNode, len(graph.DirectedNodes)), Edges: []jsonEdge{}}
+	if graph.RootDirectedNode != nil {
+		encoded.Root = &graph.RootDirectedNode.ID
+	}
+	for index, node := range graph.DirectedNodes {
+		encoded.Nodes[index] = jsonNode{ID: node.ID, Values: node.Values}
+		for _, child := range node.Children {
+			if _, ok := indices[child]; ok {
+				encoded.Edges = append(encoded.Edges, jsonEdge{From: node.ID, To: child.ID, Values: node.EdgeValues[child.ID]})
+			}
+		}
+	}
+	return encoded
+}
+
+// nodeLink lists the nodes and edges of an undirected graph by ID, every edge once
+func nodeLink(graph Graph) jsonGraph {
+	var indices = indexNodes(graph)
+	var directed = false
+	var encoded = jsonGraph{Directed: &directed, Nodes: make([]jsonNode, len(graph.Nodes)), Edges: []jsonEdge{}}
+	for i, node := range graph.Nodes {
+		encoded.Nodes[i] = jsonNode{ID: node.ID, Values: node.Values}
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i <= j {
+				encoded.Edges = append(encoded.Edges, jsonEdge{From: node.ID, To: neighbor.ID, Values: node.EdgeValues[neighbor.ID]})
+			}
+		}
+	}
+	return encoded
+}
+
+// decodedGraph is a graph read from a serialization, with its nodes and edges by position
+type decodedGraph struct {
+	directed   bool
+	root       *string
+	IDs        []string
+	values     []map[string]string
+	edges      [][2]int
+	edgeValues []map[string]string
+}
+
+// addNode adds a node, returning an error if its ID was already taken
+func (decoded *decodedGraph) addNode(ID string, values map[string]string, index map[string]int) error {
+	if _, ok := index[ID]; ok {
+		return fmt.Errorf("node ID %q appears more than once", ID)
+	}
+	index[ID] = len(decoded.IDs)
+	decoded.IDs = append(decoded.IDs, ID)
+	decoded.values = append(decoded.values, values)
+	return nil
+}
+
+// addEdge adds an edge, returning an error if either of its nodes is unknown
+func (decoded *decodedGraph) addEdge(from string, to string, values map[string]string, index map[string]int) error {
+	var i, fromOK = index[from]
+	var j, toOK = index[to]
+	if !fromOK || !toOK {
+		return fmt.Errorf("edge from %q to %q names a node that is not in the graph", from, to)
+	}
+	decoded.edges = append(decoded.edges, [2]int{i, j})
+	decoded.edgeValues = append(decoded.edgeValues, values)
+	return nil
+}
+
+// directedGraph builds a directed graph rooted at the decoded root, or without a root if none
+// was decoded
+func (decoded *decodedGraph) directedGraph() (DirectedGraph, error) {
+	var graph = createDirectedNodes(decoded.IDs, MatrixGraphOptions{Values: decoded.values})
+	graph.RootDirectedNode = nil
+	if decoded.root != nil {
+		var found = false
+		for _, node := range graph.DirectedNodes {
+			if node.ID == *decoded.root {
+				graph.RootDirectedNode, found = node, true
+				break
+			}
+		}
+		if !found {
+			return DirectedGraph{}, fmt.Errorf("root %q is not a node of the graph", *decoded.root)
+		}
+	}
+	for k, edge := range decoded.edges {
+		var parent, child = graph.DirectedNodes[edge[0]], graph.DirectedNodes[edge[1]]
+		graph, _, _ = CreateDirectedEdge(graph, parent, child)
+		for key, value := range decoded.edgeValues[k] {
+			SetDirectedEdgeValue(parent, child, key, value)
+		}
+	}
+	return graph, nil
+}
+
+// undirectedGraph builds an undirected graph, making directed edges undirected
+func (decoded *decodedGraph) undirectedGraph() Graph {
+	var graph = createNodes(decoded.IDs, MatrixGraphOptions{Values: decoded.values})
+	for k, edge := range decoded.edges {
+		var a, b = graph.Nodes[edge[0]], graph.Nodes[edge[1]]
+		graph, _, _ = CreateEdge(graph, a, b)
+		for key, value := range decoded.edgeValues[k] {
+			SetEdgeValue(a, b, key, value)
+		}
+	}
+	return graph
+}
+
+// decodeNodeLink reads the node-link JSON schema
+func decodeNodeLink(data []byte) (*decodedGraph, error) {
+	var encoded jsonGraph
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	var decoded = &decodedGraph{directed: encoded.Directed == nil || *encoded.Directed, root: encoded.Root}
+	var index = make(map[string]int, len(encoded.Nodes))
+	for _, node := range encoded.Nodes {
+		if err := decoded.addNode(node.ID, node.Values, index); err != nil {
+			return nil, err
+		}
+	}
+	for _, edge := range encoded.Edges {
+		if err := decoded.addEdge(edge.From, edge.To, edge.Values, index); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+// UnmarshalJSON decodes a directed graph from the node-link schema of MarshalJSON, replacing
+// the graph. A document without "directed" is taken to be directed, and one without a root
+// gives a graph without a root. An error is returned if the document is undirected, if node IDs
+// repeat, or if an edge or the root names a node that is not listed.
+func (graph *DirectedGraph) UnmarshalJSON(data []byte) error {
+	var decoded, err = decodeNodeLink(data)
+	if err != nil {
+		return err
+	}
+	if !decoded.directed {
+		return errors.New("JSON graph is undirected; decode it into a Graph")
+	}
+	result, err := decoded.directedGraph()
+	if err != nil {
+		return err
+	}
+	*graph = result
+	return nil
+}
+
+// UnmarshalJSON decodes an undirected graph from the node-link schema of MarshalJSON, replacing
+// the graph. The edges of a directed document become undirected edges and its root is ignored.
+// An error is returned if node IDs repeat or an edge names a node that is not listed.
+func (graph *Graph) UnmarshalJSON(data []byte) error {
+	var decoded, err = decodeNodeLink(data)
+	if err != nil {
+		return err
+	}
+	*graph = decoded.undirectedGraph()
+	return nil
+}
+
+// jgfEdge is an edge of the JSON Graph Format
+type jgfEdge struct {
+	Source   string                     `json:"source"`
+	Target   string                     `json:"target"`
+	Label    *string                    `json:"label,omitempty"`
+	Metadata map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// jgfNode is a node of the JSON Graph Format, whose ID is its key in the graph's nodes
+type jgfNode struct {
+	Label    *string                    `json:"label,omitempty"`
+	Metadata map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// jgfMetadata splits values into the label of a node or edge and the rest of its metadata
+func jgfMetadata(values map[string]string) (*string, map[string]string) {
+	var label, ok = values["label"]
+	if !ok {
+		return nil, values
+	}
+	var metadata = map[string]string{}
+	for key, value := range values {
+		if key != "label" {
+			metadata[key] = value
+		}
+	}
+	return &label, metadata
+}
+
+// writeJGF writes a graph in the JSON Graph Format, keeping its nodes in order
+func writeJGF(writer io.Writer, directed bool, root *string, nodes []jsonNode, edges []jsonEdge) error {
+	var buffered = bufio.NewWriter(writer)
+	var encode = func(value interface{}) {
+		var encoded, _ = json.Marshal(value)
+		buffered.Write(encoded)
+	}
+	buffered.WriteString(`{"graph":{"directed":`)
+	encode(directed)
+	if root != nil {
+		buffered.WriteString(`,"metadata":`)
+		encode(map[string]string{"root": *root})
+	}
+	buffered.WriteString(`,"nodes":{`)
+	for index, node := range nodes {
+		if index > 0 {
+			buffered.WriteString(",")
+		}
+		encode(node.ID)
+		buffered.WriteString(":")
+		var label, metadata = jgfMetadata(node.Values)
+		encode(struct {
+			Label    *string           `json:"label,omitempty"`
+			Metadata map[string]string `json:"metadata,omitempty"`
+		}{label, metadata})
+	}
+	buffered.WriteString(`},"edges":[`)
+	for index, edge := range edges {
+		if index > 0 {
+			buffered.WriteString(",")
+		}
+		var label, metadata = jgfMetadata(edge.Values)
+		encode(struct {
+			Source   string            `json:"source"`
+			Target   string            `json:"target"`
+			Label    *string           `json:"label,omitempty"`
+			Metadata map[string]string `json:"metadata,omitempty"`
+		}{edge.From, edge.To, label, metadata})
+	}
+	buffered.WriteString("]}}\n")
+	return buffered.Flush()
+}
+
+// WriteJGF writes a directed graph as a document of the JSON Graph Format, version 2. Nodes are
+// keyed by ID in order, with their Values as metadata, and edges carry their values as
+// metadata; a "label" value becomes the label of its node or edge. The root is recorded in the
+// graph's metadata. ReadJGF gives back the graph.
+func WriteJGF(writer io.Writer, graph DirectedGraph) error {
+	var encoded = directedNodeLink(graph)
+	return writeJGF(writer, true, encoded.Root, encoded.Nodes, encoded.Edges)
+}
+
+// WriteUndirectedJGF writes an undirected graph as a document of the JSON Graph Format, like
+// WriteJGF, with every edge listed once
+func WriteUndirectedJGF(writer io.Writer, graph Graph) error {
+	var encoded = nodeLink(graph)
+	return writeJGF(writer, false, nil, encoded.Nodes, encoded.Edges)
+}
+
+// jgfValues merges the label and metadata of a node or edge into values. Metadata that is not a
+// string is kept as its JSON text.
+func jgfValues(label *string, metadata map[string]json.RawMessage) map[string]string {
+	if label == nil && len(metadata) == 0 {
+		return nil
+	}
+	var values = make(map[string]string, len(metadata)+1)
+	for key, raw := range metadata {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			text = string(raw)
+		}
+		values[key] = text
+	}
+	if label != nil {
+		values["label"] = *label
+	}
+	return values
+}
+
+// decodeJGF reads a document of the JSON Graph Format, reading the nodes object one key at a
+// time so that the nodes keep their order
+func decodeJGF(reader io.Reader) (*decodedGraph, error) {
+	var document struct {
+		Graph *struct {
+			Directed *bool                      `json:"directed"`
+			Metadata map[string]json.RawMessage `json:"metadata"`
+			Nodes    json.RawMessage            `json:"nodes"`
+			Edges    []jgfEdge                  `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, err
+	}
+	if document.Graph == nil {
+		return nil, errors.New("JSON Graph Format document has no \"graph\"")
+	}
+	var decoded = &decodedGraph{directed: document.Graph.Directed == nil || *document.Graph.Directed}
+	if raw, ok := document.Graph.Metadata["root"]; ok {
+		var root string
+		if err := json.Unmarshal(raw, &root); err != nil {
+			return nil, errors.New("JSON Graph Format root must be a node ID")
+		}
+		decoded.root = &root
+	}
+
+	var index = map[string]int{}
+	if len(document.Graph.Nodes) > 0 && string(document.Graph.Nodes) != "null" {
+		var nodes = json.NewDecoder(bytes.NewReader(document.Graph.Nodes))
+		if token, err := nodes.Token(); err != nil || token != json.Delim('{') {
+			return nil, errors.New("JSON Graph Format nodes must be an object keyed by node ID")
+		}
+		for nodes.More() {
+			var token, err = nodes.Token()
+			if err != nil {
+				return nil, err
+			}
+			var node jgfNode
+			if err := nodes.Decode(&node); err != nil {
+				return nil, err
+			}
+			if err := decoded.addNode(token.(string), jgfValues(node.Label, node.Metadata), index); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, edge := range document.Graph.Edges {
+		if err := decoded.addEdge(edge.Source, edge.Target, jgfValues(edge.Label, edge.Metadata), index); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+// ReadJGF builds a directed graph from a document of the JSON Graph Format, version 2, such as
+// one written by WriteJGF. Nodes keep the order of their keys, and node and edge metadata
+// become values, with labels under "label"; metadata that is not a string is kept as its JSON
+// text. The node named by the root of the graph's metadata becomes the root; without one, the
+// graph has no root. An error is returned if the document is malformed or undirected, if node IDs repeat,
+// or if an edge or the root names a node that is not listed.
+func ReadJGF(reader io.Reader) (DirectedGraph, error) {
+	var decoded, err = decodeJGF(reader)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	if !decoded.directed {
+		return DirectedGraph{}, errors.New("JSON Graph Format graph is undirected; read it with ReadUndirectedJGF")
+	}
+	return decoded.directedGraph()
+}
+
+// ReadUndirectedJGF builds an undirected graph from a document of the JSON Graph Format, like
+// ReadJGF. The edges of a directed graph become undirected edges.
+func ReadUndirectedJGF(reader io.Reader) (Graph, error) {
+	var decoded, err = decodeJGF(reader)
+	if err != nil {
+		return Graph{}, err
+	}
+	return decoded.undirectedGraph(), nil
+}
@@ -0,0 +1,175 @@
+package gograph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDirectedGraphMarshalJSON(t *testing.T) {
+	describe("DirectedGraph.MarshalJSON", t)
+	var graph = createDOTTestGraph()
+
+	it("lists the nodes and edges by ID with the root", t)
+	var data, err = json.Marshal(graph)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(string(data), `{"directed":true,"root":"a",`+
+		`"nodes":[{"id":"a","values":{"color":"red","group":"left"}},{"id":"b","values":{"group":"left"}},{"id":"c"}],`+
+		`"edges":[{"from":"a","to":"b","values":{"weight":"2.5"}},{"from":"a","to":"c"},{"from":"b","to":"c"}]}`, t)
+
+	it("encodes a graph through a pointer too", t)
+	var pointerData, _ = json.Marshal(&graph)
+	expectEqualStrings(string(pointerData), string(data), t)
+
+	it("encodes an empty graph with empty lists", t)
+	data, _ = json.Marshal(CreateGraph())
+	expectEqualStrings(string(data), `{"directed":true,"nodes":[],"edges":[]}`, t)
+}
+
+func TestDirectedGraphUnmarshalJSON(t *testing.T) {
+	describe("DirectedGraph.UnmarshalJSON", t)
+
+	it("gives back a graph encoded by MarshalJSON", t)
+	var graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 0.5}, {2, 0, 3}, {3, 4, 1}, {3, 4, 2}, {4, 4, 1}})
+	graph.RootDirectedNode = graph.DirectedNodes[2]
+	var data, _ = json.Marshal(graph)
+	var decoded DirectedGraph
+	var err = json.Unmarshal(data, &decoded)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(decoded), describeDirectedGraph(graph), t)
+	expectEqualBools(decoded.DirectedNodes[2].Parents[0] == decoded.DirectedNodes[1], true, t)
+
+	it("gives back a graph without a root", t)
+	graph.RootDirectedNode = nil
+	data, _ = json.Marshal(graph)
+	err = json.Unmarshal(data, &decoded)
+	expectEqualBools(err == nil, true, t)
+	expectEqualBools(decoded.RootDirectedNode == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(decoded), describeDirectedGraph(graph), t)
+
+	it("leaves a document without a root unrooted", t)
+	json.Unmarshal([]byte(`{"nodes":[{"id":"x"},{"id":"y"}],"edges":[{"from":"y","to":"x"}]}`), &decoded)
+	expectEqualBools(decoded.RootDirectedNode == nil, true, t)
+	expectEqualStrings(decoded.DirectedNodes[1].Children[0].ID, "x", t)
+
+	context("the document is not a valid graph", t)
+
+	it("returns an error", t)
+	for _, document := range []string{
+		`{"directed":false,"nodes":[],"edges":[]}`,
+		`{"nodes":[{"id":"x"},{"id":"x"}]}`,
+		`{"nodes":[{"id":"x"}],"edges":[{"from":"x","to":"y"}]}`,
+		`{"root":"y","nodes":[{"id":"x"}]}`,
+		`{"nodes":{}}`,
+	} {
+		expectEqualBools(json.Unmarshal([]byte(document), &decoded) == nil, false, t)
+	}
+}
+
+func TestGraphMarshalJSON(t *testing.T) {
+	describe("Graph.MarshalJSON", t)
+	var graph = createNodes([]string{"x", "y"}, MatrixGraphOptions{})
+	graph, _, _ = CreateEdge(graph, graph.Nodes[0], graph.Nodes[1])
+	graph, _, _ = CreateEdge(graph, graph.Nodes[1], graph.Nodes[1])
+	SetEdgeValue(graph.Nodes[0], graph.Nodes[1], WeightKey, "3")
+
+	it("lists every edge once", t)
+	var data, err = json.Marshal(graph)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(string(data), `{"directed":false,"nodes":[{"id":"x"},{"id":"y"}],`+
+		`"edges":[{"from":"x","to":"y","values":{"weight":"3"}},{"from":"y","to":"y"}]}`, t)
+}
+
+func TestGraphUnmarshalJSON(t *testing.T) {
+	describe("Graph.UnmarshalJSON", t)
+
+	it("gives back a graph encoded by MarshalJSON", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}, {0, 1, 4}})
+	graph.Nodes[3].Values = map[string]string{"color": "blue"}
+	var data, _ = json.Marshal(graph)
+	var decoded Graph
+	var err = json.Unmarshal(data, &decoded)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(decoded), describeGraph(graph), t)
+
+	it("makes the edges of a directed document undirected", t)
+	data, _ = json.Marshal(createDOTTestGraph())
+	json.Unmarshal(data, &decoded)
+	expectEqualInts(len(decoded.Nodes[2].Edges), 2, t)
+	expectEqualFloats(EdgeWeight(decoded.Nodes[1], decoded.Nodes[0]), 2.5, t)
+}
+
+func TestWriteJGF(t *testing.T) {
+	describe("WriteJGF", t)
+	var graph = createDOTTestGraph()
+	graph.DirectedNodes[2].Values = map[string]string{"label": "Sink"}
+
+	it("writes a JSON Graph Format document keyed by node ID in order", t)
+	var buffer bytes.Buffer
+	var err = WriteJGF(&buffer, graph)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), `{"graph":{"directed":true,"metadata":{"root":"a"},"nodes":{`+
+		`"a":{"metadata":{"color":"red","group":"left"}},"b":{"metadata":{"group":"left"}},"c":{"label":"Sink"}},`+
+		`"edges":[{"source":"a","target":"b","metadata":{"weight":"2.5"}},{"source":"a","target":"c"},{"source":"b","target":"c"}]}}`+"\n", t)
+}
+
+func TestReadJGF(t *testing.T) {
+	describe("ReadJGF", t)
+
+	it("gives back a graph written by WriteJGF", t)
+	var graph = createWeightedDirectedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 0.5}, {2, 0, 3}, {3, 4, 1}, {3, 4, 2}, {4, 4, 1}})
+	graph.DirectedNodes[4].Values["label"] = "last"
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[1], "label", "first")
+	graph.RootDirectedNode = graph.DirectedNodes[4]
+	var buffer bytes.Buffer
+	WriteJGF(&buffer, graph)
+	var read, err = ReadJGF(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("gives back a graph without a root", t)
+	graph.RootDirectedNode = nil
+	buffer.Reset()
+	WriteJGF(&buffer, graph)
+	read, err = ReadJGF(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualBools(read.RootDirectedNode == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(graph), t)
+
+	it("keeps metadata that is not a string as JSON text", t)
+	read, err = ReadJGF(strings.NewReader(`{"graph":{"nodes":{"z":{"metadata":{"size":3,"tags":["a"]}},"y":{}},` +
+		`"edges":[{"source":"z","target":"y","relation":"uses","metadata":{"weight":0.5}}]}}`))
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:\n"+
+		`z map[size:3 tags:["a"]] -> [y] map[y:map[weight:0.5]]`+"\n"+
+		"y map[] -> [] map[]", t)
+
+	context("the document is not a valid graph", t)
+
+	it("returns an error", t)
+	for _, document := range []string{
+		`{"graph":{"directed":false,"nodes":{}}}`,
+		`{"graphs":[]}`,
+		`{"graph":{"nodes":[{"id":"x"}]}}`,
+		`{"graph":{"nodes":{"x":{},"x":{}}}}`,
+		`{"graph":{"nodes":{"x":{}},"edges":[{"source":"x","target":"y"}]}}`,
+		`{"graph":{"metadata":{"root":1},"nodes":{"x":{}}}}`,
+	} {
+		_, err = ReadJGF(strings.NewReader(document))
+		expectEqualBools(err == nil, false, t)
+	}
+}
+
+func TestReadUndirectedJGF(t *testing.T) {
+	describe("ReadUndirectedJGF", t)
+
+	it("gives back a graph written by WriteUndirectedJGF", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}})
+	var buffer bytes.Buffer
+	WriteUndirectedJGF(&buffer, graph)
+	expectEqualBools(strings.HasPrefix(buffer.String(), `{"graph":{"directed":false,"nodes":{`), true, t)
+	var read, err = ReadUndirectedJGF(&buffer)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(read), describeGraph(graph), t)
+}
@@ -0,0 +1,165 @@
+package gograph
+
+import (
+	"math"
+)
+
+// The matrices below all index their rows and columns by the position of each node in the
+// graph's DirectedNodes or Nodes. Parallel edges add up, so that an entry of an adjacency
+// matrix counts the edges between two nodes. For directed graphs, degrees count the edges
+// leaving a node, in keeping with CreateAdjecencyMatrix, whose rows are parents.
+
+// edgeCounts returns the number of edges from every node to every other node of a directed graph
+func edgeCounts(graph DirectedGraph) Matrix {
+	var n = len(graph.DirectedNodes)
+	var counts = make(Matrix, n)
+	for i := range counts {
+		counts[i] = make([]int, n)
+	}
+	for i, list := range childLists(graph) {
+		for _, j := range list {
+			counts[i][j]++
+		}
+	}
+	return counts
+}
+
+// undirectedEdgeCounts returns the number of edges between every pair of nodes of an
+// undirected graph. A self-loop counts twice, once for each of its ends.
+func undirectedEdgeCounts(graph Graph) Matrix {
+	var indices = indexNodes(graph)
+	var n = len(graph.Nodes)
+	var counts = make(Matrix, n)
+	for i := range counts {
+		counts[i] = make([]int, n)
+	}
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok {
+				counts[i][j]++
+				if i == j {
+					counts[i][j]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// diagonalOfRowSums returns the diagonal matrix of the row sums of a matrix
+func diagonalOfRowSums(counts Matrix) Matrix {
+	var degrees = make(Matrix, len(counts))
+	for i, row := range counts {
+		degrees[i] = make([]int, len(counts))
+		for _, count := range row {
+			degrees[i][i] += count
+		}
+	}
+	return degrees
+}
+
+// combine returns the entrywise sum of a diagonal matrix and a multiple of another matrix
+func combine(degrees Matrix, counts Matrix, sign int) Matrix {
+	var result = make(Matrix, len(counts))
+	for i, row := range counts {
+		result[i] = make([]int, len(row))
+		for j, count := range row {
+			result[i][j] = degrees[i][j] + sign*count
+		}
+	}
+	return result
+}
+
+// CreateUndirectedAdjacencyMatrix returns the symmetric adjacency matrix of an undirected
+// graph, whose entries count the edges between every two nodes. A self-loop counts twice on
+// the diagonal, once for each of its ends, so that every row sums to the node's degree.
+func CreateUndirectedAdjacencyMatrix(graph Graph) Matrix {
+	return undirectedEdgeCounts(graph)
+}
+
+// CreateDegreeMatrix returns the diagonal matrix of the number of edges leaving every node of a
+// directed graph
+func CreateDegreeMatrix(graph DirectedGraph) Matrix {
+	return diagonalOfRowSums(edgeCounts(graph))
+}
+
+// CreateUndirectedDegreeMatrix returns the diagonal matrix of the degree of every node of an
+// undirected graph, in which a self-loop counts twice
+func CreateUndirectedDegreeMatrix(graph Graph) Matrix {
+	return diagonalOfRowSums(undirectedEdgeCounts(graph))
+}
+
+// CreateLaplacianMatrix returns the out-degree Laplacian matrix D - A of a directed graph, where
+// D is its degree matrix and A counts the edges from the row's node to the column's node.
+// Every row sums to zero.
+func CreateLaplacianMatrix(graph DirectedGraph) Matrix {
+	var counts = edgeCounts(graph)
+	return combine(diagonalOfRowSums(counts), counts, -1)
+}
+
+// CreateUndirectedLaplacianMatrix returns the Laplacian matrix D - A of an undirected graph,
+// where D is its degree matrix and A counts the edges between every two nodes. It is
+// symmetric, every row sums to zero, and it equals BBᵀ for the incidence matrix B of any
+// orientation of the graph.
+func CreateUndirectedLaplacianMatrix(graph Graph) Matrix {
+	var counts = undirectedEdgeCounts(graph)
+	return combine(diagonalOfRowSums(counts), counts, -1)
+}
+
+// CreateSignlessLaplacianMatrix returns the signless Laplacian matrix D + A of a directed graph,
+// with D and A as in CreateLaplacianMatrix
+func CreateSignlessLaplacianMatrix(graph DirectedGraph) Matrix {
+	var counts = edgeCounts(graph)
+	return combine(diagonalOfRowSums(counts), counts, 1)
+}
+
+// CreateUndirectedSignlessLaplacianMatrix returns the signless Laplacian matrix D + A of an
+// undirected graph, with D and A as in CreateUndirectedLaplacianMatrix. It equals BBᵀ for the
+// incidence matrix B given by CreateUndirectedIncidenceMatrix.
+func CreateUndirectedSignlessLaplacianMatrix(graph Graph) Matrix {
+	var counts = undirectedEdgeCounts(graph)
+	return combine(diagonalOfRowSums(counts), counts, 1)
+}
+
+// CreateNormalizedLaplacianMatrix returns the random-walk normalized Laplacian I - D⁻¹A of a
+// directed graph, with D and A as in CreateLaplacianMatrix. Its off-diagonal entries are the
+// negated probabilities of a random walk following each edge leaving a node. The rows of nodes
+// without children are all zeros.
+func CreateNormalizedLaplacianMatrix(graph DirectedGraph) FloatMatrix {
+	var counts = edgeCounts(graph)
+	var degrees = diagonalOfRowSums(counts)
+	var normalized = make(FloatMatrix, len(counts))
+	for i, row := range counts {
+		normalized[i] = make([]float64, len(row))
+		if degrees[i][i] == 0 {
+			continue
+		}
+		normalized[i][i] = 1
+		for j, count := range row {
+			normalized[i][j] -= float64(count) / float64(degrees[i][i])
+		}
+	}
+	return normalized
+}
+
+// CreateUndirectedNormalizedLaplacianMatrix returns the symmetric normalized Laplacian
+// I - D^(-1/2) A D^(-1/2) of an undirected graph, with D and A as in
+// CreateUndirectedLaplacianMatrix. The rows and columns of isolated nodes are all zeros.
+func CreateUndirectedNormalizedLaplacianMatrix(graph Graph) FloatMatrix {
+	var counts = undirectedEdgeCounts(graph)
+	var degrees = diagonalOfRowSums(counts)
+	var normalized = make(FloatMatrix, len(counts))
+	for i, row := range counts {
+		normalized[i] = make([]float64, len(row))
+		if degrees[i][i] == 0 {
+			continue
+		}
+		normalized[i][i] = 1
+		for j, count := range row {
+			if count > 0 {
+				normalized[i][j] -= float64(count) / math.Sqrt(float64(degrees[i][i]*degrees[j][j]))
+			}
+		}
+	}
+	return normalized
+}
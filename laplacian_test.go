@@ -0,0 +1,104 @@
+package gograph
+
+import (
+	"math"
+	"testing"
+)
+
+func expectEqualMatrices(matrix [][]int, expectation [][]int, t *testing.T) {
+	expectEqualInts(len(matrix), len(expectation), t)
+	for i := range expectation {
+		for j := range expectation[i] {
+			if matrix[i][j] != expectation[i][j] {
+				t.Errorf("Failed: expected %+v, but found %+v", expectation, matrix)
+				return
+			}
+		}
+	}
+}
+
+func TestCreateDegreeMatrix(t *testing.T) {
+	describe("CreateDegreeMatrix", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 2, 1}, {1, 2, 1}})
+
+	it("counts the edges leaving every node", t)
+	expectEqualMatrices(CreateDegreeMatrix(graph), [][]int{{2, 0, 0}, {0, 2, 0}, {0, 0, 0}}, t)
+
+	context("the graph is undirected", t)
+	var undirected = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 2, 1}})
+
+	it("counts self-loops twice", t)
+	expectEqualMatrices(CreateUndirectedDegreeMatrix(undirected), [][]int{{1, 0, 0}, {0, 2, 0}, {0, 0, 3}}, t)
+}
+
+func TestCreateLaplacianMatrix(t *testing.T) {
+	describe("CreateLaplacianMatrix", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 2, 1}, {1, 2, 1}})
+
+	it("subtracts the edges leaving every node from its out-degree", t)
+	expectEqualMatrices(CreateLaplacianMatrix(graph), [][]int{{2, -1, -1}, {0, 2, -2}, {0, 0, 0}}, t)
+
+	context("the graph is undirected", t)
+	var undirected = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}, {3, 3, 1}})
+	var laplacian = CreateUndirectedLaplacianMatrix(undirected)
+
+	it("equals the product of an oriented incidence matrix with its transpose", t)
+	var incidence = CreateIncidenceMatrix(createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}, {3, 3, 1}}))
+	for i := range laplacian {
+		for j := range laplacian {
+			var product = 0
+			for k := range incidence[i] {
+				product += incidence[i][k] * incidence[j][k]
+			}
+			expectEqualInts(laplacian[i][j], product, t)
+		}
+	}
+}
+
+func TestCreateSignlessLaplacianMatrix(t *testing.T) {
+	describe("CreateSignlessLaplacianMatrix", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 2, 1}})
+
+	it("adds the edges leaving every node to its out-degree", t)
+	expectEqualMatrices(CreateSignlessLaplacianMatrix(graph), [][]int{{2, 1, 1}, {0, 1, 1}, {0, 0, 0}}, t)
+
+	context("the graph is undirected", t)
+	var undirected = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 2, 1}})
+	var signless = CreateUndirectedSignlessLaplacianMatrix(undirected)
+
+	it("equals the product of the incidence matrix with its transpose", t)
+	var incidence = CreateUndirectedIncidenceMatrix(undirected)
+	for i := range signless {
+		for j := range signless {
+			var product = 0
+			for k := range incidence[i] {
+				product += incidence[i][k] * incidence[j][k]
+			}
+			expectEqualInts(signless[i][j], product, t)
+		}
+	}
+}
+
+func TestCreateNormalizedLaplacianMatrix(t *testing.T) {
+	describe("CreateNormalizedLaplacianMatrix", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 2, 1}})
+	var normalized = CreateNormalizedLaplacianMatrix(graph)
+
+	it("divides every row by the node's out-degree", t)
+	expectEqualFloats(normalized[0][0], 1, t)
+	expectEqualFloats(normalized[0][1], -0.5, t)
+	expectEqualFloats(normalized[1][2], -1, t)
+
+	it("leaves the rows of nodes without children zero", t)
+	expectEqualFloats(normalized[2][2], 0, t)
+
+	context("the graph is undirected", t)
+	var undirected = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	normalized = CreateUndirectedNormalizedLaplacianMatrix(undirected)
+
+	it("scales every entry by the degrees of both nodes", t)
+	expectEqualFloats(normalized[0][0], 1, t)
+	expectEqualFloats(normalized[0][1], -1/math.Sqrt(2), t)
+	expectEqualFloats(normalized[1][0], -1/math.Sqrt(2), t)
+	expectEqualFloats(normalized[3][3], 0, t)
+}
@@ -0,0 +1,827 @@
+package gograph
+
+import (
+	"errors"
+	"math"
+)
+
+// adjacencyLists returns, for every node of a graph, the indices of its neighbors.
+// Self-loops and edges to nodes outside of the graph are left out.
+func adjacencyLists(graph Graph) [][]int {
+	var indices = indexNodes(graph)
+	var adjacency = make([][]int, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok && i != j {
+				adjacency[i] = append(adjacency[i], j)
+			}
+		}
+	}
+	return adjacency
+}
+
+// IsBipartite reports whether an undirected graph is bipartite. If it is, a two-coloring is
+// returned that maps every node ID to 0 or 1 such that no edge joins two nodes of the same
+// color. Otherwise an odd cycle is returned as proof, listed in traversal order; a self-loop is
+// a cycle of the one node it joins.
+func IsBipartite(graph Graph) (bool, map[string]int, []*Node) {
+	for _, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if neighbor == node {
+				return false, nil, []*Node{node}
+			}
+		}
+	}
+	var adjacency = adjacencyLists(graph)
+	var n = len(graph.Nodes)
+	var color = make([]int, n)
+	var parent = make([]int, n)
+	var depth = make([]int, n)
+	for i := range color {
+		color[i] = -1
+	}
+
+	for start := range graph.Nodes {
+		if color[start] != -1 {
+			continue
+		}
+		color[start], parent[start] = 0, -1
+		var queue = []int{start}
+		for len(queue) > 0 {
+			var u = queue[0]
+			queue = queue[1:]
+			for _, v := range adjacency[u] {
+				if color[v] == -1 {
+					color[v], parent[v], depth[v] = 1-color[u], u, depth[u]+1
+					queue = append(queue, v)
+				} else if color[v] == color[u] {
+					return false, nil, oddCycle(graph, parent, depth, u, v)
+				}
+			}
+		}
+	}
+
+	var coloring = make(map[string]int, n)
+	for index, node := range graph.Nodes {
+		coloring[node.ID] = color[index]
+	}
+	return true, coloring, nil
+}
+
+// oddCycle joins the breadth-first search tree paths of two adjacent nodes of the same color
+// at their lowest common ancestor, closing an odd cycle.
+func oddCycle(graph Graph, parent []int, depth []int, u int, v int) []*Node {
+	var left, right []int
+	for u != v {
+		if depth[u] >= depth[v] {
+			left = append(left, u)
+			u = parent[u]
+		} else {
+			right = append(right, v)
+			v = parent[v]
+		}
+	}
+	left = append(left, u)
+	for i := len(right) - 1; i >= 0; i-- {
+		left = append(left, right[i])
+	}
+
+	var cycle = make([]*Node, len(left))
+	for index, i := range left {
+		cycle[index] = graph.Nodes[i]
+	}
+	return cycle
+}
+
+// HopcroftKarp finds a maximum cardinality matching of a bipartite graph. The matching is
+// returned as edges whose From node is colored 0 by IsBipartite. An error is returned if the
+// graph is not bipartite.
+func HopcroftKarp(graph Graph) ([]Edge, error) {
+	var bipartite, coloring, _ = IsBipartite(graph)
+	if !bipartite {
+		return nil, errors.New("graph is not bipartite")
+	}
+
+	var adjacency = adjacencyLists(graph)
+	var n = len(graph.Nodes)
+	var match = make([]int, n)
+	var distance = make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+	var left []int
+	for index, node := range graph.Nodes {
+		if coloring[node.ID] == 0 {
+			left = append(left, index)
+		}
+	}
+
+	// Layer the free left nodes and alternating paths from them up to the first layer with a
+	// free right neighbor, whose distance is kept as the limit, so that each phase augments
+	// along shortest paths only. It reports whether some free right node can be reached.
+	var limit int
+	var layer = func() bool {
+		var queue []int
+		for _, u := range left {
+			if match[u] == -1 {
+				distance[u] = 0
+				queue = append(queue, u)
+			} else {
+				distance[u] = -1
+			}
+		}
+		limit = -1
+		for len(queue) > 0 {
+			var u = queue[0]
+			queue = queue[1:]
+			if limit != -1 && distance[u] > limit {
+				break
+			}
+			for _, v := range adjacency[u] {
+				var w = match[v]
+				if w == -1 {
+					limit = distance[u]
+				} else if distance[w] == -1 && limit == -1 {
+					distance[w] = distance[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		return limit != -1
+	}
+
+	var augment func(u int) bool
+	augment = func(u int) bool {
+		for _, v := range adjacency[u] {
+			var w = match[v]
+			if (w == -1 && distance[u] == limit) || (w != -1 && distance[w] == distance[u]+1 && augment(w)) {
+				match[u], match[v] = v, u
+				return true
+			}
+		}
+		distance[u] = -1
+		return false
+	}
+
+	for layer() {
+		for _, u := range left {
+			if match[u] == -1 {
+				augment(u)
+			}
+		}
+	}
+
+	var matching []Edge
+	for _, u := range left {
+		if match[u] != -1 {
+			matching = append(matching, Edge{From: graph.Nodes[u].ID, To: graph.Nodes[match[u]].ID})
+		}
+	}
+	return matching, nil
+}
+
+// MaximumMatching implements Edmonds' blossom algorithm to find a maximum cardinality
+// matching of a general undirected graph. Each matched pair is returned once as an edge.
+func MaximumMatching(graph Graph) []Edge {
+	var adjacency = adjacencyLists(graph)
+	var n = len(graph.Nodes)
+	var match = make([]int, n)
+	var parent = make([]int, n)
+	var base = make([]int, n)
+	var used = make([]bool, n)
+	var inBlossom = make([]bool, n)
+	for i := range match {
+		match[i] = -1
+	}
+
+	var lowestCommonAncestor = func(a int, b int) int {
+		var seen = make([]bool, n)
+		for {
+			a = base[a]
+			seen[a] = true
+			if match[a] == -1 {
+				break
+			}
+			a = parent[match[a]]
+		}
+		for {
+			b = base[b]
+			if seen[b] {
+				return b
+			}
+			b = parent[match[b]]
+		}
+	}
+
+	var markPath = func(v int, blossomBase int, child int) {
+		for base[v] != blossomBase {
+			inBlossom[base[v]], inBlossom[base[match[v]]] = true, true
+			parent[v] = child
+			child = match[v]
+			v = parent[match[v]]
+		}
+	}
+
+	// findPath searches for an augmenting path from a free root, contracting blossoms as
+	// they are found, and returns the free node it ends at or -1.
+	var findPath = func(root int) int {
+		for i := range used {
+			used[i], parent[i], base[i] = false, -1, i
+		}
+		used[root] = true
+		var queue = []int{root}
+		for len(queue) > 0 {
+			var v = queue[0]
+			queue = queue[1:]
+			for _, to := range adjacency[v] {
+				if base[v] == base[to] || match[v] == to {
+					continue
+				}
+				if to == root || (match[to] != -1 && parent[match[to]] != -1) {
+					var blossomBase = lowestCommonAncestor(v, to)
+					for i := range inBlossom {
+						inBlossom[i] = false
+					}
+					markPath(v, blossomBase, to)
+					markPath(to, blossomBase, v)
+					for i := 0; i < n; i++ {
+						if inBlossom[base[i]] {
+							base[i] = blossomBase
+							if !used[i] {
+								used[i] = true
+								queue = append(queue, i)
+							}
+						}
+					}
+				} else if parent[to] == -1 {
+					parent[to] = v
+					if match[to] == -1 {
+						return to
+					}
+					used[match[to]] = true
+					queue = append(queue, match[to])
+				}
+			}
+		}
+		return -1
+	}
+
+	for root := 0; root < n; root++ {
+		if match[root] != -1 {
+			continue
+		}
+		var v = findPath(root)
+		for v != -1 {
+			var previous = parent[v]
+			var next = match[previous]
+			match[v], match[previous] = previous, v
+			v = next
+		}
+	}
+
+	var matching []Edge
+	for u, v := range match {
+		if v != -1 && u < v {
+			matching = append(matching, Edge{From: graph.Nodes[u].ID, To: graph.Nodes[v].ID})
+		}
+	}
+	return matching
+}
+
+// weightedMatcher holds the state of Edmonds' weighted blossom algorithm. Vertices are numbered
+// from 0 to n-1 and blossoms from n to 2n-1; every edge k has the endpoints 2k and 2k+1, which
+// are its two ends, and a vertex or top-level blossom is labeled 0 when free of the search, 1
+// as an outer (S) node and 2 as an inner (T) node.
+type weightedMatcher struct {
+	n                int
+	edges            []weightedEdge
+	endpoint         []int     // vertex at each endpoint
+	neighborEnds     [][]int   // remote endpoints of the edges at each vertex
+	mate             []int     // remote endpoint of each vertex's matched edge, or -1
+	label            []int     // label of each vertex and blossom
+	labelEnd         []int     // endpoint through which each labeled node was reached, or -1
+	inBlossom        []int     // top-level blossom containing each vertex
+	blossomParent    []int     // blossom directly containing each vertex or blossom, or -1
+	blossomChildren  [][]int   // sub-blossoms of each blossom, in order around it from its base
+	blossomBase      []int     // base vertex of each blossom, or -1 if it is not in use
+	blossomEnds      [][]int   // endpoints of the edges joining each blossom's sub-blossoms
+	bestEdge         []int     // least-slack edge from each vertex or blossom to an outer node
+	blossomBestEdges [][]int   // least-slack edges from each outer blossom to other outer nodes
+	unused           []int     // blossom numbers not in use
+	dual             []float64 // dual variable of each vertex and blossom
+	allowed          []bool    // whether each edge is known to have no slack
+	queue            []int     // outer vertices still to be scanned
+}
+
+// slack returns twice the slack of an edge, which is zero when the edge is tight
+func (matcher *weightedMatcher) slack(k int) float64 {
+	var edge = matcher.edges[k]
+	return matcher.dual[edge.from] + matcher.dual[edge.to] - 2*edge.weight
+}
+
+// leaves lists the vertices of a vertex or blossom
+func (matcher *weightedMatcher) leaves(b int) []int {
+	if b < matcher.n {
+		return []int{b}
+	}
+	var vertices []int
+	for _, child := range matcher.blossomChildren[b] {
+		vertices = append(vertices, matcher.leaves(child)...)
+	}
+	return vertices
+}
+
+// assignLabel labels the top-level blossom of vertex w, reached through endpoint p, and labels
+// the mate of an inner blossom's base as outer in turn
+func (matcher *weightedMatcher) assignLabel(w int, t int, p int) {
+	var b = matcher.inBlossom[w]
+	matcher.label[w], matcher.label[b] = t, t
+	matcher.labelEnd[w], matcher.labelEnd[b] = p, p
+	matcher.bestEdge[w], matcher.bestEdge[b] = -1, -1
+	if t == 1 {
+		matcher.queue = append(matcher.queue, matcher.leaves(b)...)
+	} else {
+		var base = matcher.blossomBase[b]
+		matcher.assignLabel(matcher.endpoint[matcher.mate[base]], 1, matcher.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from two outer vertices joined by a tight edge, returning the base
+// of the new blossom they close, or -1 if they lead to different free vertices and so to an
+// augmenting path
+func (matcher *weightedMatcher) scanBlossom(v int, w int) int {
+	var path []int
+	var base = -1
+	for v != -1 || w != -1 {
+		var b = matcher.inBlossom[v]
+		if matcher.label[b]&4 != 0 {
+			base = matcher.blossomBase[b]
+			break
+		}
+		path = append(path, b)
+		matcher.label[b] = 5
+		if matcher.labelEnd[b] == -1 {
+			v = -1
+		} else {
+			v = matcher.endpoint[matcher.labelEnd[b]]
+			b = matcher.inBlossom[v]
+			v = matcher.endpoint[matcher.labelEnd[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		matcher.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom contracts the odd cycle closed by edge k through the given base into a new outer
+// blossom
+func (matcher *weightedMatcher) addBlossom(base int, k int) {
+	var v, w = matcher.edges[k].from, matcher.edges[k].to
+	var bb, bv, bw = matcher.inBlossom[base], matcher.inBlossom[v], matcher.inBlossom[w]
+	var b = matcher.unused[len(matcher.unused)-1]
+	matcher.unused = matcher.unused[:len(matcher.unused)-1]
+	matcher.blossomBase[b] = base
+	matcher.blossomParent[b] = -1
+	matcher.blossomParent[bb] = b
+
+	var path, ends []int
+	for bv != bb {
+		matcher.blossomParent[bv] = b
+		path = append(path, bv)
+		ends = append(ends, matcher.labelEnd[bv])
+		v = matcher.endpoint[matcher.labelEnd[bv]]
+		bv = matcher.inBlossom[v]
+	}
+	path = append(path, bb)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for i, j := 0, len(ends)-1; i < j; i, j = i+1, j-1 {
+		ends[i], ends[j] = ends[j], ends[i]
+	}
+	ends = append(ends, 2*k)
+	for bw != bb {
+		matcher.blossomParent[bw] = b
+		path = append(path, bw)
+		ends = append(ends, matcher.labelEnd[bw]^1)
+		w = matcher.endpoint[matcher.labelEnd[bw]]
+		bw = matcher.inBlossom[w]
+	}
+	matcher.blossomChildren[b], matcher.blossomEnds[b] = path, ends
+	matcher.label[b] = 1
+	matcher.labelEnd[b] = matcher.labelEnd[bb]
+	matcher.dual[b] = 0
+	for _, leaf := range matcher.leaves(b) {
+		if matcher.label[matcher.inBlossom[leaf]] == 2 {
+			// Inner vertices become outer and must be scanned
+			matcher.queue = append(matcher.queue, leaf)
+		}
+		matcher.inBlossom[leaf] = b
+	}
+
+	// Gather the least-slack edges from the new blossom to every other outer blossom
+	var bestTo = make([]int, 2*matcher.n)
+	for i := range bestTo {
+		bestTo[i] = -1
+	}
+	for _, child := range path {
+		var lists [][]int
+		if matcher.blossomBestEdges[child] == nil {
+			for _, leaf := range matcher.leaves(child) {
+				var list = make([]int, len(matcher.neighborEnds[leaf]))
+				for i, p := range matcher.neighborEnds[leaf] {
+					list[i] = p / 2
+				}
+				lists = append(lists, list)
+			}
+		} else {
+			lists = [][]int{matcher.blossomBestEdges[child]}
+		}
+		for _, list := range lists {
+			for _, edge := range list {
+				var j = matcher.edges[edge].to
+				if matcher.inBlossom[j] == b {
+					j = matcher.edges[edge].from
+				}
+				var bj = matcher.inBlossom[j]
+				if bj != b && matcher.label[bj] == 1 && (bestTo[bj] == -1 || matcher.slack(edge) < matcher.slack(bestTo[bj])) {
+					bestTo[bj] = edge
+				}
+			}
+		}
+		matcher.blossomBestEdges[child] = nil
+		matcher.bestEdge[child] = -1
+	}
+	var best = make([]int, 0, len(bestTo))
+	for _, edge := range bestTo {
+		if edge != -1 {
+			best = append(best, edge)
+		}
+	}
+	matcher.blossomBestEdges[b] = best
+	matcher.bestEdge[b] = -1
+	for _, edge := range best {
+		if matcher.bestEdge[b] == -1 || matcher.slack(edge) < matcher.slack(matcher.bestEdge[b]) {
+			matcher.bestEdge[b] = edge
+		}
+	}
+}
+
+// indexOf returns the position of a value in a list
+func indexOf(list []int, value int) int {
+	for i, x := range list {
+		if x == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// expandBlossom undoes the contraction of a top-level blossom. In the middle of a stage the
+// sub-blossoms of an inner blossom are relabeled along the even path from its entry to its base;
+// at the end of a stage, sub-blossoms whose duals are zero are expanded as well.
+func (matcher *weightedMatcher) expandBlossom(b int, endStage bool) {
+	for _, s := range matcher.blossomChildren[b] {
+		matcher.blossomParent[s] = -1
+		if s < matcher.n {
+			matcher.inBlossom[s] = s
+		} else if endStage && matcher.dual[s] == 0 {
+			matcher.expandBlossom(s, endStage)
+		} else {
+			for _, leaf := range matcher.leaves(s) {
+				matcher.inBlossom[leaf] = s
+			}
+		}
+	}
+
+	if !endStage && matcher.label[b] == 2 {
+		var children, ends = matcher.blossomChildren[b], matcher.blossomEnds[b]
+		var at = func(j int) int {
+			return (j%len(children) + len(children)) % len(children)
+		}
+		var entry = matcher.inBlossom[matcher.endpoint[matcher.labelEnd[b]^1]]
+		var j = indexOf(children, entry)
+		var step, trick int
+		if j&1 != 0 {
+			j -= len(children)
+			step, trick = 1, 0
+		} else {
+			step, trick = -1, 1
+		}
+		var p = matcher.labelEnd[b]
+		for j != 0 {
+			// Relabel the inner sub-blossom and the outer one after it on the even path
+			matcher.label[matcher.endpoint[p^1]] = 0
+			matcher.label[matcher.endpoint[ends[at(j-trick)]^trick^1]] = 0
+			matcher.assignLabel(matcher.endpoint[p^1], 2, p)
+			matcher.allowed[ends[at(j-trick)]/2] = true
+			j += step
+			p = ends[at(j-trick)] ^ trick
+			matcher.allowed[p/2] = true
+			j += step
+		}
+		var bv = children[at(j)]
+		matcher.label[matcher.endpoint[p^1]], matcher.label[bv] = 2, 2
+		matcher.labelEnd[matcher.endpoint[p^1]], matcher.labelEnd[bv] = p, p
+		matcher.bestEdge[bv] = -1
+		j += step
+		for children[at(j)] != entry {
+			// Sub-blossoms on the odd path are free again unless one of their vertices was
+			// reached from outside
+			bv = children[at(j)]
+			if matcher.label[bv] == 1 {
+				j += step
+				continue
+			}
+			var reached = -1
+			for _, leaf := range matcher.leaves(bv) {
+				if matcher.label[leaf] != 0 {
+					reached = leaf
+					break
+				}
+			}
+			if reached != -1 {
+				matcher.label[reached] = 0
+				matcher.label[matcher.endpoint[matcher.mate[matcher.blossomBase[bv]]]] = 0
+				matcher.assignLabel(reached, 2, matcher.labelEnd[reached])
+			}
+			j += step
+		}
+	}
+
+	matcher.label[b], matcher.labelEnd[b] = -1, -1
+	matcher.blossomChildren[b], matcher.blossomEnds[b] = nil, nil
+	matcher.blossomBase[b] = -1
+	matcher.blossomBestEdges[b] = nil
+	matcher.bestEdge[b] = -1
+	matcher.unused = append(matcher.unused, b)
+}
+
+// augmentBlossom swaps the matched and unmatched edges along the even path from vertex v to the
+// base of blossom b, rotating the blossom so that v becomes its base
+func (matcher *weightedMatcher) augmentBlossom(b int, v int) {
+	var t = v
+	for matcher.blossomParent[t] != b {
+		t = matcher.blossomParent[t]
+	}
+	if t >= matcher.n {
+		matcher.augmentBlossom(t, v)
+	}
+	var children, ends = matcher.blossomChildren[b], matcher.blossomEnds[b]
+	var at = func(j int) int {
+		return (j%len(children) + len(children)) % len(children)
+	}
+	var i = indexOf(children, t)
+	var j = i
+	var step, trick int
+	if i&1 != 0 {
+		j -= len(children)
+		step, trick = 1, 0
+	} else {
+		step, trick = -1, 1
+	}
+	for j != 0 {
+		j += step
+		t = children[at(j)]
+		var p = ends[at(j-trick)] ^ trick
+		if t >= matcher.n {
+			matcher.augmentBlossom(t, matcher.endpoint[p])
+		}
+		j += step
+		t = children[at(j)]
+		if t >= matcher.n {
+			matcher.augmentBlossom(t, matcher.endpoint[p^1])
+		}
+		matcher.mate[matcher.endpoint[p]] = p ^ 1
+		matcher.mate[matcher.endpoint[p^1]] = p
+	}
+	matcher.blossomChildren[b] = append(append([]int{}, children[i:]...), children[:i]...)
+	matcher.blossomEnds[b] = append(append([]int{}, ends[i:]...), ends[:i]...)
+	matcher.blossomBase[b] = matcher.blossomBase[matcher.blossomChildren[b][0]]
+}
+
+// augmentMatching swaps the matched and unmatched edges along the augmenting path through edge k
+func (matcher *weightedMatcher) augmentMatching(k int) {
+	for _, start := range [][2]int{{matcher.edges[k].from, 2*k + 1}, {matcher.edges[k].to, 2 * k}} {
+		var s, p = start[0], start[1]
+		for {
+			var bs = matcher.inBlossom[s]
+			if bs >= matcher.n {
+				matcher.augmentBlossom(bs, s)
+			}
+			matcher.mate[s] = p
+			if matcher.labelEnd[bs] == -1 {
+				break
+			}
+			var t = matcher.endpoint[matcher.labelEnd[bs]]
+			var bt = matcher.inBlossom[t]
+			s = matcher.endpoint[matcher.labelEnd[bt]]
+			var j = matcher.endpoint[matcher.labelEnd[bt]^1]
+			if bt >= matcher.n {
+				matcher.augmentBlossom(bt, j)
+			}
+			matcher.mate[j] = matcher.labelEnd[bt]
+			p = matcher.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// weightedMatching implements Edmonds' weighted blossom algorithm, in O(n³) time, to find the
+// matching of greatest total weight among the matchings of greatest cardinality of an
+// undirected graph on n vertices. It returns the mate of every vertex, or -1 if it has none.
+func weightedMatching(n int, edges []weightedEdge) []int {
+	var mate = make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+	if len(edges) == 0 {
+		return mate
+	}
+	var matcher = &weightedMatcher{
+		n:                n,
+		edges:            edges,
+		endpoint:         make([]int, 2*len(edges)),
+		neighborEnds:     make([][]int, n),
+		mate:             mate,
+		label:            make([]int, 2*n),
+		labelEnd:         make([]int, 2*n),
+		inBlossom:        make([]int, n),
+		blossomParent:    make([]int, 2*n),
+		blossomChildren:  make([][]int, 2*n),
+		blossomBase:      make([]int, 2*n),
+		blossomEnds:      make([][]int, 2*n),
+		bestEdge:         make([]int, 2*n),
+		blossomBestEdges: make([][]int, 2*n),
+		dual:             make([]float64, 2*n),
+		allowed:          make([]bool, len(edges)),
+	}
+	var maxWeight float64
+	for k, edge := range edges {
+		maxWeight = math.Max(maxWeight, edge.weight)
+		matcher.endpoint[2*k], matcher.endpoint[2*k+1] = edge.from, edge.to
+		matcher.neighborEnds[edge.from] = append(matcher.neighborEnds[edge.from], 2*k+1)
+		matcher.neighborEnds[edge.to] = append(matcher.neighborEnds[edge.to], 2*k)
+	}
+	for v := 0; v < n; v++ {
+		matcher.inBlossom[v] = v
+		matcher.blossomBase[v], matcher.blossomBase[n+v] = v, -1
+		matcher.dual[v] = maxWeight
+		matcher.unused = append(matcher.unused, n+v)
+	}
+	for b := range matcher.blossomParent {
+		matcher.labelEnd[b], matcher.blossomParent[b] = -1, -1
+	}
+
+	// Every stage either grows the matching by one edge or shows that it is maximum
+	for stage := 0; stage < n; stage++ {
+		for b := 0; b < 2*n; b++ {
+			matcher.label[b], matcher.bestEdge[b] = 0, -1
+			if b >= n {
+				matcher.blossomBestEdges[b] = nil
+			}
+		}
+		for k := range matcher.allowed {
+			matcher.allowed[k] = false
+		}
+		matcher.queue = matcher.queue[:0]
+		for v := 0; v < n; v++ {
+			if mate[v] == -1 && matcher.label[matcher.inBlossom[v]] == 0 {
+				matcher.assignLabel(v, 1, -1)
+			}
+		}
+
+		var augmented = false
+		for {
+			for len(matcher.queue) > 0 && !augmented {
+				var v = matcher.queue[len(matcher.queue)-1]
+				matcher.queue = matcher.queue[:len(matcher.queue)-1]
+				for _, p := range matcher.neighborEnds[v] {
+					var k, w = p / 2, matcher.endpoint[p]
+					if matcher.inBlossom[v] == matcher.inBlossom[w] {
+						continue
+					}
+					var kSlack float64
+					if !matcher.allowed[k] {
+						kSlack = matcher.slack(k)
+						if kSlack <= 0 {
+							matcher.allowed[k] = true
+						}
+					}
+					if matcher.allowed[k] {
+						if matcher.label[matcher.inBlossom[w]] == 0 {
+							matcher.assignLabel(w, 2, p^1)
+						} else if matcher.label[matcher.inBlossom[w]] == 1 {
+							if base := matcher.scanBlossom(v, w); base >= 0 {
+								matcher.addBlossom(base, k)
+							} else {
+								matcher.augmentMatching(k)
+								augmented = true
+								break
+							}
+						} else if matcher.label[w] == 0 {
+							matcher.label[w], matcher.labelEnd[w] = 2, p^1
+						}
+					} else if matcher.label[matcher.inBlossom[w]] == 1 {
+						var b = matcher.inBlossom[v]
+						if matcher.bestEdge[b] == -1 || kSlack < matcher.slack(matcher.bestEdge[b]) {
+							matcher.bestEdge[b] = k
+						}
+					} else if matcher.label[w] == 0 {
+						if matcher.bestEdge[w] == -1 || kSlack < matcher.slack(matcher.bestEdge[w]) {
+							matcher.bestEdge[w] = k
+						}
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No tight edge leads on, so change the duals by the most that keeps them feasible
+			var deltaType, deltaEdge, deltaBlossom = -1, -1, -1
+			var delta float64
+			for v := 0; v < n; v++ {
+				if matcher.label[matcher.inBlossom[v]] == 0 && matcher.bestEdge[v] != -1 {
+					if d := matcher.slack(matcher.bestEdge[v]); deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 2, matcher.bestEdge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				if matcher.blossomParent[b] == -1 && matcher.label[b] == 1 && matcher.bestEdge[b] != -1 {
+					if d := matcher.slack(matcher.bestEdge[b]) / 2; deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 3, matcher.bestEdge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if matcher.blossomBase[b] >= 0 && matcher.blossomParent[b] == -1 && matcher.label[b] == 2 && (deltaType == -1 || matcher.dual[b] < delta) {
+					delta, deltaType, deltaBlossom = matcher.dual[b], 4, b
+				}
+			}
+			if deltaType == -1 {
+				// The matching is of greatest cardinality; lower the duals to end the search
+				deltaType = 1
+				delta = matcher.dual[0]
+				for v := 1; v < n; v++ {
+					delta = math.Min(delta, matcher.dual[v])
+				}
+				delta = math.Max(delta, 0)
+			}
+
+			for v := 0; v < n; v++ {
+				switch matcher.label[matcher.inBlossom[v]] {
+				case 1:
+					matcher.dual[v] -= delta
+				case 2:
+					matcher.dual[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if matcher.blossomBase[b] >= 0 && matcher.blossomParent[b] == -1 {
+					switch matcher.label[b] {
+					case 1:
+						matcher.dual[b] += delta
+					case 2:
+						matcher.dual[b] -= delta
+					}
+				}
+			}
+
+			if deltaType == 1 {
+				break
+			} else if deltaType == 2 {
+				matcher.allowed[deltaEdge] = true
+				var i = matcher.edges[deltaEdge].from
+				if matcher.label[matcher.inBlossom[i]] == 0 {
+					i = matcher.edges[deltaEdge].to
+				}
+				matcher.queue = append(matcher.queue, i)
+			} else if deltaType == 3 {
+				matcher.allowed[deltaEdge] = true
+				matcher.queue = append(matcher.queue, matcher.edges[deltaEdge].from)
+			} else {
+				matcher.expandBlossom(deltaBlossom, false)
+			}
+		}
+		if !augmented {
+			break
+		}
+		for b := n; b < 2*n; b++ {
+			if matcher.blossomParent[b] == -1 && matcher.blossomBase[b] >= 0 && matcher.label[b] == 1 && matcher.dual[b] == 0 {
+				matcher.expandBlossom(b, true)
+			}
+		}
+	}
+
+	for v := range mate {
+		if mate[v] >= 0 {
+			mate[v] = matcher.endpoint[mate[v]]
+		}
+	}
+	return mate
+}
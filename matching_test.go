@@ -0,0 +1,125 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// expectValidMatching checks that a matching only uses edges of the graph and that no node is matched twice
+func expectValidMatching(graph Graph, matching []Edge, t *testing.T) {
+	var matched = map[string]bool{}
+	for _, edge := range matching {
+		if matched[edge.From] || matched[edge.To] {
+			t.Errorf("Failed: expected disjoint edges, but found %+v matched twice", edge)
+		}
+		matched[edge.From], matched[edge.To] = true, true
+		var from = graph.Nodes[FindNode(graph, edge.From)]
+		var isEdge = false
+		for _, neighbor := range from.Edges {
+			isEdge = isEdge || neighbor.ID == edge.To
+		}
+		if !isEdge {
+			t.Errorf("Failed: expected %+v to be an edge of the graph", edge)
+		}
+	}
+}
+
+func TestIsBipartite(t *testing.T) {
+	describe("IsBipartite", t)
+
+	context("the graph is an even cycle", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+	var bipartite, coloring, cycle = IsBipartite(graph)
+
+	it("returns a two-coloring", t)
+	expectEqualBools(bipartite, true, t)
+	expectEqualInts(len(cycle), 0, t)
+	expectEqualInts(coloring[graph.Nodes[0].ID], coloring[graph.Nodes[2].ID], t)
+	expectEqualInts(coloring[graph.Nodes[1].ID], coloring[graph.Nodes[3].ID], t)
+	expectEqualBools(coloring[graph.Nodes[0].ID] == coloring[graph.Nodes[1].ID], false, t)
+
+	context("the graph contains a triangle", t)
+	graph = createWeightedGraph(5, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 2, 1}})
+	bipartite, coloring, cycle = IsBipartite(graph)
+
+	it("returns the odd cycle as proof", t)
+	expectEqualBools(bipartite, false, t)
+	expectEqualInts(len(cycle), 3, t)
+	for index, node := range cycle {
+		var next = cycle[(index+1)%len(cycle)]
+		var adjacent = false
+		for _, neighbor := range node.Edges {
+			adjacent = adjacent || neighbor == next
+		}
+		expectEqualBools(adjacent, true, t)
+	}
+
+	context("the graph has a self-loop", t)
+	graph = createWeightedGraph(2, [][3]float64{{0, 1, 1}, {0, 0, 1}})
+	bipartite, coloring, cycle = IsBipartite(graph)
+
+	it("returns the looped node as an odd cycle", t)
+	expectEqualBools(bipartite, false, t)
+	expectEqualInts(len(coloring), 0, t)
+	expectEqualInts(len(cycle), 1, t)
+	expectEqualBools(cycle[0] == graph.Nodes[0], true, t)
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	describe("HopcroftKarp", t)
+
+	context("the graph is bipartite", t)
+	// Left nodes 0-3 and right nodes 4-7, where greedy matching would leave 3 unmatched
+	var graph = createWeightedGraph(8, [][3]float64{
+		{0, 4, 1}, {0, 5, 1}, {1, 4, 1}, {2, 5, 1}, {2, 6, 1}, {3, 6, 1}, {3, 4, 1}, {1, 7, 1},
+	})
+	var matching, err = HopcroftKarp(graph)
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("returns a maximum matching", t)
+	expectEqualInts(len(matching), 4, t)
+	expectValidMatching(graph, matching, t)
+
+	context("the graph is not bipartite", t)
+	graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+	_, err = HopcroftKarp(graph)
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
+
+func TestMaximumMatching(t *testing.T) {
+	describe("MaximumMatching", t)
+
+	context("the graph has an odd cycle with a pendant node", t)
+	var graph = createWeightedGraph(6, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1}, {0, 5, 1},
+	})
+	var matching = MaximumMatching(graph)
+
+	it("returns a perfect matching", t)
+	expectEqualInts(len(matching), 3, t)
+	expectValidMatching(graph, matching, t)
+
+	context("the graph is the Petersen graph", t)
+	graph = createWeightedGraph(10, [][3]float64{
+		{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1},
+		{0, 5, 1}, {1, 6, 1}, {2, 7, 1}, {3, 8, 1}, {4, 9, 1},
+		{5, 7, 1}, {7, 9, 1}, {9, 6, 1}, {6, 8, 1}, {8, 5, 1},
+	})
+	matching = MaximumMatching(graph)
+
+	it("returns a perfect matching", t)
+	expectEqualInts(len(matching), 5, t)
+	expectValidMatching(graph, matching, t)
+
+	context("the graph is a triangle", t)
+	graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+
+	it("leaves one node unmatched", t)
+	expectEqualInts(len(MaximumMatching(graph)), 1, t)
+}
@@ -0,0 +1,468 @@
+package gograph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Matrix is a matrix of integers stored by rows, as returned by the functions building the
+// adjacency, incidence, degree and Laplacian matrices of a graph. Rows and columns that stand
+// for nodes are in the order of the graph's Nodes or DirectedNodes, as NodeIDs and
+// DirectedNodeIDs list them.
+type Matrix [][]int
+
+// FloatMatrix is a matrix of real numbers stored by rows, as returned by the functions building
+// weighted adjacency and normalized Laplacian matrices of a graph
+type FloatMatrix [][]float64
+
+// NodeIDs lists the IDs of the nodes of an undirected graph in the order of the rows and
+// columns of its matrices
+func NodeIDs(graph Graph) []string {
+	return nodeIDs(graph)
+}
+
+// DirectedNodeIDs lists the IDs of the nodes of a directed graph in the order of the rows and
+// columns of its matrices
+func DirectedNodeIDs(graph DirectedGraph) []string {
+	return directedNodeIDs(graph)
+}
+
+// shape returns the number of rows and columns of a matrix, or an error if its rows differ in
+// length
+func shape(rows int, rowLength func(i int) int) (int, int, error) {
+	if rows == 0 {
+		return 0, 0, nil
+	}
+	var columns = rowLength(0)
+	for i := 1; i < rows; i++ {
+		if rowLength(i) != columns {
+			return 0, 0, fmt.Errorf("matrix is not rectangular: row %d has %d entries, but row 0 has %d", i, rowLength(i), columns)
+		}
+	}
+	return rows, columns, nil
+}
+
+// Shape returns the number of rows and columns of the matrix, or an error if its rows differ in
+// length
+func (matrix Matrix) Shape() (int, int, error) {
+	return shape(len(matrix), func(i int) int { return len(matrix[i]) })
+}
+
+// Shape returns the number of rows and columns of the matrix, or an error if its rows differ in
+// length
+func (matrix FloatMatrix) Shape() (int, int, error) {
+	return shape(len(matrix), func(i int) int { return len(matrix[i]) })
+}
+
+// IsSymmetric reports whether the matrix equals its transpose. An error is returned if the
+// matrix is not square.
+func (matrix Matrix) IsSymmetric() (bool, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return false, err
+	}
+	for i, row := range matrix {
+		for j := i + 1; j < len(row); j++ {
+			if row[j] != matrix[j][i] {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// IsAntisymmetric reports whether the matrix equals the negation of its transpose, so that its
+// diagonal is all zeros. An error is returned if the matrix is not square.
+func (matrix Matrix) IsAntisymmetric() (bool, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return false, err
+	}
+	for i, row := range matrix {
+		for j := i; j < len(row); j++ {
+			if row[j] != -matrix[j][i] {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// IsSymmetric reports whether the matrix exactly equals its transpose. An error is returned if
+// the matrix is not square.
+func (matrix FloatMatrix) IsSymmetric() (bool, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return false, err
+	}
+	for i, row := range matrix {
+		for j := i + 1; j < len(row); j++ {
+			if row[j] != matrix[j][i] {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// IsAntisymmetric reports whether the matrix exactly equals the negation of its transpose. An
+// error is returned if the matrix is not square.
+func (matrix FloatMatrix) IsAntisymmetric() (bool, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return false, err
+	}
+	for i, row := range matrix {
+		for j := i; j < len(row); j++ {
+			if row[j] != -matrix[j][i] {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// Transpose returns the matrix with its rows and columns swapped. An error is returned if the
+// matrix is not rectangular.
+func (matrix Matrix) Transpose() (Matrix, error) {
+	var rows, columns, err = matrix.Shape()
+	if err != nil {
+		return nil, err
+	}
+	var result = make(Matrix, columns)
+	for j := range result {
+		result[j] = make([]int, rows)
+		for i := range matrix {
+			result[j][i] = matrix[i][j]
+		}
+	}
+	return result, nil
+}
+
+// Transpose returns the matrix with its rows and columns swapped. An error is returned if the
+// matrix is not rectangular.
+func (matrix FloatMatrix) Transpose() (FloatMatrix, error) {
+	var rows, columns, err = matrix.Shape()
+	if err != nil {
+		return nil, err
+	}
+	var result = make(FloatMatrix, columns)
+	for j := range result {
+		result[j] = make([]float64, rows)
+		for i := range matrix {
+			result[j][i] = matrix[i][j]
+		}
+	}
+	return result, nil
+}
+
+// Trace returns the sum of the diagonal of the matrix, which for an adjacency matrix counts the
+// self-loops. An error is returned if the matrix is not square.
+func (matrix Matrix) Trace() (int, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return 0, err
+	}
+	var trace = 0
+	for i := range matrix {
+		trace += matrix[i][i]
+	}
+	return trace, nil
+}
+
+// Trace returns the sum of the diagonal of the matrix. An error is returned if the matrix is not
+// square.
+func (matrix FloatMatrix) Trace() (float64, error) {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return 0, err
+	}
+	var trace = 0.0
+	for i := range matrix {
+		trace += matrix[i][i]
+	}
+	return trace, nil
+}
+
+// Multiply returns the product of the matrix with another. An error is returned if either is not
+// rectangular or if the matrix does not have as many columns as the other has rows.
+func (matrix Matrix) Multiply(other Matrix) (Matrix, error) {
+	var rows, inner, err = matrix.Shape()
+	if err != nil {
+		return nil, err
+	}
+	var otherRows, columns, otherErr = other.Shape()
+	if otherErr != nil {
+		return nil, otherErr
+	}
+	if inner != otherRows {
+		return nil, fmt.Errorf("cannot multiply a %d×%d matrix by a %d×%d matrix", rows, inner, otherRows, columns)
+	}
+	var product = make(Matrix, rows)
+	for i, row := range matrix {
+		product[i] = make([]int, columns)
+		for k, entry := range row {
+			if entry == 0 {
+				continue
+			}
+			for j, otherEntry := range other[k] {
+				product[i][j] += entry * otherEntry
+			}
+		}
+	}
+	return product, nil
+}
+
+// Multiply returns the product of the matrix with another. An error is returned if either is not
+// rectangular or if the matrix does not have as many columns as the other has rows.
+func (matrix FloatMatrix) Multiply(other FloatMatrix) (FloatMatrix, error) {
+	var rows, inner, err = matrix.Shape()
+	if err != nil {
+		return nil, err
+	}
+	var otherRows, columns, otherErr = other.Shape()
+	if otherErr != nil {
+		return nil, otherErr
+	}
+	if inner != otherRows {
+		return nil, fmt.Errorf("cannot multiply a %d×%d matrix by a %d×%d matrix", rows, inner, otherRows, columns)
+	}
+	var product = make(FloatMatrix, rows)
+	for i, row := range matrix {
+		product[i] = make([]float64, columns)
+		for k, entry := range row {
+			if entry == 0 {
+				continue
+			}
+			for j, otherEntry := range other[k] {
+				product[i][j] += entry * otherEntry
+			}
+		}
+	}
+	return product, nil
+}
+
+// Power returns the matrix multiplied by itself k times, or the identity matrix when k is 0,
+// using repeated squaring. The entry at row i and column j of the k-th power of an adjacency
+// matrix counts the walks of k edges from node i to node j. An error is returned if the matrix
+// is not square or k is negative.
+func (matrix Matrix) Power(k int) (Matrix, error) {
+	var n = len(matrix)
+	if err := validateSquare(n, func(i int) int { return len(matrix[i]) }); err != nil {
+		return nil, err
+	}
+	if k < 0 {
+		return nil, errors.New("matrix power must not be negative")
+	}
+	var result = make(Matrix, n)
+	for i := range result {
+		result[i] = make([]int, n)
+		result[i][i] = 1
+	}
+	var base = matrix
+	for ; k > 0; k /= 2 {
+		if k%2 == 1 {
+			result, _ = result.Multiply(base)
+		}
+		if k > 1 {
+			base, _ = base.Multiply(base)
+		}
+	}
+	return result, nil
+}
+
+// Power returns the matrix multiplied by itself k times, or the identity matrix when k is 0,
+// using repeated squaring. An error is returned if the matrix is not square or k is negative.
+func (matrix FloatMatrix) Power(k int) (FloatMatrix, error) {
+	var n = len(matrix)
+	if err := validateSquare(n, func(i int) int { return len(matrix[i]) }); err != nil {
+		return nil, err
+	}
+	if k < 0 {
+		return nil, errors.New("matrix power must not be negative")
+	}
+	var result = make(FloatMatrix, n)
+	for i := range result {
+		result[i] = make([]float64, n)
+		result[i][i] = 1
+	}
+	var base = matrix
+	for ; k > 0; k /= 2 {
+		if k%2 == 1 {
+			result, _ = result.Multiply(base)
+		}
+		if k > 1 {
+			base, _ = base.Multiply(base)
+		}
+	}
+	return result, nil
+}
+
+// Float returns the matrix with its entries converted to real numbers
+func (matrix Matrix) Float() FloatMatrix {
+	var result = make(FloatMatrix, len(matrix))
+	for i, row := range matrix {
+		result[i] = make([]float64, len(row))
+		for j, entry := range row {
+			result[i][j] = float64(entry)
+		}
+	}
+	return result
+}
+
+// cells returns the entries of the matrix as text
+func (matrix Matrix) cells() [][]string {
+	var cells = make([][]string, len(matrix))
+	for i, row := range matrix {
+		cells[i] = make([]string, len(row))
+		for j, entry := range row {
+			cells[i][j] = strconv.Itoa(entry)
+		}
+	}
+	return cells
+}
+
+// cells returns the entries of the matrix as text, in the shortest form that reads back exactly
+func (matrix FloatMatrix) cells() [][]string {
+	var cells = make([][]string, len(matrix))
+	for i, row := range matrix {
+		cells[i] = make([]string, len(row))
+		for j, entry := range row {
+			cells[i][j] = formatWeight(entry)
+		}
+	}
+	return cells
+}
+
+// labelCells adds a header row of column labels and a first column of row labels to the cells
+// of a matrix, leaving out either when its labels are nil. An error is returned if there is not
+// one label for every row or column.
+func labelCells(cells [][]string, rowLabels []string, columnLabels []string) ([][]string, error) {
+	if rowLabels != nil && len(rowLabels) != len(cells) {
+		return nil, fmt.Errorf("matrix has %d rows, but there are %d row labels", len(cells), len(rowLabels))
+	}
+	for i, row := range cells {
+		if columnLabels != nil && len(columnLabels) != len(row) {
+			return nil, fmt.Errorf("matrix row %d has %d entries, but there are %d column labels", i, len(row), len(columnLabels))
+		}
+	}
+	var labeled [][]string
+	if columnLabels != nil {
+		var header = append([]string{}, columnLabels...)
+		if rowLabels != nil {
+			header = append([]string{""}, header...)
+		}
+		labeled = append(labeled, header)
+	}
+	for i, row := range cells {
+		if rowLabels != nil {
+			row = append([]string{rowLabels[i]}, row...)
+		}
+		labeled = append(labeled, row)
+	}
+	return labeled, nil
+}
+
+// formatCells lays out labeled cells in right-aligned columns, one line per row
+func formatCells(cells [][]string) string {
+	var widths []int
+	for _, row := range cells {
+		for j, cell := range row {
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(cell) > widths[j] {
+				widths[j] = len(cell)
+			}
+		}
+	}
+	var builder strings.Builder
+	for _, row := range cells {
+		for j, cell := range row {
+			if j > 0 {
+				builder.WriteString(" ")
+			}
+			builder.WriteString(strings.Repeat(" ", widths[j]-len(cell)))
+			builder.WriteString(cell)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// writeCells writes labeled cells as delimited text, quoting cells that contain the delimiter
+func writeCells(writer io.Writer, cells [][]string, delimiter rune) error {
+	var records = csv.NewWriter(writer)
+	records.Comma = delimiter
+	if err := records.WriteAll(cells); err != nil {
+		return err
+	}
+	return records.Error()
+}
+
+// Format lays out the matrix as text in right-aligned columns, one line per row, headed by the
+// row and column labels, such as the IDs of the nodes given by NodeIDs. Either set of labels
+// may be nil to leave it out. An error is returned if there is not one label for every row or
+// column.
+func (matrix Matrix) Format(rowLabels []string, columnLabels []string) (string, error) {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return "", err
+	}
+	return formatCells(cells), nil
+}
+
+// Format lays out the matrix as text like Matrix.Format
+func (matrix FloatMatrix) Format(rowLabels []string, columnLabels []string) (string, error) {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return "", err
+	}
+	return formatCells(cells), nil
+}
+
+// String lays out the matrix as text in right-aligned columns without labels
+func (matrix Matrix) String() string {
+	return formatCells(matrix.cells())
+}
+
+// String lays out the matrix as text in right-aligned columns without labels
+func (matrix FloatMatrix) String() string {
+	return formatCells(matrix.cells())
+}
+
+// WriteCSV writes the matrix as comma-separated values, one record per row, headed by the row
+// and column labels as in Format. An error is returned if the labels do not fit the matrix or
+// the writer fails.
+func (matrix Matrix) WriteCSV(writer io.Writer, rowLabels []string, columnLabels []string) error {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return err
+	}
+	return writeCells(writer, cells, ',')
+}
+
+// WriteTSV writes the matrix as tab-separated values, like WriteCSV
+func (matrix Matrix) WriteTSV(writer io.Writer, rowLabels []string, columnLabels []string) error {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return err
+	}
+	return writeCells(writer, cells, '\t')
+}
+
+// WriteCSV writes the matrix as comma-separated values, like Matrix.WriteCSV
+func (matrix FloatMatrix) WriteCSV(writer io.Writer, rowLabels []string, columnLabels []string) error {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return err
+	}
+	return writeCells(writer, cells, ',')
+}
+
+// WriteTSV writes the matrix as tab-separated values, like Matrix.WriteCSV
+func (matrix FloatMatrix) WriteTSV(writer io.Writer, rowLabels []string, columnLabels []string) error {
+	var cells, err = labelCells(matrix.cells(), rowLabels, columnLabels)
+	if err != nil {
+		return err
+	}
+	return writeCells(writer, cells, '\t')
+}
@@ -0,0 +1,191 @@
+package gograph
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMatrixIsSymmetric(t *testing.T) {
+	describe("Matrix.IsSymmetric", t)
+
+	it("is true for the adjacency matrix of an undirected graph", t)
+	var symmetric, err = CreateUndirectedAdjacencyMatrix(createPathGraph(4)).IsSymmetric()
+	expectEqualBools(err == nil, true, t)
+	expectEqualBools(symmetric, true, t)
+
+	it("is false when an entry differs from its mirror", t)
+	symmetric, _ = Matrix{{0, 1}, {0, 0}}.IsSymmetric()
+	expectEqualBools(symmetric, false, t)
+
+	context("the matrix is not square", t)
+
+	it("returns an error", t)
+	_, err = Matrix{{0, 1, 0}, {1, 0, 0}}.IsSymmetric()
+	expectEqualBools(err == nil, false, t)
+	_, err = FloatMatrix{{0}, {1, 0}}.IsSymmetric()
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixIsAntisymmetric(t *testing.T) {
+	describe("Matrix.IsAntisymmetric", t)
+
+	it("is true when every entry is the negation of its mirror", t)
+	var antisymmetric, err = Matrix{{0, 2, -1}, {-2, 0, 3}, {1, -3, 0}}.IsAntisymmetric()
+	expectEqualBools(err == nil, true, t)
+	expectEqualBools(antisymmetric, true, t)
+
+	it("is false when the diagonal is not zero", t)
+	antisymmetric, _ = Matrix{{1, 0}, {0, 0}}.IsAntisymmetric()
+	expectEqualBools(antisymmetric, false, t)
+
+	it("is false for the adjacency matrix of a directed graph with an edge", t)
+	var graph = createWeightedDirectedGraph(2, [][3]float64{{0, 1, 1}})
+	antisymmetric, _ = CreateAdjecencyMatrix(graph).IsAntisymmetric()
+	expectEqualBools(antisymmetric, false, t)
+
+	context("the matrix is not square", t)
+
+	it("returns an error", t)
+	_, err = Matrix{{0, 1}}.IsAntisymmetric()
+	expectEqualBools(err == nil, false, t)
+
+	it("makes IsAntisymmetricMatrix false instead of panicking", t)
+	expectEqualBools(IsAntisymmetricMatrix(Matrix{{0, 1}}), false, t)
+}
+
+func TestMatrixTranspose(t *testing.T) {
+	describe("Matrix.Transpose", t)
+
+	it("swaps rows and columns", t)
+	var transpose, err = Matrix{{1, 2, 3}, {4, 5, 6}}.Transpose()
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(transpose, [][]int{{1, 4}, {2, 5}, {3, 6}}, t)
+
+	it("turns the adjacency matrix of a directed graph into that of its reverse", t)
+	var graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	transpose, _ = CreateAdjecencyMatrix(graph).Transpose()
+	expectEqualMatrices(transpose, [][]int{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, t)
+
+	context("the rows differ in length", t)
+
+	it("returns an error", t)
+	_, err = Matrix{{1, 2}, {3}}.Transpose()
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixTrace(t *testing.T) {
+	describe("Matrix.Trace", t)
+
+	it("sums the diagonal", t)
+	var trace, err = CreateUndirectedLaplacianMatrix(createPathGraph(4)).Trace()
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(trace, 6, t)
+	var floatTrace, _ = CreateUndirectedNormalizedLaplacianMatrix(createPathGraph(4)).Trace()
+	expectEqualFloats(floatTrace, 4, t)
+
+	context("the matrix is not square", t)
+
+	it("returns an error", t)
+	_, err = Matrix{{1, 2}}.Trace()
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixMultiply(t *testing.T) {
+	describe("Matrix.Multiply", t)
+
+	it("multiplies rows by columns", t)
+	var product, err = Matrix{{1, 2}, {3, 4}, {5, 6}}.Multiply(Matrix{{1, 0, -1}, {0, 1, 2}})
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(product, [][]int{{1, 2, 3}, {3, 4, 5}, {5, 6, 7}}, t)
+
+	it("gives the undirected signless Laplacian as the incidence matrix times its transpose", t)
+	var graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 2, 1}})
+	var incidence = CreateUndirectedIncidenceMatrix(graph)
+	var transpose, _ = incidence.Transpose()
+	product, _ = incidence.Multiply(transpose)
+	expectEqualMatrices(product, CreateUndirectedSignlessLaplacianMatrix(graph), t)
+
+	it("multiplies matrices of real numbers", t)
+	var floatProduct, _ = FloatMatrix{{0.5, 1}}.Multiply(FloatMatrix{{2}, {0.25}})
+	expectEqualFloats(floatProduct[0][0], 1.25, t)
+
+	context("the shapes do not fit together", t)
+
+	it("returns an error", t)
+	_, err = Matrix{{1, 2}}.Multiply(Matrix{{1, 2}})
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixPower(t *testing.T) {
+	describe("Matrix.Power", t)
+	var cycle = CreateUndirectedAdjacencyMatrix(createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}}))
+
+	it("counts the walks of k edges between every two nodes", t)
+	var walks, err = cycle.Power(3)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(walks, [][]int{{0, 4, 0, 4}, {4, 0, 4, 0}, {0, 4, 0, 4}, {4, 0, 4, 0}}, t)
+
+	it("counts twice the edges on the diagonal of the square", t)
+	walks, _ = cycle.Power(2)
+	var trace, _ = walks.Trace()
+	expectEqualInts(trace, 8, t)
+
+	it("is the identity matrix for k of 0", t)
+	walks, _ = cycle.Power(0)
+	expectEqualMatrices(walks, [][]int{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}, t)
+
+	it("raises matrices of real numbers", t)
+	var floatPower, _ = FloatMatrix{{0.5, 0.5}, {0.5, 0.5}}.Power(5)
+	expectEqualFloats(floatPower[0][1], 0.5, t)
+
+	context("k is negative", t)
+
+	it("returns an error", t)
+	_, err = cycle.Power(-1)
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixFormat(t *testing.T) {
+	describe("Matrix.Format", t)
+	var matrix = Matrix{{0, 12}, {-3, 4}}
+
+	it("aligns the entries under the column labels, beside the row labels", t)
+	var text, err = matrix.Format([]string{"a", "bb"}, []string{"a", "bb"})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(text, "    a bb\n a  0 12\nbb -3  4\n", t)
+
+	it("leaves out labels that are nil", t)
+	expectEqualStrings(matrix.String(), " 0 12\n-3  4\n", t)
+	text, _ = FloatMatrix{{0.5, 1}}.Format(nil, []string{"x", "y"})
+	expectEqualStrings(text, "  x y\n0.5 1\n", t)
+
+	context("there are too few labels", t)
+
+	it("returns an error", t)
+	_, err = matrix.Format([]string{"a"}, nil)
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestMatrixWriteCSV(t *testing.T) {
+	describe("Matrix.WriteCSV", t)
+	var graph = createWeightedDirectedGraph(2, [][3]float64{{0, 1, 1}})
+	var IDs = DirectedNodeIDs(graph)
+
+	it("writes a record for every row after a header of node IDs", t)
+	var buffer bytes.Buffer
+	var err = CreateAdjecencyMatrix(graph).WriteCSV(&buffer, IDs, IDs)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(buffer.String(), ","+IDs[0]+","+IDs[1]+"\n"+IDs[0]+",0,1\n"+IDs[1]+",0,0\n", t)
+
+	it("quotes labels that contain the delimiter", t)
+	buffer.Reset()
+	Matrix{{1}}.WriteCSV(&buffer, []string{"a,b"}, nil)
+	expectEqualStrings(buffer.String(), "\"a,b\",1\n", t)
+
+	context("the values are separated by tabs", t)
+
+	it("writes tab-separated values", t)
+	buffer.Reset()
+	FloatMatrix{{0.5, -1}}.WriteTSV(&buffer, nil, nil)
+	expectEqualStrings(buffer.String(), "0.5\t-1\n", t)
+}
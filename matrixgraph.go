@@ -0,0 +1,368 @@
+package gograph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MatrixGraphOptions names the nodes of a graph built from a matrix. Node i stands for row i
+// of an adjacency or incidence matrix.
+type MatrixGraphOptions struct {
+	IDs    []string            // ID of every node, or the node's row number if empty
+	Values []map[string]string // Values of every node, or none if empty
+}
+
+// nodeIdentities returns the ID of every one of n nodes, or an error if the options do not
+// describe exactly n distinct nodes
+func (options MatrixGraphOptions) nodeIdentities(n int) ([]string, error) {
+	if len(options.Values) > 0 && len(options.Values) != n {
+		return nil, fmt.Errorf("matrix has %d nodes, but %d sets of values were given", n, len(options.Values))
+	}
+	if len(options.IDs) == 0 {
+		var IDs = make([]string, n)
+		for i := range IDs {
+			IDs[i] = strconv.Itoa(i)
+		}
+		return IDs, nil
+	}
+	if len(options.IDs) != n {
+		return nil, fmt.Errorf("matrix has %d nodes, but %d IDs were given", n, len(options.IDs))
+	}
+	var seen = make(map[string]bool, n)
+	for _, ID := range options.IDs {
+		if seen[ID] {
+			return nil, fmt.Errorf("node ID %q was given more than once", ID)
+		}
+		seen[ID] = true
+	}
+	return options.IDs, nil
+}
+
+// value returns the Values of the i-th node, if any were given
+func (options MatrixGraphOptions) value(i int) map[string]string {
+	if len(options.Values) == 0 {
+		return nil
+	}
+	return options.Values[i]
+}
+
+// createNodes returns a graph with an edgeless node for every ID
+func createNodes(IDs []string, options MatrixGraphOptions) Graph {
+	var graph = Graph{Nodes: make([]*Node, len(IDs))}
+	for i, ID := range IDs {
+		graph.Nodes[i] = &Node{ID: ID, Values: options.value(i)}
+	}
+	return graph
+}
+
+// createDirectedNodes returns a directed graph with an edgeless node for every ID, rooted at the
+// first node
+func createDirectedNodes(IDs []string, options MatrixGraphOptions) DirectedGraph {
+	var graph = DirectedGraph{DirectedNodes: make([]*DirectedNode, len(IDs))}
+	for i, ID := range IDs {
+		graph.DirectedNodes[i] = &DirectedNode{ID: ID, Values: options.value(i), Parents: []*DirectedNode{}, Children: []*DirectedNode{}}
+	}
+	if len(IDs) > 0 {
+		graph.RootDirectedNode = graph.DirectedNodes[0]
+	}
+	return graph
+}
+
+// validateSquare checks that every row of a matrix has as many entries as the matrix has rows
+func validateSquare(n int, rowLength func(i int) int) error {
+	for i := 0; i < n; i++ {
+		if rowLength(i) != n {
+			return fmt.Errorf("matrix is not square: row %d has %d entries, but there are %d rows", i, rowLength(i), n)
+		}
+	}
+	return nil
+}
+
+// CreateGraphFromAdjacencyMatrix builds a directed graph from an adjacency matrix whose entry at
+// row i and column j counts the edges from node i to node j, as CreateCSRMatrix(graph).Dense()
+// gives it; CreateAdjecencyMatrix gives the same matrix for graphs without parallel edges. The
+// options name the nodes, and the first node becomes the root. Building the adjacency matrix of
+// the result gives back the matrix exactly. An error is returned if the matrix is not square,
+// has a negative entry, or if the options do not fit it.
+func CreateGraphFromAdjacencyMatrix(matrix Matrix, options MatrixGraphOptions) (DirectedGraph, error) {
+	var n = len(matrix)
+	if err := validateSquare(n, func(i int) int { return len(matrix[i]) }); err != nil {
+		return DirectedGraph{}, err
+	}
+	var IDs, err = options.nodeIdentities(n)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	var rows = compressed{pointers: make([]int, n+1)}
+	for i, row := range matrix {
+		for j, count := range row {
+			if count < 0 {
+				return DirectedGraph{}, fmt.Errorf("matrix has a negative entry in row %d and column %d", i, j)
+			}
+			if count > 0 {
+				rows.indices = append(rows.indices, j)
+				rows.values = append(rows.values, count)
+			}
+		}
+		rows.pointers[i+1] = len(rows.indices)
+	}
+	var graph = createGraphFromRows(IDs, rows)
+	for i, node := range graph.DirectedNodes {
+		node.Values = options.value(i)
+	}
+	return graph, nil
+}
+
+// CreateUndirectedGraphFromAdjacencyMatrix builds an undirected graph from a symmetric adjacency
+// matrix as given by CreateUndirectedAdjacencyMatrix, whose entries count the edges between two
+// nodes and whose diagonal counts each self-loop twice. Building the adjacency matrix of the
+// result gives back the matrix exactly. An error is returned if the matrix is not square and
+// symmetric, has a negative entry or an odd entry on its diagonal, or if the options do not
+// fit it.
+func CreateUndirectedGraphFromAdjacencyMatrix(matrix Matrix, options MatrixGraphOptions) (Graph, error) {
+	var n = len(matrix)
+	if err := validateSquare(n, func(i int) int { return len(matrix[i]) }); err != nil {
+		return Graph{}, err
+	}
+	var IDs, err = options.nodeIdentities(n)
+	if err != nil {
+		return Graph{}, err
+	}
+	for i := range matrix {
+		for j := range matrix[i] {
+			if matrix[i][j] < 0 {
+				return Graph{}, fmt.Errorf("matrix has a negative entry in row %d and column %d", i, j)
+			}
+			if matrix[i][j] != matrix[j][i] {
+				return Graph{}, fmt.Errorf("matrix is not symmetric in row %d and column %d", i, j)
+			}
+		}
+		if matrix[i][i]%2 != 0 {
+			return Graph{}, fmt.Errorf("matrix has an odd entry on its diagonal in row %d, but self-loops count twice", i)
+		}
+	}
+
+	var graph = createNodes(IDs, options)
+	for i := range matrix {
+		for j := i; j < n; j++ {
+			var count = matrix[i][j]
+			if i == j {
+				count /= 2
+			}
+			for ; count > 0; count-- {
+				graph, _, _ = CreateEdge(graph, graph.Nodes[i], graph.Nodes[j])
+			}
+		}
+	}
+	return graph, nil
+}
+
+// incidenceColumns checks that every row of an n×m incidence matrix has m entries, and
+// returns the row of every nonzero entry of every column
+func incidenceColumns(matrix Matrix) ([][]int, error) {
+	if len(matrix) == 0 {
+		return nil, nil
+	}
+	var m = len(matrix[0])
+	var columns = make([][]int, m)
+	for i, row := range matrix {
+		if len(row) != m {
+			return nil, fmt.Errorf("matrix rows differ in length: row %d has %d entries, but row 0 has %d", i, len(row), m)
+		}
+		for k, entry := range row {
+			if entry != 0 {
+				columns[k] = append(columns[k], i)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// CreateGraphFromIncidenceMatrix builds a directed graph from an n×m incidence matrix as given
+// by CreateIncidenceMatrix, whose columns each hold a 1 in the row of an edge's parent and a
+// -1 in the row of its child. Edges are created in column order, so the incidence matrix of
+// the result gives back the matrix exactly when its columns are grouped by parent in row
+// order, as CreateIncidenceMatrix leaves them. The options name the nodes, and the first node
+// becomes the root. An error is returned for any other column, including a column of zeros,
+// from which a self-loop cannot be told apart on any node, or if the options do not fit the
+// matrix.
+func CreateGraphFromIncidenceMatrix(matrix Matrix, options MatrixGraphOptions) (DirectedGraph, error) {
+	var columns, err = incidenceColumns(matrix)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	IDs, err := options.nodeIdentities(len(matrix))
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	var edges = make([][2]int, len(columns))
+	for k, rows := range columns {
+		if len(rows) != 2 || matrix[rows[0]][k]*matrix[rows[1]][k] != -1 {
+			return DirectedGraph{}, fmt.Errorf("column %d must have a 1 for the parent and a -1 for the child of an edge", k)
+		}
+		if matrix[rows[0]][k] == 1 {
+			edges[k] = [2]int{rows[0], rows[1]}
+		} else {
+			edges[k] = [2]int{rows[1], rows[0]}
+		}
+	}
+
+	var graph = createDirectedNodes(IDs, options)
+	for _, edge := range edges {
+		graph, _, _ = CreateDirectedEdge(graph, graph.DirectedNodes[edge[0]], graph.DirectedNodes[edge[1]])
+	}
+	return graph, nil
+}
+
+// CreateUndirectedGraphFromIncidenceMatrix builds an undirected graph from an n×m incidence
+// matrix as given by CreateUndirectedIncidenceMatrix, whose columns each hold a 1 in the rows of
+// an edge's two nodes, or a 2 in the row of a self-loop's node. Edges are created in column
+// order, so the incidence matrix of the result gives back the matrix exactly when its columns
+// are grouped by their first nonzero row in row order, as CreateUndirectedIncidenceMatrix
+// leaves them. An error is returned for any other column, or if the options do not fit the
+// matrix.
+func CreateUndirectedGraphFromIncidenceMatrix(matrix Matrix, options MatrixGraphOptions) (Graph, error) {
+	var columns, err = incidenceColumns(matrix)
+	if err != nil {
+		return Graph{}, err
+	}
+	IDs, err := options.nodeIdentities(len(matrix))
+	if err != nil {
+		return Graph{}, err
+	}
+	var edges = make([][2]int, len(columns))
+	for k, rows := range columns {
+		switch {
+		case len(rows) == 1 && matrix[rows[0]][k] == 2:
+			edges[k] = [2]int{rows[0], rows[0]}
+		case len(rows) == 2 && matrix[rows[0]][k] == 1 && matrix[rows[1]][k] == 1:
+			edges[k] = [2]int{rows[0], rows[1]}
+		default:
+			return Graph{}, fmt.Errorf("column %d must have a 1 for each node of an edge, or a 2 for the node of a self-loop", k)
+		}
+	}
+
+	var graph = createNodes(IDs, options)
+	for _, edge := range edges {
+		graph, _, _ = CreateEdge(graph, graph.Nodes[edge[0]], graph.Nodes[edge[1]])
+	}
+	return graph, nil
+}
+
+// CreateWeightedAdjacencyMatrix returns the adjacency matrix of a directed graph whose entry at
+// row i and column j is the weight of the edge from node i to node j, or 0 if there is none.
+// Parallel edges share their values, so they give a single weight.
+func CreateWeightedAdjacencyMatrix(graph DirectedGraph) FloatMatrix {
+	var n = len(graph.DirectedNodes)
+	var matrix = make(FloatMatrix, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+	}
+	for i, list := range childLists(graph) {
+		for _, j := range list {
+			matrix[i][j] = DirectedEdgeWeight(graph.DirectedNodes[i], graph.DirectedNodes[j])
+		}
+	}
+	return matrix
+}
+
+// CreateUndirectedWeightedAdjacencyMatrix returns the symmetric adjacency matrix of an
+// undirected graph whose entries are the weights of the edges between every two nodes, or 0
+// where there is none. A self-loop's weight appears once on the diagonal.
+func CreateUndirectedWeightedAdjacencyMatrix(graph Graph) FloatMatrix {
+	var indices = indexNodes(graph)
+	var n = len(graph.Nodes)
+	var matrix = make(FloatMatrix, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+	}
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok {
+				matrix[i][j] = EdgeWeight(node, neighbor)
+			}
+		}
+	}
+	return matrix
+}
+
+// validateWeights checks that a weighted adjacency matrix is square and its entries are finite
+func validateWeights(matrix FloatMatrix) error {
+	if err := validateSquare(len(matrix), func(i int) int { return len(matrix[i]) }); err != nil {
+		return err
+	}
+	for i, row := range matrix {
+		for j, weight := range row {
+			if math.IsNaN(weight) || math.IsInf(weight, 0) {
+				return fmt.Errorf("matrix has an entry that is not a finite number in row %d and column %d", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// formatWeight encodes an edge weight as an edge value that parses back to the same number
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'g', -1, 64)
+}
+
+// CreateGraphFromWeightedAdjacencyMatrix builds a directed graph from a weighted adjacency
+// matrix as given by CreateWeightedAdjacencyMatrix, with an edge from node i to node j, weighted
+// by the entry at row i and column j, wherever that entry is not 0. The weighted adjacency
+// matrix of the result gives back the matrix exactly. The options name the nodes, and the first
+// node becomes the root. An error is returned if the matrix is not square, has an entry that
+// is not a finite number, or if the options do not fit it.
+func CreateGraphFromWeightedAdjacencyMatrix(matrix FloatMatrix, options MatrixGraphOptions) (DirectedGraph, error) {
+	if err := validateWeights(matrix); err != nil {
+		return DirectedGraph{}, err
+	}
+	var IDs, err = options.nodeIdentities(len(matrix))
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	var graph = createDirectedNodes(IDs, options)
+	for i, row := range matrix {
+		for j, weight := range row {
+			if weight != 0 {
+				var parent, child = graph.DirectedNodes[i], graph.DirectedNodes[j]
+				graph, _, _ = CreateDirectedEdge(graph, parent, child)
+				SetDirectedEdgeValue(parent, child, WeightKey, formatWeight(weight))
+			}
+		}
+	}
+	return graph, nil
+}
+
+// CreateUndirectedGraphFromWeightedAdjacencyMatrix builds an undirected graph from a symmetric
+// weighted adjacency matrix as given by CreateUndirectedWeightedAdjacencyMatrix, with an edge
+// weighted by every entry on or above the diagonal that is not 0. The weighted adjacency matrix
+// of the result gives back the matrix exactly. An error is returned if the matrix is not square
+// and symmetric, has an entry that is not a finite number, or if the options do not fit it.
+func CreateUndirectedGraphFromWeightedAdjacencyMatrix(matrix FloatMatrix, options MatrixGraphOptions) (Graph, error) {
+	if err := validateWeights(matrix); err != nil {
+		return Graph{}, err
+	}
+	for i := range matrix {
+		for j := range matrix[i] {
+			if matrix[i][j] != matrix[j][i] {
+				return Graph{}, fmt.Errorf("matrix is not symmetric in row %d and column %d", i, j)
+			}
+		}
+	}
+	var IDs, err = options.nodeIdentities(len(matrix))
+	if err != nil {
+		return Graph{}, err
+	}
+	var graph = createNodes(IDs, options)
+	for i := range matrix {
+		for j := i; j < len(matrix); j++ {
+			if matrix[i][j] != 0 {
+				var a, b = graph.Nodes[i], graph.Nodes[j]
+				graph, _, _ = CreateEdge(graph, a, b)
+				SetEdgeValue(a, b, WeightKey, formatWeight(matrix[i][j]))
+			}
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,159 @@
+package gograph
+
+import (
+	"testing"
+)
+
+func TestCreateGraphFromAdjacencyMatrix(t *testing.T) {
+	describe("CreateGraphFromAdjacencyMatrix", t)
+	var matrix = [][]int{
+		{0, 1, 1, 0},
+		{0, 0, 0, 2},
+		{0, 0, 1, 0},
+		{1, 0, 0, 0},
+	}
+	var options = MatrixGraphOptions{
+		IDs:    []string{"a", "b", "c", "d"},
+		Values: []map[string]string{{"name": "nodeA"}, nil, nil, {"name": "nodeD"}},
+	}
+	var graph, err = CreateGraphFromAdjacencyMatrix(matrix, options)
+
+	it("names the nodes from the options", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(graph.RootDirectedNode.ID, "a", t)
+	expectEqualStrings(graph.DirectedNodes[3].ID, "d", t)
+	expectEqualStrings(graph.DirectedNodes[3].Values["name"], "nodeD", t)
+
+	it("round-trips the matrix", t)
+	expectEqualMatrices(CreateCSRMatrix(graph).Dense(), matrix, t)
+
+	context("no IDs are given", t)
+	graph, _ = CreateGraphFromAdjacencyMatrix(matrix, MatrixGraphOptions{})
+
+	it("names the nodes by row", t)
+	expectEqualStrings(graph.DirectedNodes[2].ID, "2", t)
+
+	context("the matrix is not square", t)
+	_, err = CreateGraphFromAdjacencyMatrix([][]int{{0, 1}, {0}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+
+	context("an ID is repeated", t)
+	_, err = CreateGraphFromAdjacencyMatrix([][]int{{0, 1}, {0, 0}}, MatrixGraphOptions{IDs: []string{"a", "a"}})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestCreateUndirectedGraphFromAdjacencyMatrix(t *testing.T) {
+	describe("CreateUndirectedGraphFromAdjacencyMatrix", t)
+	var original = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {0, 1, 1}, {1, 2, 1}, {2, 2, 1}, {3, 0, 1}})
+	var matrix = CreateUndirectedAdjacencyMatrix(original)
+	var graph, err = CreateUndirectedGraphFromAdjacencyMatrix(matrix, MatrixGraphOptions{IDs: nodeIDs(original)})
+
+	it("round-trips the matrix", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(CreateUndirectedAdjacencyMatrix(graph), matrix, t)
+	expectEqualStrings(graph.Nodes[1].ID, original.Nodes[1].ID, t)
+
+	context("the matrix is not symmetric", t)
+	_, err = CreateUndirectedGraphFromAdjacencyMatrix([][]int{{0, 1}, {0, 0}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+
+	context("a self-loop is not counted twice", t)
+	_, err = CreateUndirectedGraphFromAdjacencyMatrix([][]int{{1}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestCreateGraphFromIncidenceMatrix(t *testing.T) {
+	describe("CreateGraphFromIncidenceMatrix", t)
+	var original = createWeightedDirectedGraph(4, [][3]float64{{0, 2, 1}, {0, 1, 1}, {1, 3, 1}, {1, 3, 1}, {3, 0, 1}})
+	var matrix = CreateIncidenceMatrix(original)
+	var graph, err = CreateGraphFromIncidenceMatrix(matrix, MatrixGraphOptions{IDs: directedNodeIDs(original)})
+
+	it("round-trips the matrix", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(CreateIncidenceMatrix(graph), matrix, t)
+	expectEqualStrings(graph.DirectedNodes[0].Children[0].ID, original.DirectedNodes[2].ID, t)
+
+	context("a column is all zeros", t)
+	_, err = CreateGraphFromIncidenceMatrix([][]int{{1, 0}, {-1, 0}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+
+	context("a column has two parents", t)
+	_, err = CreateGraphFromIncidenceMatrix([][]int{{1}, {1}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestCreateUndirectedGraphFromIncidenceMatrix(t *testing.T) {
+	describe("CreateUndirectedGraphFromIncidenceMatrix", t)
+	var original = createWeightedGraph(4, [][3]float64{{0, 2, 1}, {0, 1, 1}, {1, 2, 1}, {2, 2, 1}, {3, 0, 1}})
+	var matrix = CreateUndirectedIncidenceMatrix(original)
+	var graph, err = CreateUndirectedGraphFromIncidenceMatrix(matrix, MatrixGraphOptions{})
+
+	it("round-trips the matrix", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(CreateUndirectedIncidenceMatrix(graph), matrix, t)
+
+	context("a column has three nodes", t)
+	_, err = CreateUndirectedGraphFromIncidenceMatrix([][]int{{1}, {1}, {1}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestCreateGraphFromWeightedAdjacencyMatrix(t *testing.T) {
+	describe("CreateGraphFromWeightedAdjacencyMatrix", t)
+	var matrix = [][]float64{
+		{0, 2.5, 0},
+		{0, 0, -1},
+		{0.1, 0, 3},
+	}
+	var graph, err = CreateGraphFromWeightedAdjacencyMatrix(matrix, MatrixGraphOptions{})
+
+	it("weights every edge by its entry", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(DirectedEdgeWeight(graph.DirectedNodes[0], graph.DirectedNodes[1]), 2.5, t)
+
+	it("round-trips the matrix", t)
+	var roundTrip = CreateWeightedAdjacencyMatrix(graph)
+	for i := range matrix {
+		for j := range matrix[i] {
+			expectEqualBools(roundTrip[i][j] == matrix[i][j], true, t)
+		}
+	}
+}
+
+func TestCreateUndirectedGraphFromWeightedAdjacencyMatrix(t *testing.T) {
+	describe("CreateUndirectedGraphFromWeightedAdjacencyMatrix", t)
+	var matrix = [][]float64{
+		{0, 0.3, 7},
+		{0.3, 2, 0},
+		{7, 0, 0},
+	}
+	var graph, err = CreateUndirectedGraphFromWeightedAdjacencyMatrix(matrix, MatrixGraphOptions{})
+
+	it("round-trips the matrix", t)
+	expectEqualBools(err == nil, true, t)
+	var roundTrip = CreateUndirectedWeightedAdjacencyMatrix(graph)
+	for i := range matrix {
+		for j := range matrix[i] {
+			expectEqualBools(roundTrip[i][j] == matrix[i][j], true, t)
+		}
+	}
+
+	context("the matrix is not symmetric", t)
+	_, err = CreateUndirectedGraphFromWeightedAdjacencyMatrix([][]float64{{0, 1}, {2, 0}}, MatrixGraphOptions{})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
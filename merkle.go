@@ -0,0 +1,151 @@
+package gograph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MerkleIndex holds the content hash of every node of a directed graph, computed like a git
+// tree from the node's Values and the hashes of its children. Once enabled on a graph, the
+// hashes are kept up to date as CreateDirectedNode, CreateDirectedEdge, CreateMerkleEdge and
+// DeleteDirectedEdge change it.
+type MerkleIndex struct {
+	Hashes map[string]string // hash of every node, keyed by node ID
+}
+
+// EnableMerkleHashing computes the Merkle hash of every node of a directed acyclic graph and
+// attaches the index to the graph so that later edge changes rehash the affected ancestors.
+// An error is returned if the graph has a cycle. Edges added afterwards must keep the graph
+// acyclic: CreateMerkleEdge rejects those that would not, at the cost of searching the
+// descendants of the child, which is O(V+E) per edge, while CreateDirectedEdge skips the check.
+func EnableMerkleHashing(graph DirectedGraph) (DirectedGraph, error) {
+	var index = &MerkleIndex{Hashes: make(map[string]string, len(graph.DirectedNodes))}
+	var state = map[*DirectedNode]int{} // 1 while on the depth-first search stack, 2 once hashed
+	var visit func(node *DirectedNode) bool
+	visit = func(node *DirectedNode) bool {
+		state[node] = 1
+		for _, child := range node.Children {
+			if state[child] == 1 || (state[child] == 0 && !visit(child)) {
+				return false
+			}
+		}
+		index.Hashes[node.ID] = index.compute(node)
+		state[node] = 2
+		return true
+	}
+	for _, node := range graph.DirectedNodes {
+		if state[node] == 0 && !visit(node) {
+			return graph, errors.New("graph has a cycle and cannot be Merkle hashed")
+		}
+	}
+	graph.Merkle = index
+	return graph, nil
+}
+
+// CreateMerkleEdge creates a parent-child relationship between two nodes like
+// CreateDirectedEdge, unless Merkle hashing is enabled on the graph and the parent can be
+// reached from the child, in which case the edge would close a cycle and an error is returned
+// with the graph unchanged.
+func CreateMerkleEdge(graph DirectedGraph, parent *DirectedNode, child *DirectedNode) (DirectedGraph, error) {
+	if graph.Merkle != nil && reaches(child, parent) {
+		return graph, fmt.Errorf("edge from %q to %q would create a cycle in a Merkle hashed graph", parent.ID, child.ID)
+	}
+	graph, _, _ = CreateDirectedEdge(graph, parent, child)
+	return graph, nil
+}
+
+// MerkleHash returns the Merkle hash of a node, or an empty string if Merkle hashing is not
+// enabled on the graph.
+func MerkleHash(graph DirectedGraph, node *DirectedNode) string {
+	if graph.Merkle == nil {
+		return ""
+	}
+	return graph.Merkle.Hashes[node.ID]
+}
+
+// UpdateMerkleHashes rehashes a node and its ancestors, as is needed after changing the
+// node's Values. It does nothing if Merkle hashing is not enabled on the graph.
+func UpdateMerkleHashes(graph DirectedGraph, node *DirectedNode) {
+	if graph.Merkle != nil {
+		graph.Merkle.update(node)
+	}
+}
+
+// compute hashes a node's values along with the hash and edge values of each of its children.
+// Both are sorted so that the order in which values and edges were added does not matter.
+func (index *MerkleIndex) compute(node *DirectedNode) string {
+	var keys = make([]string, 0, len(node.Values))
+	for key := range node.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries = make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		var edgeValues = node.EdgeValues[child.ID]
+		var edgeKeys = make([]string, 0, len(edgeValues))
+		for key := range edgeValues {
+			edgeKeys = append(edgeKeys, key)
+		}
+		sort.Strings(edgeKeys)
+		entries = append(entries, index.Hashes[child.ID]+" "+selectedValues(edgeValues, edgeKeys))
+	}
+	sort.Strings(entries)
+
+	return hashStrings(selectedValues(node.Values, keys), hashStrings(entries...))
+}
+
+// update rehashes a node and every one of its ancestors, children before parents, leaving
+// the rest of the graph untouched.
+func (index *MerkleIndex) update(node *DirectedNode) {
+	var affected = map[*DirectedNode]bool{node: true}
+	var queue = []*DirectedNode{node}
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+		for _, parent := range current.Parents {
+			if !affected[parent] {
+				affected[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	var hashed = map[*DirectedNode]bool{}
+	var visit func(current *DirectedNode)
+	visit = func(current *DirectedNode) {
+		hashed[current] = true
+		for _, child := range current.Children {
+			if affected[child] && !hashed[child] {
+				visit(child)
+			}
+		}
+		index.Hashes[current.ID] = index.compute(current)
+	}
+	for current := range affected {
+		if !hashed[current] {
+			visit(current)
+		}
+	}
+}
+
+// reaches reports whether a node can be reached from another by following edges from parent to child
+func reaches(from *DirectedNode, to *DirectedNode) bool {
+	var visited = map[*DirectedNode]bool{from: true}
+	var stack = []*DirectedNode{from}
+	for len(stack) > 0 {
+		var current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == to {
+			return true
+		}
+		for _, child := range current.Children {
+			if !visited[child] {
+				visited[child] = true
+				stack = append(stack, child)
+			}
+		}
+	}
+	return false
+}
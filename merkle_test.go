@@ -0,0 +1,104 @@
+package gograph
+
+import (
+	"testing"
+)
+
+func TestEnableMerkleHashing(t *testing.T) {
+	describe("EnableMerkleHashing", t)
+
+	context("two graphs have the same content", t)
+	var edges = [][3]float64{{0, 1, 1}, {0, 2, 1}, {1, 3, 1}, {2, 3, 1}}
+	var a, err = EnableMerkleHashing(createWeightedDirectedGraph(4, edges))
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+	var b, _ = EnableMerkleHashing(createWeightedDirectedGraph(4, edges))
+
+	it("hashes their nodes alike despite their random node IDs", t)
+	for index := range a.DirectedNodes {
+		expectEqualStrings(MerkleHash(a, a.DirectedNodes[index]), MerkleHash(b, b.DirectedNodes[index]), t)
+	}
+	expectEqualInts(len(MerkleHash(a, a.RootDirectedNode)), 64, t)
+
+	context("nodes differ only in their values", t)
+	it("hashes them differently", t)
+	expectEqualBools(MerkleHash(a, a.DirectedNodes[1]) == MerkleHash(a, a.DirectedNodes[2]), false, t)
+
+	context("the graph has a cycle", t)
+	_, err = EnableMerkleHashing(createWeightedDirectedGraph(2, [][3]float64{{0, 1, 1}, {1, 0, 1}}))
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+
+	context("Merkle hashing is not enabled", t)
+	it("returns empty hashes", t)
+	expectEqualStrings(MerkleHash(createWeightedDirectedGraph(1, nil), a.RootDirectedNode), "", t)
+}
+
+func TestMerkleHashUpdates(t *testing.T) {
+	describe("MerkleHash", t)
+	//  0 -> 1 -> 2    3
+	var graph, _ = EnableMerkleHashing(createWeightedDirectedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}}))
+	var nodes = graph.DirectedNodes
+	var original = make([]string, len(nodes))
+	for index, node := range nodes {
+		original[index] = MerkleHash(graph, node)
+	}
+
+	context("an edge is created", t)
+	graph, _, _ = CreateDirectedEdge(graph, nodes[2], nodes[3])
+
+	it("rehashes the parent and its ancestors only", t)
+	for index := 0; index < 3; index++ {
+		expectEqualBools(MerkleHash(graph, nodes[index]) == original[index], false, t)
+	}
+	expectEqualStrings(MerkleHash(graph, nodes[3]), original[3], t)
+
+	context("the edge is deleted", t)
+	graph, _, _ = DeleteDirectedEdge(graph, nodes[2], nodes[3])
+
+	it("restores the previous hashes", t)
+	for index, node := range nodes {
+		expectEqualStrings(MerkleHash(graph, node), original[index], t)
+	}
+
+	context("a node is created with a parent", t)
+	var node *DirectedNode
+	graph, node = CreateDirectedNode(graph, map[string]string{"name": "4"}, []*DirectedNode{nodes[1]}, []*DirectedNode{})
+
+	it("hashes the new node and rehashes its ancestors", t)
+	expectEqualInts(len(MerkleHash(graph, node)), 64, t)
+	expectEqualBools(MerkleHash(graph, nodes[0]) == original[0], false, t)
+	expectEqualStrings(MerkleHash(graph, nodes[2]), original[2], t)
+
+	context("the values of a node change", t)
+	var before = MerkleHash(graph, nodes[0])
+	nodes[2].Values["name"] = "changed"
+	UpdateMerkleHashes(graph, nodes[2])
+
+	it("rehashes the node and its ancestors", t)
+	expectEqualBools(MerkleHash(graph, nodes[2]) == original[2], false, t)
+	expectEqualBools(MerkleHash(graph, nodes[0]) == before, false, t)
+
+	context("an edge would create a cycle", t)
+	before = MerkleHash(graph, nodes[0])
+	var err error
+	graph, err = CreateMerkleEdge(graph, nodes[2], nodes[0])
+
+	it("returns an error and leaves the graph unchanged", t)
+	expectEqualBools(err != nil, true, t)
+	expectEqualInts(len(nodes[2].Children), 0, t)
+	expectEqualStrings(MerkleHash(graph, nodes[0]), before, t)
+
+	context("an edge keeps the graph acyclic", t)
+	graph, err = CreateMerkleEdge(graph, nodes[3], nodes[0])
+
+	it("creates the edge and rehashes its parent", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(len(nodes[3].Children), 1, t)
+	expectEqualBools(MerkleHash(graph, nodes[3]) == original[3], false, t)
+}
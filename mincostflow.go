@@ -0,0 +1,196 @@
+package gograph
+
+import (
+	"errors"
+	"math"
+)
+
+// CostKey is the edge value key under which the cost per unit of flow along an edge is stored
+const CostKey = "cost"
+
+// DirectedEdgeCost returns the cost of the directed edge from a parent to a child node.
+// Edges without a cost are treated as free.
+func DirectedEdgeCost(parent *DirectedNode, child *DirectedNode) float64 {
+	return edgeFloat(parent.EdgeValues, child.ID, CostKey, 0)
+}
+
+// MinCostMaxFlow computes the maximum flow from a source to a sink node that has the
+// lowest total cost, returning the flow along with that cost. Capacities and costs are
+// read from the CapacityKey and CostKey edge values; negative costs are allowed so long as
+// the graph has no negative cost cycle.
+func MinCostMaxFlow(graph DirectedGraph, source *DirectedNode, sink *DirectedNode) (FlowResult, float64, error) {
+	var network, err = newFlowNetwork(graph, source, sink)
+	if err != nil {
+		return FlowResult{}, 0, err
+	}
+
+	// Successively augment along the cheapest path of the residual network, found with
+	// a queue-based Bellman-Ford search since reverse arcs carry negative costs.
+	var n = len(network.arcs)
+	var total float64
+	for {
+		var distance = make([]float64, n)
+		var previousNode = make([]int, n)
+		var previousArc = make([]int, n)
+		var queued = make([]bool, n)
+		var visits = make([]int, n)
+		for i := range distance {
+			distance[i] = math.Inf(1)
+			previousNode[i] = -1
+		}
+		distance[network.source] = 0
+		var queue = []int{network.source}
+		queued[network.source] = true
+		for len(queue) > 0 {
+			var u = queue[0]
+			queue = queue[1:]
+			queued[u] = false
+			for index, arc := range network.arcs[u] {
+				if arc.residual() <= flowEpsilon || distance[u]+arc.cost >= distance[arc.to]-flowEpsilon {
+					continue
+				}
+				distance[arc.to] = distance[u] + arc.cost
+				previousNode[arc.to] = u
+				previousArc[arc.to] = index
+				if !queued[arc.to] {
+					visits[arc.to]++
+					if visits[arc.to] > n {
+						return FlowResult{}, 0, errors.New("graph has a negative cost cycle")
+					}
+					queued[arc.to] = true
+					queue = append(queue, arc.to)
+				}
+			}
+		}
+		if math.IsInf(distance[network.sink], 1) {
+			break
+		}
+
+		var bottleneck = math.Inf(1)
+		for v := network.sink; v != network.source; v = previousNode[v] {
+			bottleneck = math.Min(bottleneck, network.arcs[previousNode[v]][previousArc[v]].residual())
+		}
+		if math.IsInf(bottleneck, 1) {
+			return FlowResult{}, 0, errors.New("graph has a path of unbounded capacity")
+		}
+		for v := network.sink; v != network.source; v = previousNode[v] {
+			network.push(previousNode[v], previousArc[v], bottleneck)
+		}
+		total += bottleneck * distance[network.sink]
+	}
+	return network.result(), total, nil
+}
+
+// HungarianAssignment solves the assignment problem between workers and jobs, where every
+// edge from a worker to a job is an allowed assignment costing its CostKey edge value. Each
+// worker is assigned a distinct job (or, if there are fewer jobs than workers, each job a
+// distinct worker) so that the total cost is minimal. The assignment is returned as edges
+// from worker to job IDs. An error is returned if the edges do not allow such an assignment.
+func HungarianAssignment(graph DirectedGraph, workers []*DirectedNode, jobs []*DirectedNode) ([]Edge, float64, error) {
+	var rows, columns = workers, jobs
+	var transposed = len(workers) > len(jobs)
+	if transposed {
+		rows, columns = jobs, workers
+	}
+	var n, m = len(rows), len(columns)
+	if n == 0 {
+		return nil, 0, nil
+	}
+
+	// Disallowed assignments cost more than any assignment made only of allowed ones
+	var allowed = make([][]bool, n)
+	var costs = make([][]float64, n)
+	var magnitude float64
+	for i := range rows {
+		allowed[i] = make([]bool, m)
+		costs[i] = make([]float64, m)
+		for j := range columns {
+			var worker, job = rows[i], columns[j]
+			if transposed {
+				worker, job = job, worker
+			}
+			for _, child := range worker.Children {
+				if child == job {
+					allowed[i][j] = true
+					costs[i][j] = DirectedEdgeCost(worker, job)
+					magnitude += math.Abs(costs[i][j])
+					break
+				}
+			}
+		}
+	}
+	var forbidden = 2*magnitude + 1
+	for i := range costs {
+		for j := range costs[i] {
+			if !allowed[i][j] {
+				costs[i][j] = forbidden
+			}
+		}
+	}
+
+	// Potentials u and v over 1-indexed rows and columns; column 0 is a sentinel
+	var u = make([]float64, n+1)
+	var v = make([]float64, m+1)
+	var match = make([]int, m+1) // row matched to each column
+	var way = make([]int, m+1)
+	for i := 1; i <= n; i++ {
+		match[0] = i
+		var j0 = 0
+		var minimum = make([]float64, m+1)
+		var used = make([]bool, m+1)
+		for j := range minimum {
+			minimum[j] = math.Inf(1)
+		}
+		for match[j0] != 0 {
+			used[j0] = true
+			var i0, delta, j1 = match[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				var reduced = costs[i0-1][j-1] - u[i0] - v[j]
+				if reduced < minimum[j] {
+					minimum[j] = reduced
+					way[j] = j0
+				}
+				if minimum[j] < delta {
+					delta = minimum[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					minimum[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		for j0 != 0 {
+			var j1 = way[j0]
+			match[j0] = match[j1]
+			j0 = j1
+		}
+	}
+
+	var assignment []Edge
+	var total float64
+	for j := 1; j <= m; j++ {
+		if match[j] == 0 {
+			continue
+		}
+		var i = match[j] - 1
+		if !allowed[i][j-1] {
+			return nil, 0, errors.New("edges do not allow every worker or job to be assigned")
+		}
+		var worker, job = rows[i], columns[j-1]
+		if transposed {
+			worker, job = job, worker
+		}
+		assignment = append(assignment, Edge{From: worker.ID, To: job.ID})
+		total += costs[i][j-1]
+	}
+	return assignment, total, nil
+}
@@ -0,0 +1,96 @@
+package gograph
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMinCostMaxFlow(t *testing.T) {
+	describe("MinCostMaxFlow", t)
+
+	context("there are several ways to route the maximum flow", t)
+	// s=0, a=1, b=2, c=3, t=4
+	var graph = createValuedDirectedGraph(5, CapacityKey, [][3]float64{
+		{0, 1, 2}, {1, 4, 1}, {1, 3, 1}, {3, 4, 1}, {0, 2, 1}, {2, 3, 1},
+	})
+	var nodes = graph.DirectedNodes
+	for _, edge := range [][3]int{{0, 1, 1}, {1, 4, 1}, {1, 3, 5}, {3, 4, 1}, {0, 2, 1}, {2, 3, 1}} {
+		SetDirectedEdgeValue(nodes[edge[0]], nodes[edge[1]], CostKey, strconv.Itoa(edge[2]))
+	}
+	var result, cost, err = MinCostMaxFlow(graph, nodes[0], nodes[4])
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("returns the maximum flow value", t)
+	expectEqualFloats(result.Value, 2, t)
+
+	it("routes the flow along the cheapest paths", t)
+	expectEqualFloats(cost, 5, t)
+	expectEqualFloats(result.Flow[Edge{From: nodes[1].ID, To: nodes[3].ID}], 0, t)
+	expectEqualFloats(result.Flow[Edge{From: nodes[2].ID, To: nodes[3].ID}], 1, t)
+
+	context("the graph has a negative cost cycle", t)
+	graph = createValuedDirectedGraph(4, CapacityKey, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 1, 1}, {2, 3, 1}})
+	nodes = graph.DirectedNodes
+	SetDirectedEdgeValue(nodes[1], nodes[2], CostKey, "-2")
+	SetDirectedEdgeValue(nodes[2], nodes[1], CostKey, "-2")
+	_, _, err = MinCostMaxFlow(graph, nodes[0], nodes[3])
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
+
+func TestHungarianAssignment(t *testing.T) {
+	describe("HungarianAssignment", t)
+
+	// Workers 0-2 and jobs 3-5
+	var costs = [][]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}
+	var edges [][3]float64
+	for i, row := range costs {
+		for j, cost := range row {
+			edges = append(edges, [3]float64{float64(i), float64(j + 3), cost})
+		}
+	}
+
+	context("there are as many workers as jobs", t)
+	var graph = createValuedDirectedGraph(6, CostKey, edges)
+	var workers, jobs = graph.DirectedNodes[:3], graph.DirectedNodes[3:]
+	var assignment, total, err = HungarianAssignment(graph, workers, jobs)
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+
+	it("returns the minimum total cost", t)
+	expectEqualFloats(total, 5, t)
+
+	it("assigns every worker a distinct job", t)
+	expectEqualInts(len(assignment), 3, t)
+	var assigned = map[string]string{}
+	for _, edge := range assignment {
+		assigned[edge.From] = edge.To
+	}
+	expectEqualStrings(assigned[workers[0].ID], jobs[1].ID, t)
+	expectEqualStrings(assigned[workers[1].ID], jobs[0].ID, t)
+	expectEqualStrings(assigned[workers[2].ID], jobs[2].ID, t)
+
+	context("there are fewer jobs than workers", t)
+	assignment, total, _ = HungarianAssignment(graph, workers, jobs[:2])
+
+	it("assigns every job a distinct worker", t)
+	expectEqualInts(len(assignment), 2, t)
+	expectEqualFloats(total, 3, t)
+
+	context("a worker has no allowed job", t)
+	graph = createValuedDirectedGraph(4, CostKey, [][3]float64{{0, 2, 1}, {0, 3, 1}})
+	_, _, err = HungarianAssignment(graph, graph.DirectedNodes[:2], graph.DirectedNodes[2:])
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
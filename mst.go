@@ -0,0 +1,338 @@
+package gograph
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+)
+
+// weightedEdge is an edge between two node indices of a graph
+type weightedEdge struct {
+	from   int
+	to     int
+	weight float64
+}
+
+// disjointSet is a union-find structure over the integers [0, n)
+type disjointSet struct {
+	parent []int
+	rank   []int
+}
+
+func newDisjointSet(n int) *disjointSet {
+	var set = &disjointSet{parent: make([]int, n), rank: make([]int, n)}
+	for i := range set.parent {
+		set.parent[i] = i
+	}
+	return set
+}
+
+func (set *disjointSet) find(x int) int {
+	for set.parent[x] != x {
+		set.parent[x] = set.parent[set.parent[x]]
+		x = set.parent[x]
+	}
+	return x
+}
+
+// union merges the sets containing x and y, and reports whether they were disjoint
+func (set *disjointSet) union(x int, y int) bool {
+	var rootX, rootY = set.find(x), set.find(y)
+	if rootX == rootY {
+		return false
+	}
+	if set.rank[rootX] < set.rank[rootY] {
+		rootX, rootY = rootY, rootX
+	}
+	set.parent[rootY] = rootX
+	if set.rank[rootX] == set.rank[rootY] {
+		set.rank[rootX]++
+	}
+	return true
+}
+
+// indexNodes maps each node of a graph to its index in graph.Nodes
+func indexNodes(graph Graph) map[*Node]int {
+	var indices = make(map[*Node]int, len(graph.Nodes))
+	for index, node := range graph.Nodes {
+		indices[node] = index
+	}
+	return indices
+}
+
+// indexDirectedNodes maps each node of a directed graph to its index in graph.DirectedNodes
+func indexDirectedNodes(graph DirectedGraph) map[*DirectedNode]int {
+	var indices = make(map[*DirectedNode]int, len(graph.DirectedNodes))
+	for index, node := range graph.DirectedNodes {
+		indices[node] = index
+	}
+	return indices
+}
+
+// undirectedEdges lists every undirected edge of the graph once, excluding self-loops
+func undirectedEdges(graph Graph) []weightedEdge {
+	var indices = indexNodes(graph)
+	var edges []weightedEdge
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			var j, ok = indices[neighbor]
+			if ok && i < j {
+				edges = append(edges, weightedEdge{from: i, to: j, weight: EdgeWeight(node, neighbor)})
+			}
+		}
+	}
+	return edges
+}
+
+// copyNodes returns a graph of edgeless copies of the graph's nodes, in the same order and
+// sharing their Values
+func copyNodes(graph Graph) Graph {
+	var copied = Graph{Nodes: make([]*Node, len(graph.Nodes))}
+	for index, node := range graph.Nodes {
+		copied.Nodes[index] = &Node{ID: node.ID, Values: node.Values}
+	}
+	return copied
+}
+
+// copyEdge creates the edge between the i-th and j-th nodes of the source graph in the
+// target graph, carrying over the values of the edge.
+func copyEdge(source Graph, target Graph, i int, j int) Graph {
+	var a, b = target.Nodes[i], target.Nodes[j]
+	target, _, _ = CreateEdge(target, a, b)
+	for key, value := range source.Nodes[i].EdgeValues[source.Nodes[j].ID] {
+		SetEdgeValue(a, b, key, value)
+	}
+	return target
+}
+
+// KruskalMinimumSpanningForest returns the minimum spanning forest of an undirected graph
+// along with its total weight. The forest contains a copy of every node of the graph, so a
+// disconnected graph yields one spanning tree per connected component.
+func KruskalMinimumSpanningForest(graph Graph) (Graph, float64) {
+	var forest = copyNodes(graph)
+	var edges = undirectedEdges(graph)
+	var set = newDisjointSet(len(graph.Nodes))
+	var total float64
+
+	sort.SliceStable(edges, func(a, b int) bool { return edges[a].weight < edges[b].weight })
+	for _, edge := range edges {
+		if set.union(edge.from, edge.to) {
+			forest = copyEdge(graph, forest, edge.from, edge.to)
+			total += edge.weight
+		}
+	}
+	return forest, total
+}
+
+// edgeHeap is a min-heap of weighted edges ordered by weight
+type edgeHeap []weightedEdge
+
+func (h edgeHeap) Len() int            { return len(h) }
+func (h edgeHeap) Less(i, j int) bool  { return h[i].weight < h[j].weight }
+func (h edgeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *edgeHeap) Push(x interface{}) { *h = append(*h, x.(weightedEdge)) }
+func (h *edgeHeap) Pop() interface{} {
+	var old = *h
+	var edge = old[len(old)-1]
+	*h = old[:len(old)-1]
+	return edge
+}
+
+// PrimMinimumSpanningForest returns the minimum spanning forest of an undirected graph
+// along with its total weight. A new tree is grown from every node not yet reached, so a
+// disconnected graph yields one spanning tree per connected component.
+func PrimMinimumSpanningForest(graph Graph) (Graph, float64) {
+	var forest = copyNodes(graph)
+	var indices = indexNodes(graph)
+	var visited = make([]bool, len(graph.Nodes))
+	var total float64
+
+	var visit = func(i int, frontier *edgeHeap) {
+		visited[i] = true
+		for _, neighbor := range graph.Nodes[i].Edges {
+			var j, ok = indices[neighbor]
+			if ok && !visited[j] {
+				heap.Push(frontier, weightedEdge{from: i, to: j, weight: EdgeWeight(graph.Nodes[i], neighbor)})
+			}
+		}
+	}
+
+	for start := range graph.Nodes {
+		if visited[start] {
+			continue
+		}
+		var frontier = &edgeHeap{}
+		visit(start, frontier)
+		for frontier.Len() > 0 {
+			var edge = heap.Pop(frontier).(weightedEdge)
+			if visited[edge.to] {
+				continue
+			}
+			forest = copyEdge(graph, forest, edge.from, edge.to)
+			total += edge.weight
+			visit(edge.to, frontier)
+		}
+	}
+	return forest, total
+}
+
+// MinimumSpanningArborescence implements the Chu-Liu/Edmonds algorithm to find the minimum
+// weight spanning arborescence of a directed graph rooted at its RootDirectedNode. The
+// returned graph contains copies of every node and the selected parent-child edges. An
+// error is returned if some node is unreachable from the root.
+func MinimumSpanningArborescence(graph DirectedGraph) (DirectedGraph, float64, error) {
+	var arborescence = CreateGraph()
+	if graph.RootDirectedNode == nil {
+		return arborescence, 0, errors.New("graph has no root node")
+	}
+
+	var indices = indexDirectedNodes(graph)
+	var root, ok = indices[graph.RootDirectedNode]
+	if !ok {
+		return arborescence, 0, errors.New("root node is not in the graph")
+	}
+
+	var edges []weightedEdge
+	for i, parent := range graph.DirectedNodes {
+		for _, child := range parent.Children {
+			if j, ok := indices[child]; ok {
+				edges = append(edges, weightedEdge{from: i, to: j, weight: DirectedEdgeWeight(parent, child)})
+			}
+		}
+	}
+
+	var chosen, found = chuLiuEdmonds(len(graph.DirectedNodes), root, edges)
+	if !found {
+		return arborescence, 0, errors.New("not every node is reachable from the root node")
+	}
+
+	var copies = make([]*DirectedNode, len(graph.DirectedNodes))
+	for index, node := range graph.DirectedNodes {
+		copies[index] = &DirectedNode{Values: node.Values, ID: node.ID}
+	}
+	arborescence.DirectedNodes = copies
+	arborescence.RootDirectedNode = copies[root]
+
+	var total float64
+	for _, index := range chosen {
+		var edge = edges[index]
+		var parent, child = copies[edge.from], copies[edge.to]
+		arborescence, _, _ = CreateDirectedEdge(arborescence, parent, child)
+		for key, value := range graph.DirectedNodes[edge.from].EdgeValues[child.ID] {
+			SetDirectedEdgeValue(parent, child, key, value)
+		}
+		total += edge.weight
+	}
+	return arborescence, total, nil
+}
+
+// chuLiuEdmonds returns the indices of the edges forming a minimum arborescence over n
+// nodes rooted at root, contracting one cycle per recursion. It reports false if no
+// arborescence exists.
+func chuLiuEdmonds(n int, root int, edges []weightedEdge) ([]int, bool) {
+	// Select the cheapest incoming edge of every node but the root
+	var incoming = make([]int, n)
+	for v := range incoming {
+		incoming[v] = -1
+	}
+	for index, edge := range edges {
+		if edge.to == root || edge.from == edge.to {
+			continue
+		}
+		if incoming[edge.to] == -1 || edge.weight < edges[incoming[edge.to]].weight {
+			incoming[edge.to] = index
+		}
+	}
+	for v := range incoming {
+		if v != root && incoming[v] == -1 {
+			return nil, false
+		}
+	}
+
+	// Walk the selected edges backwards from every node to look for a cycle
+	var cycle []int
+	var visitedBy = make([]int, n)
+	for v := range visitedBy {
+		visitedBy[v] = -1
+	}
+	for v := 0; v < n && cycle == nil; v++ {
+		var u = v
+		for u != root && visitedBy[u] == -1 {
+			visitedBy[u] = v
+			u = edges[incoming[u]].from
+		}
+		if u != root && visitedBy[u] == v {
+			for x := u; ; {
+				cycle = append(cycle, x)
+				x = edges[incoming[x]].from
+				if x == u {
+					break
+				}
+			}
+		}
+	}
+
+	if cycle == nil {
+		var chosen []int
+		for v, index := range incoming {
+			if v != root {
+				chosen = append(chosen, index)
+			}
+		}
+		return chosen, true
+	}
+
+	// Contract the cycle into a single node and solve the smaller problem
+	var inCycle = make([]bool, n)
+	for _, v := range cycle {
+		inCycle[v] = true
+	}
+	var contractedID = make([]int, n)
+	var size = 0
+	for v := range contractedID {
+		if !inCycle[v] {
+			contractedID[v] = size
+			size++
+		}
+	}
+	for _, v := range cycle {
+		contractedID[v] = size
+	}
+
+	var contracted []weightedEdge
+	var origin []int
+	for index, edge := range edges {
+		var from, to = contractedID[edge.from], contractedID[edge.to]
+		if from == to {
+			continue
+		}
+		var weight = edge.weight
+		if inCycle[edge.to] {
+			weight -= edges[incoming[edge.to]].weight
+		}
+		contracted = append(contracted, weightedEdge{from: from, to: to, weight: weight})
+		origin = append(origin, index)
+	}
+
+	var contractedChosen, found = chuLiuEdmonds(size+1, contractedID[root], contracted)
+	if !found {
+		return nil, false
+	}
+
+	// Expand the cycle, keeping every cycle edge but the one replaced by the entering edge
+	var chosen []int
+	var entering = -1
+	for _, index := range contractedChosen {
+		var original = origin[index]
+		chosen = append(chosen, original)
+		if inCycle[edges[original].to] {
+			entering = edges[original].to
+		}
+	}
+	for _, v := range cycle {
+		if v != entering {
+			chosen = append(chosen, incoming[v])
+		}
+	}
+	return chosen, true
+}
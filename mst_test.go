@@ -0,0 +1,94 @@
+package gograph
+
+import (
+	"testing"
+)
+
+func countUndirectedEdges(graph Graph) int {
+	var count int
+	for _, node := range graph.Nodes {
+		count += len(node.Edges)
+	}
+	return count / 2
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+	var algorithms = map[string]func(Graph) (Graph, float64){
+		"KruskalMinimumSpanningForest": KruskalMinimumSpanningForest,
+		"PrimMinimumSpanningForest":    PrimMinimumSpanningForest,
+	}
+
+	for name, algorithm := range algorithms {
+		describe(name, t)
+
+		context("the graph is connected", t)
+		//    0 --2-- 1 --3-- 2
+		//    |     / |     /
+		//    6   8   5   7
+		//    | /     | /
+		//    3 --9-- 4
+		var graph = createWeightedGraph(5, [][3]float64{
+			{0, 1, 2}, {0, 3, 6}, {1, 2, 3}, {1, 3, 8}, {1, 4, 5}, {2, 4, 7}, {3, 4, 9},
+		})
+		var forest, total = algorithm(graph)
+
+		it("returns the total weight of the minimum spanning tree", t)
+		expectEqualFloats(total, 16, t)
+
+		it("spans every node with one fewer edges than nodes", t)
+		expectEqualInts(len(forest.Nodes), 5, t)
+		expectEqualInts(countUndirectedEdges(forest), 4, t)
+
+		it("preserves node IDs and edge weights", t)
+		expectEqualStrings(forest.Nodes[3].ID, graph.Nodes[3].ID, t)
+		expectEqualFloats(EdgeWeight(forest.Nodes[0], forest.Nodes[3]), 6, t)
+
+		context("the graph is disconnected", t)
+		graph = createWeightedGraph(8, [][3]float64{
+			{0, 1, 2}, {0, 3, 6}, {1, 2, 3}, {1, 3, 8}, {1, 4, 5}, {2, 4, 7}, {3, 4, 9}, {5, 6, 1},
+		})
+		forest, total = algorithm(graph)
+
+		it("returns a spanning tree for each component", t)
+		expectEqualFloats(total, 17, t)
+		expectEqualInts(len(forest.Nodes), 8, t)
+		expectEqualInts(countUndirectedEdges(forest), 5, t)
+		expectEqualInts(len(forest.Nodes[7].Edges), 0, t)
+	}
+}
+
+func TestMinimumSpanningArborescence(t *testing.T) {
+	describe("MinimumSpanningArborescence", t)
+
+	context("the cheapest incoming edges form a cycle", t)
+	//  0 -10-> 1 <-1-> 2 -5-> 3
+	//  0 -10-> 2,  1 -8-> 3
+	var graph = createWeightedDirectedGraph(4, [][3]float64{
+		{0, 1, 10}, {0, 2, 10}, {1, 2, 1}, {2, 1, 1}, {2, 3, 5}, {1, 3, 8},
+	})
+	var arborescence, total, err = MinimumSpanningArborescence(graph)
+
+	it("breaks the cycle and returns the minimum total weight", t)
+	if err != nil {
+		t.Errorf("Failed: expected no error, but found %s", err)
+		return
+	}
+	expectEqualFloats(total, 16, t)
+
+	it("gives every node but the root exactly one parent", t)
+	expectEqualStrings(arborescence.RootDirectedNode.ID, graph.RootDirectedNode.ID, t)
+	expectEqualInts(len(arborescence.RootDirectedNode.Parents), 0, t)
+	for _, node := range arborescence.DirectedNodes[1:] {
+		expectEqualInts(len(node.Parents), 1, t)
+	}
+	expectEqualStrings(arborescence.DirectedNodes[3].Parents[0].ID, graph.DirectedNodes[2].ID, t)
+
+	context("a node is unreachable from the root", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {2, 1, 1}})
+	_, _, err = MinimumSpanningArborescence(graph)
+
+	it("returns an error", t)
+	if err == nil {
+		t.Errorf("Failed: expected an error, but found none")
+	}
+}
@@ -0,0 +1,295 @@
+package gograph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Path is a walk from parent to child through a directed graph, along with its total weight
+type Path struct {
+	Nodes []*DirectedNode
+	Cost  float64
+}
+
+// weightedArc is an edge to a child index with the weight of the edge
+type weightedArc struct {
+	to     int
+	weight float64
+}
+
+// weightedChildLists returns, for every node of a directed graph, its distinct children other
+// than itself along with the weights of the edges to them
+func weightedChildLists(graph DirectedGraph) [][]weightedArc {
+	var arcs = make([][]weightedArc, len(graph.DirectedNodes))
+	for i, list := range childLists(graph) {
+		var seen = map[int]bool{i: true}
+		for _, j := range list {
+			if !seen[j] {
+				seen[j] = true
+				arcs[i] = append(arcs[i], weightedArc{to: j, weight: DirectedEdgeWeight(graph.DirectedNodes[i], graph.DirectedNodes[j])})
+			}
+		}
+	}
+	return arcs
+}
+
+// endpointIndices returns the indices of a source and target node, or an error if either is
+// not a node of the graph
+func endpointIndices(graph DirectedGraph, source *DirectedNode, target *DirectedNode) (int, int, error) {
+	var indices = indexDirectedNodes(graph)
+	var s, sourceOK = indices[source]
+	var t, targetOK = indices[target]
+	if !sourceOK || !targetOK {
+		return 0, 0, errors.New("source and target must be nodes of the graph")
+	}
+	return s, t, nil
+}
+
+// dijkstra finds a cheapest path from source to target that avoids the blocked nodes and
+// edges, returning the node indices along it and its cost, or nil if the target is unreachable
+func dijkstra(arcs [][]weightedArc, source int, target int, blockedNodes []bool, blockedEdges map[[2]int]bool) ([]int, float64) {
+	var distance = make([]float64, len(arcs))
+	var previous = make([]int, len(arcs))
+	var settled = make([]bool, len(arcs))
+	for i := range distance {
+		distance[i], previous[i] = math.Inf(1), -1
+	}
+	distance[source] = 0
+	var frontier = &edgeHeap{{from: -1, to: source, weight: 0}}
+	for frontier.Len() > 0 {
+		var entry = heap.Pop(frontier).(weightedEdge)
+		var u = entry.to
+		if settled[u] {
+			continue
+		}
+		settled[u] = true
+		if u == target {
+			break
+		}
+		for _, arc := range arcs[u] {
+			if blockedNodes[arc.to] || blockedEdges[[2]int{u, arc.to}] {
+				continue
+			}
+			if distance[u]+arc.weight < distance[arc.to] {
+				distance[arc.to], previous[arc.to] = distance[u]+arc.weight, u
+				heap.Push(frontier, weightedEdge{from: u, to: arc.to, weight: distance[arc.to]})
+			}
+		}
+	}
+	if !settled[target] {
+		return nil, 0
+	}
+	var path []int
+	for v := target; v != -1; v = previous[v] {
+		path = append([]int{v}, path...)
+	}
+	return path, distance[target]
+}
+
+// samePrefix reports whether a path begins with the given nodes
+func samePrefix(path []int, prefix []int) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for index, v := range prefix {
+		if path[index] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// pathKey identifies a path by its node indices
+func pathKey(path []int) string {
+	return fmt.Sprint(path)
+}
+
+// KShortestPathIterator yields the loopless paths between two nodes in order of increasing
+// cost, as found by Yen's algorithm
+type KShortestPathIterator struct {
+	graph          DirectedGraph
+	arcs           [][]weightedArc
+	source         int
+	target         int
+	found          [][]int
+	candidates     [][]int // paths found by deviating from those found so far
+	candidateCosts []float64
+	seen           map[string]bool
+}
+
+// KShortestPaths returns an iterator over the loopless paths from a source to a target node of
+// a directed graph, cheapest first, weighted by DirectedEdgeWeight. Calling Next k times yields
+// the k shortest paths using Yen's algorithm; each call does the work of finding one more
+// path. Parallel edges count as one edge and self-loops are never followed. An error is
+// returned if either node is not in the graph or if any edge has a negative weight.
+func KShortestPaths(graph DirectedGraph, source *DirectedNode, target *DirectedNode) (*KShortestPathIterator, error) {
+	var s, t, err = endpointIndices(graph, source, target)
+	if err != nil {
+		return nil, err
+	}
+	var arcs = weightedChildLists(graph)
+	for _, list := range arcs {
+		for _, arc := range list {
+			if arc.weight < 0 {
+				return nil, errors.New("graph has an edge of negative weight")
+			}
+		}
+	}
+	return &KShortestPathIterator{graph: graph, arcs: arcs, source: s, target: t, seen: map[string]bool{}}, nil
+}
+
+// Next returns the next cheapest path, or false once there are no more paths
+func (iterator *KShortestPathIterator) Next() (Path, bool) {
+	var n = len(iterator.arcs)
+	if len(iterator.found) == 0 {
+		var path, cost = dijkstra(iterator.arcs, iterator.source, iterator.target, make([]bool, n), nil)
+		if path != nil {
+			iterator.add(path, cost)
+		}
+	} else {
+		// Deviate from the last path found at each of its nodes in turn
+		var last = iterator.found[len(iterator.found)-1]
+		var rootCost float64
+		for i := 0; i < len(last)-1; i++ {
+			var root = last[:i+1]
+			var blockedEdges = map[[2]int]bool{}
+			for _, path := range iterator.found {
+				if samePrefix(path, root) && len(path) > i+1 {
+					blockedEdges[[2]int{path[i], path[i+1]}] = true
+				}
+			}
+			var blockedNodes = make([]bool, n)
+			for _, v := range root[:i] {
+				blockedNodes[v] = true
+			}
+			var spur, spurCost = dijkstra(iterator.arcs, last[i], iterator.target, blockedNodes, blockedEdges)
+			if spur != nil {
+				iterator.add(append(append([]int{}, root[:i]...), spur...), rootCost+spurCost)
+			}
+			rootCost += iterator.weight(last[i], last[i+1])
+		}
+	}
+	if len(iterator.candidates) == 0 {
+		return Path{}, false
+	}
+
+	// Take the cheapest candidate, preferring fewer edges between paths of equal cost
+	var best = 0
+	for index, path := range iterator.candidates {
+		var cost, bestCost = iterator.candidateCosts[index], iterator.candidateCosts[best]
+		if cost < bestCost || (cost == bestCost && len(path) < len(iterator.candidates[best])) {
+			best = index
+		}
+	}
+	var path, cost = iterator.candidates[best], iterator.candidateCosts[best]
+	iterator.candidates = append(iterator.candidates[:best], iterator.candidates[best+1:]...)
+	iterator.candidateCosts = append(iterator.candidateCosts[:best], iterator.candidateCosts[best+1:]...)
+	iterator.found = append(iterator.found, path)
+	return Path{Nodes: indexedDirectedNodes(iterator.graph, path), Cost: cost}, true
+}
+
+// add records a candidate path unless it has been seen before
+func (iterator *KShortestPathIterator) add(path []int, cost float64) {
+	var key = pathKey(path)
+	if iterator.seen[key] {
+		return
+	}
+	iterator.seen[key] = true
+	iterator.candidates = append(iterator.candidates, path)
+	iterator.candidateCosts = append(iterator.candidateCosts, cost)
+}
+
+// weight returns the weight of the edge from node u to node v
+func (iterator *KShortestPathIterator) weight(u int, v int) float64 {
+	for _, arc := range iterator.arcs[u] {
+		if arc.to == v {
+			return arc.weight
+		}
+	}
+	return math.Inf(1)
+}
+
+// SimplePathOptions bounds the enumeration of simple paths
+type SimplePathOptions struct {
+	MaxLength int // most edges a path may have, or 0 for no limit
+	Limit     int // most paths to yield, or 0 for all of them
+}
+
+// SimplePathIterator yields the simple paths between two nodes of a directed graph
+type SimplePathIterator struct {
+	graph    DirectedGraph
+	children [][]int
+	target   int
+	options  SimplePathOptions
+	count    int
+	path     []int
+	next     []int // position of the next child to try from every node of the path
+	onPath   []bool
+	done     bool
+}
+
+// AllSimplePaths returns an iterator over the paths from a source to a target node of a
+// directed graph that visit no node twice, in depth-first order. Paths are found one at a time
+// as Next is called, so enumeration can stop early; the options bound the number of edges of
+// each path and the number of paths. Parallel edges yield a path only once. A node is a path
+// of no edges to itself. An error is returned if either node is not in the graph.
+func AllSimplePaths(graph DirectedGraph, source *DirectedNode, target *DirectedNode, options SimplePathOptions) (*SimplePathIterator, error) {
+	var s, t, err = endpointIndices(graph, source, target)
+	if err != nil {
+		return nil, err
+	}
+	var children = make([][]int, len(graph.DirectedNodes))
+	for i, list := range weightedChildLists(graph) {
+		for _, arc := range list {
+			children[i] = append(children[i], arc.to)
+		}
+	}
+	var iterator = &SimplePathIterator{
+		graph:    graph,
+		children: children,
+		target:   t,
+		options:  options,
+		path:     []int{s},
+		next:     []int{0},
+		onPath:   make([]bool, len(children)),
+	}
+	iterator.onPath[s] = true
+	return iterator, nil
+}
+
+// Next returns the next simple path, or false once there are no more paths
+func (iterator *SimplePathIterator) Next() ([]*DirectedNode, bool) {
+	if iterator.done || (iterator.options.Limit > 0 && iterator.count >= iterator.options.Limit) {
+		return nil, false
+	}
+	if iterator.path[0] == iterator.target {
+		iterator.done = true
+		iterator.count++
+		return indexedDirectedNodes(iterator.graph, iterator.path), true
+	}
+	for len(iterator.path) > 0 {
+		var top = len(iterator.path) - 1
+		var v = iterator.path[top]
+		var full = iterator.options.MaxLength > 0 && top >= iterator.options.MaxLength
+		if full || iterator.next[top] == len(iterator.children[v]) {
+			iterator.onPath[v] = false
+			iterator.path, iterator.next = iterator.path[:top], iterator.next[:top]
+			continue
+		}
+		var w = iterator.children[v][iterator.next[top]]
+		iterator.next[top]++
+		if iterator.onPath[w] {
+			continue
+		}
+		if w == iterator.target {
+			iterator.count++
+			return indexedDirectedNodes(iterator.graph, append(append([]int{}, iterator.path...), w)), true
+		}
+		iterator.onPath[w] = true
+		iterator.path, iterator.next = append(iterator.path, w), append(iterator.next, 0)
+	}
+	iterator.done = true
+	return nil, false
+}
@@ -0,0 +1,107 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// createDiamondChainGraph builds a diamond followed by a triangle, weighted so that every path
+// from node 0 to node 6 has a different cost:
+//
+//	  1
+//	 / \
+//	0   3 - 5 - 4 - 6
+//	 \ /     \     /
+//	  2       -----
+func createDiamondChainGraph() DirectedGraph {
+	return createWeightedDirectedGraph(7, [][3]float64{
+		{0, 1, 1}, {0, 2, 2}, {1, 3, 1}, {2, 3, 2}, {3, 5, 1}, {5, 4, 1}, {4, 6, 1}, {5, 6, 5},
+	})
+}
+
+func TestKShortestPaths(t *testing.T) {
+	describe("KShortestPaths", t)
+	var graph = createDiamondChainGraph()
+	var source, target = graph.DirectedNodes[0], graph.DirectedNodes[6]
+	var iterator, err = KShortestPaths(graph, source, target)
+
+	it("yields every loopless path, cheapest first", t)
+	expectEqualBools(err == nil, true, t)
+	var costs []float64
+	for path, ok := iterator.Next(); ok; path, ok = iterator.Next() {
+		expectEqualStrings(path.Nodes[0].ID, source.ID, t)
+		expectEqualStrings(path.Nodes[len(path.Nodes)-1].ID, target.ID, t)
+		costs = append(costs, path.Cost)
+	}
+	expectEqualInts(len(costs), 4, t)
+	expectEqualFloats(costs[0], 5, t)
+	expectEqualFloats(costs[1], 7, t)
+	expectEqualFloats(costs[2], 8, t)
+	expectEqualFloats(costs[3], 10, t)
+
+	context("the graph has a cycle", t)
+	graph = createWeightedDirectedGraph(3, [][3]float64{{0, 1, 1}, {1, 0, 1}, {1, 2, 1}, {0, 2, 5}})
+	iterator, _ = KShortestPaths(graph, graph.DirectedNodes[0], graph.DirectedNodes[2])
+
+	it("does not revisit nodes", t)
+	var count = 0
+	for path, ok := iterator.Next(); ok; path, ok = iterator.Next() {
+		expectEqualBools(len(path.Nodes) <= 3, true, t)
+		count++
+	}
+	expectEqualInts(count, 2, t)
+
+	context("an edge has a negative weight", t)
+	graph = createWeightedDirectedGraph(2, [][3]float64{{0, 1, -1}})
+	_, err = KShortestPaths(graph, graph.DirectedNodes[0], graph.DirectedNodes[1])
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestAllSimplePaths(t *testing.T) {
+	describe("AllSimplePaths", t)
+	var graph = createDiamondChainGraph()
+	var source, target = graph.DirectedNodes[0], graph.DirectedNodes[6]
+	var iterator, err = AllSimplePaths(graph, source, target, SimplePathOptions{})
+
+	it("yields every simple path", t)
+	expectEqualBools(err == nil, true, t)
+	var count = 0
+	for path, ok := iterator.Next(); ok; path, ok = iterator.Next() {
+		expectEqualStrings(path[0].ID, source.ID, t)
+		expectEqualStrings(path[len(path)-1].ID, target.ID, t)
+		count++
+	}
+	expectEqualInts(count, 4, t)
+
+	context("paths are limited in length", t)
+	iterator, _ = AllSimplePaths(graph, source, target, SimplePathOptions{MaxLength: 4})
+
+	it("yields only the shorter paths", t)
+	count = 0
+	for path, ok := iterator.Next(); ok; path, ok = iterator.Next() {
+		expectEqualInts(len(path), 5, t)
+		count++
+	}
+	expectEqualInts(count, 2, t)
+
+	context("the number of paths is capped", t)
+	iterator, _ = AllSimplePaths(graph, source, target, SimplePathOptions{Limit: 3})
+
+	it("stops after the cap", t)
+	count = 0
+	for _, ok := iterator.Next(); ok; _, ok = iterator.Next() {
+		count++
+	}
+	expectEqualInts(count, 3, t)
+
+	context("the source is the target", t)
+	iterator, _ = AllSimplePaths(graph, source, source, SimplePathOptions{})
+	var path, ok = iterator.Next()
+
+	it("yields the path of no edges", t)
+	expectEqualBools(ok, true, t)
+	expectEqualInts(len(path), 1, t)
+	_, ok = iterator.Next()
+	expectEqualBools(ok, false, t)
+}
@@ -0,0 +1,292 @@
+package gograph
+
+import (
+	"errors"
+)
+
+// CSRMatrix is a sparse adjacency matrix of a directed graph in compressed sparse row format.
+// Rows stand for parents and columns for children, both in the order of IDs. The entries of
+// row i are at positions RowPointers[i] up to RowPointers[i+1] of ColumnIndices and Values,
+// in increasing column order, and each value counts the edges from the row's node to the
+// column's node.
+type CSRMatrix struct {
+	IDs           []string
+	RowPointers   []int
+	ColumnIndices []int
+	Values        []int
+}
+
+// CSCMatrix is a sparse adjacency matrix of a directed graph in compressed sparse column
+// format, laid out like CSRMatrix with the roles of rows and columns swapped. The entries of
+// column j are at positions ColumnPointers[j] up to ColumnPointers[j+1] of RowIndices and
+// Values, in increasing row order.
+type CSCMatrix struct {
+	IDs            []string
+	ColumnPointers []int
+	RowIndices     []int
+	Values         []int
+}
+
+// COOMatrix is a sparse adjacency matrix of a directed graph in coordinate format: the k-th
+// entry counts the edges from the node of row Rows[k] to the node of column Columns[k]. Rows
+// and columns stand for the nodes in the order of IDs.
+type COOMatrix struct {
+	IDs     []string
+	Rows    []int
+	Columns []int
+	Values  []int
+}
+
+// compressed is a matrix in compressed sparse row or column format, whose pointers delimit the
+// entries of every row or column and whose indices give the other coordinate of every entry
+type compressed struct {
+	pointers []int
+	indices  []int
+	values   []int
+}
+
+// transpose switches a compressed matrix between row and column format with a counting sort,
+// leaving the entries of every row or column of the result in increasing order
+func (matrix compressed) transpose(n int) compressed {
+	var result = compressed{
+		pointers: make([]int, n+1),
+		indices:  make([]int, len(matrix.indices)),
+		values:   make([]int, len(matrix.values)),
+	}
+	for _, minor := range matrix.indices {
+		result.pointers[minor+1]++
+	}
+	for i := 0; i < n; i++ {
+		result.pointers[i+1] += result.pointers[i]
+	}
+	var next = append([]int{}, result.pointers[:n]...)
+	for major := 0; major < n; major++ {
+		for k := matrix.pointers[major]; k < matrix.pointers[major+1]; k++ {
+			var minor = matrix.indices[k]
+			result.indices[next[minor]] = major
+			result.values[next[minor]] = matrix.values[k]
+			next[minor]++
+		}
+	}
+	return result
+}
+
+// compressRows builds the compressed sparse rows of the adjacency matrix of a directed graph
+// in O(n+m), merging parallel edges into a single entry. Columns are ordered within each row
+// by transposing twice.
+func compressRows(graph DirectedGraph) compressed {
+	var n = len(graph.DirectedNodes)
+	var rows = compressed{pointers: make([]int, n+1)}
+	var position = make([]int, n) // position of the entry for each column in the current row
+	for j := range position {
+		position[j] = -1
+	}
+	for i, list := range childLists(graph) {
+		var start = len(rows.indices)
+		for _, j := range list {
+			if position[j] >= start {
+				rows.values[position[j]]++
+				continue
+			}
+			position[j] = len(rows.indices)
+			rows.indices = append(rows.indices, j)
+			rows.values = append(rows.values, 1)
+		}
+		rows.pointers[i+1] = len(rows.indices)
+	}
+	return rows.transpose(n).transpose(n)
+}
+
+// CreateCSRMatrix returns the adjacency matrix of a directed graph in compressed sparse row
+// format, built in time proportional to the number of nodes and edges
+func CreateCSRMatrix(graph DirectedGraph) CSRMatrix {
+	var rows = compressRows(graph)
+	return CSRMatrix{IDs: directedNodeIDs(graph), RowPointers: rows.pointers, ColumnIndices: rows.indices, Values: rows.values}
+}
+
+// CreateCSCMatrix returns the adjacency matrix of a directed graph in compressed sparse column
+// format, built in time proportional to the number of nodes and edges
+func CreateCSCMatrix(graph DirectedGraph) CSCMatrix {
+	var columns = compressRows(graph).transpose(len(graph.DirectedNodes))
+	return CSCMatrix{IDs: directedNodeIDs(graph), ColumnPointers: columns.pointers, RowIndices: columns.indices, Values: columns.values}
+}
+
+// CreateCOOMatrix returns the adjacency matrix of a directed graph in coordinate format, with
+// its entries ordered by row and then by column, built in time proportional to the number of
+// nodes and edges
+func CreateCOOMatrix(graph DirectedGraph) COOMatrix {
+	return CreateCSRMatrix(graph).COO()
+}
+
+// COO converts a compressed sparse row matrix to coordinate format
+func (matrix CSRMatrix) COO() COOMatrix {
+	var coo = COOMatrix{
+		IDs:     matrix.IDs,
+		Rows:    make([]int, len(matrix.ColumnIndices)),
+		Columns: append([]int{}, matrix.ColumnIndices...),
+		Values:  append([]int{}, matrix.Values...),
+	}
+	for i := 0; i+1 < len(matrix.RowPointers); i++ {
+		for k := matrix.RowPointers[i]; k < matrix.RowPointers[i+1]; k++ {
+			coo.Rows[k] = i
+		}
+	}
+	return coo
+}
+
+// CSC converts a compressed sparse row matrix to compressed sparse column format
+func (matrix CSRMatrix) CSC() CSCMatrix {
+	var columns = compressed{matrix.RowPointers, matrix.ColumnIndices, matrix.Values}.transpose(len(matrix.IDs))
+	return CSCMatrix{IDs: matrix.IDs, ColumnPointers: columns.pointers, RowIndices: columns.indices, Values: columns.values}
+}
+
+// CSR converts a compressed sparse column matrix to compressed sparse row format
+func (matrix CSCMatrix) CSR() CSRMatrix {
+	var rows = compressed{matrix.ColumnPointers, matrix.RowIndices, matrix.Values}.transpose(len(matrix.IDs))
+	return CSRMatrix{IDs: matrix.IDs, RowPointers: rows.pointers, ColumnIndices: rows.indices, Values: rows.values}
+}
+
+// CSR converts a coordinate matrix to compressed sparse row format, adding up the values of
+// entries at the same position
+func (matrix COOMatrix) CSR() CSRMatrix {
+	var n = len(matrix.IDs)
+	var columns = compressed{pointers: make([]int, n+1), indices: make([]int, len(matrix.Rows)), values: make([]int, len(matrix.Rows))}
+	for _, j := range matrix.Columns {
+		columns.pointers[j+1]++
+	}
+	for j := 0; j < n; j++ {
+		columns.pointers[j+1] += columns.pointers[j]
+	}
+	var next = append([]int{}, columns.pointers[:n]...)
+	for k, j := range matrix.Columns {
+		columns.indices[next[j]] = matrix.Rows[k]
+		columns.values[next[j]] = matrix.Values[k]
+		next[j]++
+	}
+	var rows = columns.transpose(n)
+
+	// Merge entries at the same position, which are now adjacent
+	var merged = compressed{pointers: make([]int, n+1)}
+	for i := 0; i < n; i++ {
+		for k := rows.pointers[i]; k < rows.pointers[i+1]; k++ {
+			var last = len(merged.indices) - 1
+			if k > rows.pointers[i] && merged.indices[last] == rows.indices[k] {
+				merged.values[last] += rows.values[k]
+				continue
+			}
+			merged.indices = append(merged.indices, rows.indices[k])
+			merged.values = append(merged.values, rows.values[k])
+		}
+		merged.pointers[i+1] = len(merged.indices)
+	}
+	return CSRMatrix{IDs: matrix.IDs, RowPointers: merged.pointers, ColumnIndices: merged.indices, Values: merged.values}
+}
+
+// Dense returns the matrix as an n×n matrix of integers, which takes memory quadratic in the
+// number of nodes and so suits only small graphs
+func (matrix CSRMatrix) Dense() Matrix {
+	var n = len(matrix.IDs)
+	var dense = make(Matrix, n)
+	for i := range dense {
+		dense[i] = make([]int, n)
+		for k := matrix.RowPointers[i]; k < matrix.RowPointers[i+1]; k++ {
+			dense[i][matrix.ColumnIndices[k]] += matrix.Values[k]
+		}
+	}
+	return dense
+}
+
+// Dense returns the matrix as an n×n matrix of integers, like CSRMatrix.Dense
+func (matrix CSCMatrix) Dense() Matrix {
+	return matrix.CSR().Dense()
+}
+
+// Dense returns the matrix as an n×n matrix of integers, like CSRMatrix.Dense
+func (matrix COOMatrix) Dense() Matrix {
+	return matrix.CSR().Dense()
+}
+
+// validateCompressed checks that a compressed matrix over n nodes is well formed
+func validateCompressed(n int, matrix compressed) error {
+	if len(matrix.pointers) != n+1 || matrix.pointers[0] != 0 {
+		return errors.New("matrix must have a pointer for every node and one more, starting at 0")
+	}
+	for i := 0; i < n; i++ {
+		if matrix.pointers[i+1] < matrix.pointers[i] {
+			return errors.New("matrix pointers must not decrease")
+		}
+	}
+	if matrix.pointers[n] != len(matrix.indices) || len(matrix.indices) != len(matrix.values) {
+		return errors.New("matrix must have an index and a value for every entry")
+	}
+	return validateEntries(n, matrix.indices, matrix.values)
+}
+
+// validateEntries checks that the indices of a matrix's entries are in range and that its
+// values count edges
+func validateEntries(n int, indices []int, values []int) error {
+	for k, index := range indices {
+		if index < 0 || index >= n {
+			return errors.New("matrix has an entry outside of its rows and columns")
+		}
+		if values[k] < 0 {
+			return errors.New("matrix has a negative number of edges")
+		}
+	}
+	return nil
+}
+
+// createGraphFromRows builds a directed graph with a node for every ID, and as many edges from
+// every row's node to every column's node as the entry between them counts. The first node
+// becomes the root.
+func createGraphFromRows(IDs []string, rows compressed) DirectedGraph {
+	var graph = createDirectedNodes(IDs, MatrixGraphOptions{})
+	for i := range IDs {
+		for k := rows.pointers[i]; k < rows.pointers[i+1]; k++ {
+			for count := 0; count < rows.values[k]; count++ {
+				graph, _, _ = CreateDirectedEdge(graph, graph.DirectedNodes[i], graph.DirectedNodes[rows.indices[k]])
+			}
+		}
+	}
+	return graph
+}
+
+// CreateGraphFromCSR builds a directed graph from a compressed sparse row adjacency matrix,
+// with a node for every ID and as many edges between two nodes as their entry counts. When the
+// columns of every row are in increasing order and no value is zero, as CreateCSRMatrix
+// leaves them, CreateCSRMatrix of the graph gives back the matrix. An error is returned if the
+// matrix is malformed.
+func CreateGraphFromCSR(matrix CSRMatrix) (DirectedGraph, error) {
+	var rows = compressed{matrix.RowPointers, matrix.ColumnIndices, matrix.Values}
+	if err := validateCompressed(len(matrix.IDs), rows); err != nil {
+		return DirectedGraph{}, err
+	}
+	return createGraphFromRows(matrix.IDs, rows), nil
+}
+
+// CreateGraphFromCSC builds a directed graph from a compressed sparse column adjacency matrix,
+// like CreateGraphFromCSR
+func CreateGraphFromCSC(matrix CSCMatrix) (DirectedGraph, error) {
+	var columns = compressed{matrix.ColumnPointers, matrix.RowIndices, matrix.Values}
+	if err := validateCompressed(len(matrix.IDs), columns); err != nil {
+		return DirectedGraph{}, err
+	}
+	return createGraphFromRows(matrix.IDs, columns.transpose(len(matrix.IDs))), nil
+}
+
+// CreateGraphFromCOO builds a directed graph from a coordinate adjacency matrix, like
+// CreateGraphFromCSR. Entries at the same position add up.
+func CreateGraphFromCOO(matrix COOMatrix) (DirectedGraph, error) {
+	if len(matrix.Rows) != len(matrix.Columns) || len(matrix.Rows) != len(matrix.Values) {
+		return DirectedGraph{}, errors.New("matrix must have a row, a column and a value for every entry")
+	}
+	var n = len(matrix.IDs)
+	if err := validateEntries(n, matrix.Rows, matrix.Values); err != nil {
+		return DirectedGraph{}, err
+	}
+	if err := validateEntries(n, matrix.Columns, matrix.Values); err != nil {
+		return DirectedGraph{}, err
+	}
+	var csr = matrix.CSR()
+	return createGraphFromRows(matrix.IDs, compressed{csr.RowPointers, csr.ColumnIndices, csr.Values}), nil
+}
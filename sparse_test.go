@@ -0,0 +1,127 @@
+package gograph
+
+import (
+	"testing"
+)
+
+// createSparseTestGraph builds a small directed graph with a parallel edge and a self-loop,
+// whose children are listed out of order
+func createSparseTestGraph() DirectedGraph {
+	return createWeightedDirectedGraph(4, [][3]float64{{0, 2, 1}, {0, 1, 1}, {1, 3, 1}, {1, 3, 1}, {2, 2, 1}, {3, 0, 1}})
+}
+
+var sparseTestDense = [][]int{
+	{0, 1, 1, 0},
+	{0, 0, 0, 2},
+	{0, 0, 1, 0},
+	{1, 0, 0, 0},
+}
+
+func expectEqualIntSlices(values []int, expectation []int, t *testing.T) {
+	expectEqualInts(len(values), len(expectation), t)
+	for index := range expectation {
+		if index < len(values) && values[index] != expectation[index] {
+			t.Errorf("Failed: expected %+v, but found %+v", expectation, values)
+			return
+		}
+	}
+}
+
+func TestCreateCSRMatrix(t *testing.T) {
+	describe("CreateCSRMatrix", t)
+	var graph = createSparseTestGraph()
+	var matrix = CreateCSRMatrix(graph)
+
+	it("lists the children of every row in column order, counting parallel edges", t)
+	expectEqualIntSlices(matrix.RowPointers, []int{0, 2, 3, 4, 5}, t)
+	expectEqualIntSlices(matrix.ColumnIndices, []int{1, 2, 3, 2, 0}, t)
+	expectEqualIntSlices(matrix.Values, []int{1, 1, 2, 1, 1}, t)
+
+	it("converts to the dense adjacency matrix", t)
+	expectEqualMatrices(matrix.Dense(), sparseTestDense, t)
+
+	it("converts to the other formats", t)
+	expectEqualMatrices(matrix.CSC().Dense(), sparseTestDense, t)
+	expectEqualMatrices(matrix.COO().Dense(), sparseTestDense, t)
+}
+
+func TestCreateCSCMatrix(t *testing.T) {
+	describe("CreateCSCMatrix", t)
+	var matrix = CreateCSCMatrix(createSparseTestGraph())
+
+	it("lists the parents of every column in row order", t)
+	expectEqualIntSlices(matrix.ColumnPointers, []int{0, 1, 2, 4, 5}, t)
+	expectEqualIntSlices(matrix.RowIndices, []int{3, 0, 0, 2, 1}, t)
+	expectEqualMatrices(matrix.Dense(), sparseTestDense, t)
+}
+
+func TestCreateCOOMatrix(t *testing.T) {
+	describe("CreateCOOMatrix", t)
+	var matrix = CreateCOOMatrix(createSparseTestGraph())
+
+	it("lists every entry by row and then by column", t)
+	expectEqualIntSlices(matrix.Rows, []int{0, 0, 1, 2, 3}, t)
+	expectEqualIntSlices(matrix.Columns, []int{1, 2, 3, 2, 0}, t)
+
+	context("entries are repeated", t)
+	matrix.Rows = append(matrix.Rows, 1)
+	matrix.Columns = append(matrix.Columns, 3)
+	matrix.Values = append(matrix.Values, 1)
+
+	it("adds them up", t)
+	expectEqualInts(matrix.Dense()[1][3], 3, t)
+	expectEqualInts(len(matrix.CSR().Values), 5, t)
+}
+
+func TestCreateGraphFromCSR(t *testing.T) {
+	describe("CreateGraphFromCSR", t)
+	var graph = createSparseTestGraph()
+	var matrix = CreateCSRMatrix(graph)
+	var rebuilt, err = CreateGraphFromCSR(matrix)
+
+	it("rebuilds the nodes and edges of the graph", t)
+	expectEqualBools(err == nil, true, t)
+	for index, node := range graph.DirectedNodes {
+		expectEqualStrings(rebuilt.DirectedNodes[index].ID, node.ID, t)
+	}
+	expectEqualStrings(rebuilt.RootDirectedNode.ID, graph.DirectedNodes[0].ID, t)
+	expectEqualInts(len(rebuilt.DirectedNodes[1].Children), 2, t)
+	expectEqualInts(len(rebuilt.DirectedNodes[3].Parents), 2, t)
+
+	it("round-trips the matrix", t)
+	var roundTrip = CreateCSRMatrix(rebuilt)
+	expectEqualIntSlices(roundTrip.RowPointers, matrix.RowPointers, t)
+	expectEqualIntSlices(roundTrip.ColumnIndices, matrix.ColumnIndices, t)
+	expectEqualIntSlices(roundTrip.Values, matrix.Values, t)
+
+	context("the matrix is malformed", t)
+	matrix.ColumnIndices[0] = 7
+	_, err = CreateGraphFromCSR(matrix)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestCreateGraphFromCSC(t *testing.T) {
+	describe("CreateGraphFromCSC", t)
+	var rebuilt, err = CreateGraphFromCSC(CreateCSCMatrix(createSparseTestGraph()))
+
+	it("rebuilds the graph", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(CreateCSRMatrix(rebuilt).Dense(), sparseTestDense, t)
+}
+
+func TestCreateGraphFromCOO(t *testing.T) {
+	describe("CreateGraphFromCOO", t)
+	var rebuilt, err = CreateGraphFromCOO(CreateCOOMatrix(createSparseTestGraph()))
+
+	it("rebuilds the graph", t)
+	expectEqualBools(err == nil, true, t)
+	expectEqualMatrices(CreateCSRMatrix(rebuilt).Dense(), sparseTestDense, t)
+
+	context("an entry has a negative value", t)
+	_, err = CreateGraphFromCOO(COOMatrix{IDs: []string{"a"}, Rows: []int{0}, Columns: []int{0}, Values: []int{-1}})
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
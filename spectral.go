@@ -0,0 +1,600 @@
+package gograph
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// SpectralMatrix selects the matrix of an undirected graph whose eigenvalues are computed
+type SpectralMatrix int
+
+const (
+	// SpectralAdjacency is the matrix given by CreateUndirectedAdjacencyMatrix
+	SpectralAdjacency SpectralMatrix = iota
+	// SpectralLaplacian is the matrix given by CreateUndirectedLaplacianMatrix
+	SpectralLaplacian
+	// SpectralNormalizedLaplacian is the matrix given by CreateUndirectedNormalizedLaplacianMatrix
+	SpectralNormalizedLaplacian
+)
+
+// SpectralOptions controls the iterative eigenvalue computations
+type SpectralOptions struct {
+	Tolerance     float64 // most summed change of the vector in power iteration, or residual relative to the largest eigenvalue otherwise
+	MaxIterations int     // most power iterations, Lanczos products or inverse iterations before giving up
+	Steps         int     // Lanczos steps between restarts, or 0 for 64, capped at one per node, which finds the whole spectrum
+	Seed          int64   // seed of the random starting vectors
+}
+
+// DefaultSpectralOptions returns the controls used when none are specified
+func DefaultSpectralOptions() SpectralOptions {
+	return SpectralOptions{Tolerance: 1e-10, MaxIterations: 10000}
+}
+
+// withDefaults replaces a tolerance or maximum of iterations that is not positive, as in the
+// zero value, with the default
+func (options SpectralOptions) withDefaults() SpectralOptions {
+	var defaults = DefaultSpectralOptions()
+	if options.Tolerance <= 0 {
+		options.Tolerance = defaults.Tolerance
+	}
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = defaults.MaxIterations
+	}
+	return options
+}
+
+// Eigenpair is an eigenvalue of a graph matrix with a unit eigenvector, whose entries are in
+// the order of the graph's Nodes. The first nonzero entry of the vector is positive.
+type Eigenpair struct {
+	Value  float64
+	Vector []float64
+}
+
+// linearOperator multiplies a vector by a symmetric matrix, writing the product into another
+type linearOperator func(x []float64, y []float64)
+
+// spectralOperator returns the product with the selected matrix of an undirected graph, which
+// is computed from the graph's edges without building the matrix, along with a bound on the
+// absolute value of the matrix's eigenvalues
+func spectralOperator(graph Graph, matrix SpectralMatrix) (linearOperator, float64, error) {
+	var indices = indexNodes(graph)
+	var n = len(graph.Nodes)
+	var neighbors = make([][]weightedArc, n) // count of edges to every neighbor, self-loops twice
+	var degree = make([]float64, n)
+	for i, node := range graph.Nodes {
+		var position = map[int]int{}
+		for _, neighbor := range node.Edges {
+			var j, ok = indices[neighbor]
+			if !ok {
+				continue
+			}
+			var count = 1.0
+			if i == j {
+				count = 2
+			}
+			if p, seen := position[j]; seen {
+				neighbors[i][p].weight += count
+			} else {
+				position[j] = len(neighbors[i])
+				neighbors[i] = append(neighbors[i], weightedArc{to: j, weight: count})
+			}
+			degree[i] += count
+		}
+	}
+	var maxDegree float64
+	for _, d := range degree {
+		maxDegree = math.Max(maxDegree, d)
+	}
+
+	var adjacency = func(x []float64, y []float64) {
+		for i, list := range neighbors {
+			y[i] = 0
+			for _, arc := range list {
+				y[i] += arc.weight * x[arc.to]
+			}
+		}
+	}
+	switch matrix {
+	case SpectralAdjacency:
+		return adjacency, maxDegree, nil
+	case SpectralLaplacian:
+		return func(x []float64, y []float64) {
+			adjacency(x, y)
+			for i := range y {
+				y[i] = degree[i]*x[i] - y[i]
+			}
+		}, 2 * maxDegree, nil
+	case SpectralNormalizedLaplacian:
+		var scale = make([]float64, n)
+		for i, d := range degree {
+			if d > 0 {
+				scale[i] = 1 / math.Sqrt(d)
+			}
+		}
+		var scaled = make([]float64, n)
+		return func(x []float64, y []float64) {
+			for i := range x {
+				scaled[i] = scale[i] * x[i]
+			}
+			adjacency(scaled, y)
+			for i := range y {
+				if degree[i] > 0 {
+					y[i] = x[i] - scale[i]*y[i]
+				} else {
+					y[i] = 0
+				}
+			}
+		}, 2, nil
+	}
+	return nil, 0, errors.New("unknown spectral matrix")
+}
+
+// dot returns the inner product of two vectors
+func dot(x []float64, y []float64) float64 {
+	var sum float64
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+// orientVector flips a vector so that its first entry of any significance is positive
+func orientVector(vector []float64) {
+	for _, x := range vector {
+		if math.Abs(x) > 1e-12 {
+			if x < 0 {
+				for i := range vector {
+					vector[i] = -vector[i]
+				}
+			}
+			return
+		}
+	}
+}
+
+// PowerIteration computes the largest eigenvalue of the selected matrix of an undirected graph
+// and its eigenvector by repeated multiplication. The matrix is shifted by a bound on its
+// eigenvalues so that the largest eigenvalue dominates even when the most negative one is as
+// large in absolute value, as it is for the adjacency matrix of a bipartite graph. An error is
+// returned if the graph has no nodes or the iteration does not converge.
+func PowerIteration(graph Graph, matrix SpectralMatrix, options SpectralOptions) (Eigenpair, error) {
+	var operator, bound, err = spectralOperator(graph, matrix)
+	if err != nil {
+		return Eigenpair{}, err
+	}
+	var n = len(graph.Nodes)
+	if n == 0 {
+		return Eigenpair{}, errors.New("graph has no nodes")
+	}
+	options = options.withDefaults()
+	var shift = 0.0
+	if matrix == SpectralAdjacency {
+		shift = bound
+	}
+
+	var random = rand.New(rand.NewSource(options.Seed))
+	var vector = make([]float64, n)
+	for i := range vector {
+		vector[i] = 1 + random.Float64()
+	}
+	normalize(vector, 2)
+	var product = make([]float64, n)
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		operator(vector, product)
+		var next = make([]float64, n)
+		for i := range next {
+			next[i] = product[i] + shift*vector[i]
+		}
+		if math.Sqrt(dot(next, next)) < 1e-12 {
+			// The random vector has a part along every eigenvector, and the shifted matrix has no
+			// negative eigenvalues, so all of them are zero
+			orientVector(vector)
+			return Eigenpair{Value: 0, Vector: vector}, nil
+		}
+		normalize(next, 2)
+		var converged = change(vector, next) < options.Tolerance
+		vector = next
+		if converged {
+			orientVector(vector)
+			operator(vector, product)
+			return Eigenpair{Value: dot(vector, product) / dot(vector, vector), Vector: vector}, nil
+		}
+	}
+	return Eigenpair{}, errors.New("power iteration did not converge within the maximum iterations")
+}
+
+// lanczos builds an orthonormal basis of Krylov vectors for a symmetric operator from a unit
+// starting vector, along with the tridiagonal matrix, given by its diagonal and off-diagonal,
+// of the operator in that basis, and the length of the part of the last product that leaves
+// the basis. Every new vector is reorthogonalized against all the previous ones, and when the
+// Krylov space is exhausted, as happens for disconnected graphs, the basis is extended with a
+// fresh random vector, leaving a zero on the off-diagonal.
+func lanczos(operator linearOperator, start []float64, steps int, random *rand.Rand) ([]float64, []float64, [][]float64, float64) {
+	var n = len(start)
+	var randomVector = func() []float64 {
+		var vector = make([]float64, n)
+		for i := range vector {
+			vector[i] = random.Float64() - 0.5
+		}
+		return vector
+	}
+	var orthogonalize = func(vector []float64, basis [][]float64) {
+		for pass := 0; pass < 2; pass++ {
+			for _, q := range basis {
+				var projection = dot(q, vector)
+				for i := range vector {
+					vector[i] -= projection * q[i]
+				}
+			}
+		}
+	}
+
+	var basis = [][]float64{start}
+	var alpha, beta []float64
+	for j := 0; j < steps; j++ {
+		var w = make([]float64, n)
+		operator(basis[j], w)
+		alpha = append(alpha, dot(basis[j], w))
+		orthogonalize(w, basis)
+		var norm = math.Sqrt(dot(w, w))
+		if j == steps-1 {
+			return alpha, beta, basis, norm
+		}
+		if norm < 1e-10 {
+			// Restart in a direction not yet spanned
+			norm = 0
+			for attempt := 0; attempt < 3 && norm < 1e-8; attempt++ {
+				w = randomVector()
+				orthogonalize(w, basis)
+				norm = math.Sqrt(dot(w, w))
+			}
+			if norm < 1e-8 {
+				break
+			}
+			beta = append(beta, 0)
+		} else {
+			beta = append(beta, norm)
+		}
+		for i := range w {
+			w[i] /= norm
+		}
+		basis = append(basis, w)
+	}
+	return alpha, beta, basis, 0
+}
+
+// jacobiEigen computes every eigenvalue and eigenvector of a small dense symmetric matrix with
+// the cyclic Jacobi method, returning the eigenvectors as the columns of a matrix
+func jacobiEigen(matrix [][]float64) ([]float64, [][]float64) {
+	var m = len(matrix)
+	var a = make([][]float64, m)
+	var v = make([][]float64, m)
+	for i := range a {
+		a[i] = append([]float64{}, matrix[i]...)
+		v[i] = make([]float64, m)
+		v[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off, total float64
+		for i := range a {
+			for j := range a {
+				total += a[i][j] * a[i][j]
+				if i != j {
+					off += a[i][j] * a[i][j]
+				}
+			}
+		}
+		if off <= 1e-30*total || off == 0 {
+			break
+		}
+		for p := 0; p < m; p++ {
+			for q := p + 1; q < m; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				var theta = (a[q][q] - a[p][p]) / (2 * a[p][q])
+				var t = 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				var c = 1 / math.Sqrt(t*t+1)
+				var s = t * c
+				for k := 0; k < m; k++ {
+					var akp, akq = a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < m; k++ {
+					var apk, aqk = a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < m; k++ {
+					var vkp, vkq = v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	var values = make([]float64, m)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return values, v
+}
+
+// defaultLanczosSteps is the number of Lanczos steps taken between restarts when options.Steps
+// is 0
+const defaultLanczosSteps = 64
+
+// ritzVector combines the Lanczos basis by the kth eigenvector of the tridiagonal matrix
+func ritzVector(basis [][]float64, vectors [][]float64, k int) []float64 {
+	var vector = make([]float64, len(basis[0]))
+	for j := range basis {
+		for i := range vector {
+			vector[i] += vectors[j][k] * basis[j][i]
+		}
+	}
+	normalize(vector, 2)
+	return vector
+}
+
+// LanczosEigenpairs approximates eigenpairs of the selected matrix of an undirected graph with
+// the Lanczos method, which needs only products of the matrix with vectors and so suits large
+// sparse graphs. It returns one eigenpair per step, in increasing order of eigenvalue. The
+// eigenvalues at either end of the spectrum are found first: the steps are repeated from a
+// combination of the smallest and largest eigenvectors found so far until both have a residual
+// within options.Tolerance times a bound on the eigenvalues, and the ones in between are rougher
+// approximations. With one step per node, as when options.Steps is 0 and the graph has at most
+// 64 nodes, the whole spectrum is found. An error is returned if the graph has no nodes or the
+// ends of the spectrum do not converge within options.MaxIterations products.
+func LanczosEigenpairs(graph Graph, matrix SpectralMatrix, options SpectralOptions) ([]Eigenpair, error) {
+	var operator, bound, err = spectralOperator(graph, matrix)
+	if err != nil {
+		return nil, err
+	}
+	var n = len(graph.Nodes)
+	if n == 0 {
+		return nil, errors.New("graph has no nodes")
+	}
+	options = options.withDefaults()
+	var steps = options.Steps
+	if steps <= 0 {
+		steps = defaultLanczosSteps
+	}
+	if steps > n {
+		steps = n
+	}
+
+	var random = rand.New(rand.NewSource(options.Seed))
+	var start = make([]float64, n)
+	for i := range start {
+		start[i] = random.Float64() - 0.5
+	}
+	normalize(start, 2)
+	for products := 0; products < options.MaxIterations; {
+		var alpha, beta, basis, residual = lanczos(operator, start, steps, random)
+		var m = len(alpha)
+		products += m
+		var tridiagonal = make([][]float64, m)
+		for i := range tridiagonal {
+			tridiagonal[i] = make([]float64, m)
+			tridiagonal[i][i] = alpha[i]
+		}
+		for i := 0; i+1 < m; i++ {
+			tridiagonal[i][i+1], tridiagonal[i+1][i] = beta[i], beta[i]
+		}
+		var values, vectors = jacobiEigen(tridiagonal)
+
+		// The residual of a Ritz pair is the last product's part outside the basis, scaled by the
+		// last entry of the pair's eigenvector of the tridiagonal matrix
+		var smallest, largest = 0, 0
+		for k := range values {
+			if values[k] < values[smallest] {
+				smallest = k
+			}
+			if values[k] > values[largest] {
+				largest = k
+			}
+		}
+		var limit = options.Tolerance * math.Max(bound, 1)
+		if residual*math.Abs(vectors[m-1][smallest]) > limit || residual*math.Abs(vectors[m-1][largest]) > limit {
+			var low, high = ritzVector(basis, vectors, smallest), ritzVector(basis, vectors, largest)
+			for i := range start {
+				start[i] = low[i] + high[i]
+			}
+			normalize(start, 2)
+			continue
+		}
+
+		var pairs = make([]Eigenpair, m)
+		for k := range pairs {
+			var vector = ritzVector(basis, vectors, k)
+			orientVector(vector)
+			pairs[k] = Eigenpair{Value: values[k], Vector: vector}
+		}
+		sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].Value < pairs[b].Value })
+		return pairs, nil
+	}
+	return nil, errors.New("Lanczos iteration did not converge within the maximum iterations")
+}
+
+// removeMean makes a vector orthogonal to the constant vector, which spans the null space of the
+// Laplacian matrix of a connected graph
+func removeMean(vector []float64) {
+	var mean float64
+	for _, x := range vector {
+		mean += x
+	}
+	mean /= float64(len(vector))
+	for i := range vector {
+		vector[i] -= mean
+	}
+}
+
+// conjugateGradient improves a solution of operator(y) = b in place, for the Laplacian matrix of
+// a connected graph and a vector b orthogonal to the constant vector, until the residual is at
+// most precision or limit steps are taken. The iterates are kept orthogonal to the constant
+// vector, where the matrix is positive definite.
+func conjugateGradient(operator linearOperator, b []float64, y []float64, precision float64, limit int) {
+	var n = len(b)
+	var product = make([]float64, n)
+	var residual = make([]float64, n)
+	operator(y, product)
+	for i := range residual {
+		residual[i] = b[i] - product[i]
+	}
+	removeMean(residual)
+	var direction = append([]float64{}, residual...)
+	var squared = dot(residual, residual)
+	for step := 0; step < limit && math.Sqrt(squared) > precision; step++ {
+		operator(direction, product)
+		var curvature = dot(direction, product)
+		if curvature <= 0 {
+			return
+		}
+		var length = squared / curvature
+		for i := range y {
+			y[i] += length * direction[i]
+			residual[i] -= length * product[i]
+		}
+		removeMean(residual)
+		var next = dot(residual, residual)
+		for i := range direction {
+			direction[i] = residual[i] + next/squared*direction[i]
+		}
+		squared = next
+	}
+}
+
+// fiedlerPair returns the eigenpair of the second smallest eigenvalue of the Laplacian matrix.
+// For a disconnected graph the eigenvalue is 0, with a vector constant on the component of the
+// first node and on the rest. Otherwise it is found by inverse iteration orthogonal to the
+// constant vector, whose linear systems are solved by conjugate gradients, until the residual is
+// within options.Tolerance times a bound on the eigenvalues.
+func fiedlerPair(graph Graph, options SpectralOptions) (Eigenpair, error) {
+	var n = len(graph.Nodes)
+	if n < 2 {
+		return Eigenpair{}, errors.New("graph needs at least two nodes")
+	}
+	var operator, bound, err = spectralOperator(graph, SpectralLaplacian)
+	if err != nil {
+		return Eigenpair{}, err
+	}
+	options = options.withDefaults()
+
+	var indices = indexNodes(graph)
+	var components = newDisjointSet(n)
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok {
+				components.union(i, j)
+			}
+		}
+	}
+	var size = 0
+	for i := range graph.Nodes {
+		if components.find(i) == components.find(0) {
+			size++
+		}
+	}
+	if size < n {
+		// Entries on either side are chosen to sum to 0 and make a unit vector
+		var inside, outside = math.Sqrt(float64(n-size) / float64(size*n)), -math.Sqrt(float64(size) / float64((n-size)*n))
+		var vector = make([]float64, n)
+		for i := range vector {
+			vector[i] = outside
+			if components.find(i) == components.find(0) {
+				vector[i] = inside
+			}
+		}
+		return Eigenpair{Value: 0, Vector: vector}, nil
+	}
+
+	var random = rand.New(rand.NewSource(options.Seed))
+	var vector = make([]float64, n)
+	for i := range vector {
+		vector[i] = random.Float64() - 0.5
+	}
+	removeMean(vector)
+	normalize(vector, 2)
+	var limit = options.Tolerance * bound
+	var product = make([]float64, n)
+	var solution = make([]float64, n)
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		operator(vector, product)
+		var value = dot(vector, product)
+		var residual float64
+		for i := range product {
+			residual += (product[i] - value*vector[i]) * (product[i] - value*vector[i])
+		}
+		if math.Sqrt(residual) <= limit {
+			orientVector(vector)
+			return Eigenpair{Value: value, Vector: vector}, nil
+		}
+		// The solution is near the vector divided by its eigenvalue, and errors in it are damped
+		// by the ratio of the eigenvalue to the others, so it is only solved as precisely as the
+		// residual requires
+		for i := range solution {
+			solution[i] = vector[i] / value
+		}
+		conjugateGradient(operator, vector, solution, math.Min(0.1*limit/value, 0.1), 10*n+100)
+		removeMean(solution)
+		normalize(solution, 2)
+		vector, solution = solution, vector
+	}
+	return Eigenpair{}, errors.New("inverse iteration did not converge within the maximum iterations")
+}
+
+// AlgebraicConnectivity computes the second smallest eigenvalue of the Laplacian matrix of an
+// undirected graph, which is positive exactly when the graph is connected and grows the harder
+// the graph is to cut apart. An error is returned if the graph has fewer than two nodes.
+func AlgebraicConnectivity(graph Graph, options SpectralOptions) (float64, error) {
+	var pair, err = fiedlerPair(graph, options)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(pair.Value, 0), nil
+}
+
+// FiedlerVector computes the eigenvector of the algebraic connectivity of an undirected graph,
+// keyed by node ID. Nodes with values of the same sign tend to be well connected to each other.
+// An error is returned if the graph has fewer than two nodes.
+func FiedlerVector(graph Graph, options SpectralOptions) (map[string]float64, error) {
+	var pair, err = fiedlerPair(graph, options)
+	if err != nil {
+		return nil, err
+	}
+	var vector = make(map[string]float64, len(graph.Nodes))
+	for index, node := range graph.Nodes {
+		vector[node.ID] = pair.Vector[index]
+	}
+	return vector, nil
+}
+
+// SpectralBisection splits the nodes of an undirected graph into two halves with few edges
+// between them, by ordering the nodes by their entries in the Fiedler vector and cutting the
+// order in the middle. The first half holds the nodes with the smaller entries, and has the
+// extra node when the number of nodes is odd; both halves list nodes in the order of the
+// graph. An error is returned if the graph has fewer than two nodes.
+func SpectralBisection(graph Graph, options SpectralOptions) ([]*Node, []*Node, error) {
+	var pair, err = fiedlerPair(graph, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var order = identityPartition(len(graph.Nodes))
+	sort.SliceStable(order, func(a, b int) bool { return pair.Vector[order[a]] < pair.Vector[order[b]] })
+	var inFirst = make([]bool, len(order))
+	for _, i := range order[:(len(order)+1)/2] {
+		inFirst[i] = true
+	}
+	var first, second []*Node
+	for i, node := range graph.Nodes {
+		if inFirst[i] {
+			first = append(first, node)
+		} else {
+			second = append(second, node)
+		}
+	}
+	return first, second, nil
+}
@@ -0,0 +1,200 @@
+package gograph
+
+import (
+	"math"
+	"testing"
+)
+
+// createPathGraph builds an undirected path through n nodes
+func createPathGraph(n int) Graph {
+	var edges [][3]float64
+	for i := 0; i+1 < n; i++ {
+		edges = append(edges, [3]float64{float64(i), float64(i + 1), 1})
+	}
+	return createWeightedGraph(n, edges)
+}
+
+func eigenvalues(pairs []Eigenpair) []float64 {
+	var values []float64
+	for _, pair := range pairs {
+		values = append(values, pair.Value)
+	}
+	return values
+}
+
+func expectEqualFloatSlices(values []float64, expectation []float64, t *testing.T) {
+	expectEqualInts(len(values), len(expectation), t)
+	for i := range expectation {
+		if i < len(values) {
+			expectEqualFloats(values[i], expectation[i], t)
+		}
+	}
+}
+
+func TestPowerIteration(t *testing.T) {
+	describe("PowerIteration", t)
+	var cycle = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+
+	it("finds the largest eigenvalue of the adjacency matrix of a bipartite graph", t)
+	var pair, err = PowerIteration(cycle, SpectralAdjacency, DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(pair.Value, 2, t)
+	expectEqualFloatSlices(pair.Vector, []float64{0.5, 0.5, 0.5, 0.5}, t)
+
+	it("finds the largest eigenvalue of the Laplacian matrix", t)
+	pair, _ = PowerIteration(createPathGraph(4), SpectralLaplacian, DefaultSpectralOptions())
+	expectEqualFloats(pair.Value, 2+math.Sqrt2, t)
+
+	it("finds the largest eigenvalue of the normalized Laplacian matrix", t)
+	pair, _ = PowerIteration(cycle, SpectralNormalizedLaplacian, DefaultSpectralOptions())
+	expectEqualFloats(pair.Value, 2, t)
+
+	context("the iteration cannot converge in time", t)
+	var options = DefaultSpectralOptions()
+	options.MaxIterations = 1
+
+	it("returns an error", t)
+	_, err = PowerIteration(createPathGraph(4), SpectralLaplacian, options)
+	expectEqualBools(err == nil, false, t)
+
+	context("the graph has no nodes", t)
+
+	it("returns an error", t)
+	_, err = PowerIteration(Graph{}, SpectralAdjacency, DefaultSpectralOptions())
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestLanczosEigenpairs(t *testing.T) {
+	describe("LanczosEigenpairs", t)
+	var path = createPathGraph(4)
+
+	it("finds the whole spectrum of the Laplacian matrix in increasing order", t)
+	var pairs, err = LanczosEigenpairs(path, SpectralLaplacian, DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloatSlices(eigenvalues(pairs), []float64{0, 2 - math.Sqrt2, 2, 2 + math.Sqrt2}, t)
+
+	it("returns unit eigenvectors", t)
+	var laplacian = CreateUndirectedLaplacianMatrix(path)
+	for _, pair := range pairs {
+		var length float64
+		for i, row := range laplacian {
+			var product float64
+			for j, entry := range row {
+				product += float64(entry) * pair.Vector[j]
+			}
+			expectEqualFloats(product, pair.Value*pair.Vector[i], t)
+			length += pair.Vector[i] * pair.Vector[i]
+		}
+		expectEqualFloats(length, 1, t)
+	}
+
+	it("counts self-loops twice on the diagonal of the adjacency matrix", t)
+	pairs, _ = LanczosEigenpairs(createWeightedGraph(1, [][3]float64{{0, 0, 1}}), SpectralAdjacency, DefaultSpectralOptions())
+	expectEqualFloatSlices(eigenvalues(pairs), []float64{2}, t)
+
+	context("the graph is disconnected", t)
+	var disconnected = createWeightedGraph(5, [][3]float64{{0, 1, 1}, {2, 3, 1}})
+
+	it("finds repeated eigenvalues", t)
+	pairs, _ = LanczosEigenpairs(disconnected, SpectralAdjacency, DefaultSpectralOptions())
+	expectEqualFloatSlices(eigenvalues(pairs), []float64{-1, -1, 0, 1, 1}, t)
+
+	context("there are fewer steps than nodes", t)
+	var options = DefaultSpectralOptions()
+	options.Steps = 30
+
+	// A path with a hub joined to every node, whose largest eigenvalue stands well apart
+	var wheel = createPathGraph(200)
+	wheel, _ = CreateNode(wheel)
+	for _, node := range wheel.Nodes[:200] {
+		wheel, _, _ = CreateEdge(wheel, wheel.Nodes[200], node)
+	}
+
+	it("approximates the eigenvalues at the ends of the spectrum", t)
+	pairs, _ = LanczosEigenpairs(wheel, SpectralAdjacency, options)
+	expectEqualInts(len(pairs), 30, t)
+	var whole = DefaultSpectralOptions()
+	whole.Steps = len(wheel.Nodes)
+	var spectrum, _ = LanczosEigenpairs(wheel, SpectralAdjacency, whole)
+	expectEqualFloats(pairs[29].Value, spectrum[200].Value, t)
+	expectEqualFloats(pairs[0].Value, spectrum[0].Value, t)
+
+	context("the ends of the spectrum cannot converge in time", t)
+	options.Steps = 10
+	options.MaxIterations = 10
+
+	it("returns an error", t)
+	_, err = LanczosEigenpairs(createPathGraph(200), SpectralAdjacency, options)
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestAlgebraicConnectivity(t *testing.T) {
+	describe("AlgebraicConnectivity", t)
+	var complete = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {1, 2, 1}, {1, 3, 1}, {2, 3, 1}})
+
+	it("is the second smallest eigenvalue of the Laplacian matrix", t)
+	var connectivity, err = AlgebraicConnectivity(complete, DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(connectivity, 4, t)
+	connectivity, _ = AlgebraicConnectivity(createPathGraph(4), DefaultSpectralOptions())
+	expectEqualFloats(connectivity, 2-math.Sqrt2, t)
+
+	context("the graph is disconnected", t)
+
+	it("is zero", t)
+	connectivity, _ = AlgebraicConnectivity(createWeightedGraph(4, [][3]float64{{0, 1, 1}, {2, 3, 1}}), DefaultSpectralOptions())
+	expectEqualFloats(connectivity, 0, t)
+
+	context("the graph has thousands of nodes", t)
+
+	it("is found without computing the rest of the spectrum", t)
+	connectivity, err = AlgebraicConnectivity(createPathGraph(3000), DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(connectivity, 2-2*math.Cos(math.Pi/3000), t)
+
+	context("the graph has a single node", t)
+
+	it("returns an error", t)
+	_, err = AlgebraicConnectivity(createWeightedGraph(1, nil), DefaultSpectralOptions())
+	expectEqualBools(err == nil, false, t)
+}
+
+func TestFiedlerVector(t *testing.T) {
+	describe("FiedlerVector", t)
+	var path = createPathGraph(3)
+
+	it("is the eigenvector of the algebraic connectivity, keyed by node ID", t)
+	var vector, err = FiedlerVector(path, DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualFloats(vector[path.Nodes[0].ID], 1/math.Sqrt2, t)
+	expectEqualFloats(vector[path.Nodes[1].ID], 0, t)
+	expectEqualFloats(vector[path.Nodes[2].ID], -1/math.Sqrt2, t)
+}
+
+func TestSpectralBisection(t *testing.T) {
+	describe("SpectralBisection", t)
+	// Two triangles joined by a single edge
+	var graph = createWeightedGraph(6, [][3]float64{
+		{0, 2, 1}, {2, 4, 1}, {4, 0, 1}, {1, 3, 1}, {3, 5, 1}, {5, 1, 1}, {4, 5, 1},
+	})
+
+	it("separates the sparsely joined parts of a graph", t)
+	var first, second, err = SpectralBisection(graph, DefaultSpectralOptions())
+	expectEqualBools(err == nil, true, t)
+	expectEqualInts(len(first), 3, t)
+	expectEqualInts(len(second), 3, t)
+	var side = map[*Node]int{}
+	for _, node := range second {
+		side[node] = 1
+	}
+	expectEqualBools(side[graph.Nodes[0]] == side[graph.Nodes[2]] && side[graph.Nodes[2]] == side[graph.Nodes[4]], true, t)
+	expectEqualBools(side[graph.Nodes[1]] == side[graph.Nodes[3]] && side[graph.Nodes[3]] == side[graph.Nodes[5]], true, t)
+	expectEqualBools(side[graph.Nodes[0]] != side[graph.Nodes[1]], true, t)
+
+	context("the number of nodes is odd", t)
+
+	it("puts the extra node in the first half", t)
+	first, second, _ = SpectralBisection(createPathGraph(5), DefaultSpectralOptions())
+	expectEqualInts(len(first), 3, t)
+	expectEqualInts(len(second), 2, t)
+}
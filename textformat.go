@@ -0,0 +1,1074 @@
+package gograph
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TextFormat selects a plain text format of graphs, as used by benchmark datasets
+type TextFormat int
+
+const (
+	// EdgeListFormat lists one edge per line as a source, a target and an optional weight,
+	// separated by a delimiter: commas for CSV, tabs for TSV, or spaces for the whitespace
+	// separated lists of SNAP. Lines starting with "#" are comments. Nodes without edges are
+	// not listed.
+	EdgeListFormat TextFormat = iota
+	// AdjacencyListFormat lists one node per line, followed by its children, or by its
+	// neighbors that are listed after it in undirected graphs, separated by whitespace. Lines
+	// starting with "#" are comments.
+	AdjacencyListFormat
+	// PajekFormat is the .net format of Pajek: a *Vertices section numbering the nodes with
+	// their IDs as labels, then *Arcs of directed or *Edges of undirected edges, by number with
+	// an optional weight.
+	PajekFormat
+	// DIMACSFormat is the format of the DIMACS challenges: a "p sp" problem line, or "p edge"
+	// for undirected graphs, giving the node and edge counts, then "a" lines of directed or
+	// "e" lines of undirected edges between nodes numbered from 1, with an optional weight.
+	// Node IDs are not kept: nodes are read with IDs "1" to "n", in order.
+	DIMACSFormat
+	// MatrixMarketFormat is the coordinate format of the Matrix Market, listing the entries of
+	// the adjacency matrix, with weights as values, as a general matrix for directed graphs or
+	// a symmetric matrix for undirected graphs. Node IDs are not kept: nodes are read with IDs
+	// "1" to "n", in order.
+	MatrixMarketFormat
+	// TGFFormat is the Trivial Graph Format: one node per line as an ID and an optional label,
+	// a "#" line, then one edge per line as two node IDs and an optional label. Labels are the
+	// "label" values of nodes and edges.
+	TGFFormat
+)
+
+// String names a text format
+func (format TextFormat) String() string {
+	switch format {
+	case EdgeListFormat:
+		return "edge list"
+	case AdjacencyListFormat:
+		return "adjacency list"
+	case PajekFormat:
+		return "Pajek"
+	case DIMACSFormat:
+		return "DIMACS"
+	case MatrixMarketFormat:
+		return "Matrix Market"
+	case TGFFormat:
+		return "TGF"
+	}
+	return "TextFormat(" + strconv.Itoa(int(format)) + ")"
+}
+
+// TextOptions configure reading and writing graphs in a text format. Delimiter and Header
+// apply to edge lists only.
+type TextOptions struct {
+	Format TextFormat
+	// Delimiter separates the fields of edge lists: ',' for CSV, which is the default, '\t'
+	// for TSV, or ' ' for fields separated by any run of spaces and tabs
+	Delimiter rune
+	// Header is set for edge lists whose first line names the columns
+	Header bool
+}
+
+// delimiter returns the delimiter of edge lists, defaulting to a comma
+func (options TextOptions) delimiter() (byte, error) {
+	switch {
+	case options.Delimiter == 0:
+		return ',', nil
+	case options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' || options.Delimiter >= 0x80:
+		return 0, fmt.Errorf("delimiter %q cannot separate the fields of edge lists", options.Delimiter)
+	}
+	return byte(options.Delimiter), nil
+}
+
+// textSource is a graph to be written in a text format, with its nodes numbered in order
+type textSource struct {
+	directed bool
+	IDs      []string
+	values   []map[string]string
+	// edges visits every edge once by the numbers of its nodes, stopping at the first error
+	edges func(visit func(from int, to int, values map[string]string) error) error
+}
+
+func directedTextSource(graph DirectedGraph) textSource {
+	var indices = indexDirectedNodes(graph)
+	var source = textSource{directed: true, IDs: directedNodeIDs(graph), values: make([]map[string]string, len(graph.DirectedNodes))}
+	for index, node := range graph.DirectedNodes {
+		source.values[index] = node.Values
+	}
+	source.edges = func(visit func(int, int, map[string]string) error) error {
+		for i, node := range graph.DirectedNodes {
+			for _, child := range node.Children {
+				if j, ok := indices[child]; ok {
+					if err := visit(i, j, node.EdgeValues[child.ID]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return source
+}
+
+func undirectedTextSource(graph Graph) textSource {
+	var indices = indexNodes(graph)
+	var source = textSource{IDs: nodeIDs(graph), values: make([]map[string]string, len(graph.Nodes))}
+	for index, node := range graph.Nodes {
+		source.values[index] = node.Values
+	}
+	source.edges = func(visit func(int, int, map[string]string) error) error {
+		for i, node := range graph.Nodes {
+			for _, neighbor := range node.Edges {
+				if j, ok := indices[neighbor]; ok && i <= j {
+					if err := visit(i, j, node.EdgeValues[neighbor.ID]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return source
+}
+
+// checkLineText returns an error if a text would break the line it is written on
+func checkLineText(format TextFormat, text string) error {
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("%v cannot hold %q, which has a line break", format, text)
+	}
+	return nil
+}
+
+// appendDelimited appends a field of a delimited line, quoting it as CSV does when it is empty,
+// holds the delimiter or a quote, or could be taken for a comment
+func appendDelimited(line []byte, field string, delimiter byte) []byte {
+	if field != "" && !strings.ContainsAny(field, string(delimiter)+`"`) && field[0] != '#' {
+		return append(line, field...)
+	}
+	line = append(line, '"')
+	for index := 0; index < len(field); index++ {
+		if field[index] == '"' {
+			line = append(line, '"')
+		}
+		line = append(line, field[index])
+	}
+	return append(line, '"')
+}
+
+// appendQuoted appends a field of a whitespace separated line, quoting it with backslash
+// escapes when it is empty or holds whitespace or a quote, or when forced
+func appendQuoted(line []byte, field string, force bool) []byte {
+	if !force && field != "" && !strings.ContainsAny(field, " \t\"") && !strings.ContainsAny(field[:1], "#%*") {
+		return append(line, field...)
+	}
+	line = append(line, '"')
+	for index := 0; index < len(field); index++ {
+		if field[index] == '"' || field[index] == '\\' {
+			line = append(line, '\\')
+		}
+		line = append(line, field[index])
+	}
+	return append(line, '"')
+}
+
+// lineReader reads a text format line by line, reusing its buffers, so that the lines it
+// returns are only valid until the next one is read
+type lineReader struct {
+	format TextFormat
+	reader *bufio.Reader
+	long   []byte
+	number int
+}
+
+// next returns the next line without its line break, or io.EOF after the last line
+func (lines *lineReader) next() ([]byte, error) {
+	var line, err = lines.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		lines.long = append(lines.long[:0], line...)
+		for err == bufio.ErrBufferFull {
+			line, err = lines.reader.ReadSlice('\n')
+			lines.long = append(lines.long, line...)
+		}
+		line = lines.long
+	}
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines.number++
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+// syntaxError returns an error naming the format and the line last read
+func (lines *lineReader) syntaxError(format string, arguments ...interface{}) error {
+	return fmt.Errorf("%v syntax error on line %d: %s", lines.format, lines.number, fmt.Sprintf(format, arguments...))
+}
+
+// splitDelimited splits a line into fields at a delimiter, unquoting CSV quoted fields in place
+func splitDelimited(line []byte, delimiter byte, fields [][]byte) ([][]byte, error) {
+	for start := 0; ; {
+		if start < len(line) && line[start] == '"' {
+			var end, read = start, start + 1
+			for {
+				if read >= len(line) {
+					return nil, errors.New("quoted field is not closed")
+				}
+				if line[read] == '"' {
+					if read+1 < len(line) && line[read+1] == '"' {
+						read++
+					} else {
+						break
+					}
+				}
+				line[end] = line[read]
+				end, read = end+1, read+1
+			}
+			fields = append(fields, line[start:end])
+			read++
+			if read == len(line) {
+				return fields, nil
+			}
+			if line[read] != delimiter {
+				return nil, fmt.Errorf("expected %q after a quoted field", delimiter)
+			}
+			start = read + 1
+			continue
+		}
+		var end = bytes.IndexByte(line[start:], delimiter)
+		if end < 0 {
+			return append(fields, line[start:]), nil
+		}
+		fields = append(fields, line[start:start+end])
+		start += end + 1
+	}
+}
+
+// splitWhitespace splits a line into fields at runs of spaces and tabs, unquoting fields quoted
+// with backslash escapes in place
+func splitWhitespace(line []byte, fields [][]byte) ([][]byte, error) {
+	var read = 0
+	for {
+		for read < len(line) && (line[read] == ' ' || line[read] == '\t') {
+			read++
+		}
+		if read == len(line) {
+			return fields, nil
+		}
+		var start = read
+		if line[read] != '"' {
+			for read < len(line) && line[read] != ' ' && line[read] != '\t' {
+				read++
+			}
+			fields = append(fields, line[start:read])
+			continue
+		}
+		var end = start
+		for read++; ; read++ {
+			if read >= len(line) {
+				return nil, errors.New("quoted field is not closed")
+			}
+			if line[read] == '"' {
+				break
+			}
+			if line[read] == '\\' && read+1 < len(line) {
+				read++
+			}
+			line[end] = line[read]
+			end++
+		}
+		fields = append(fields, line[start:end])
+		read++
+		if read < len(line) && line[read] != ' ' && line[read] != '\t' {
+			return nil, errors.New("expected whitespace after a quoted field")
+		}
+	}
+}
+
+// splitFields splits a line into fields at runs of spaces and tabs, for formats without quoting
+func splitFields(line []byte, fields [][]byte) [][]byte {
+	var start = -1
+	for index, char := range line {
+		if char == ' ' || char == '\t' {
+			if start >= 0 {
+				fields = append(fields, line[start:index])
+				start = -1
+			}
+		} else if start < 0 {
+			start = index
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, line[start:])
+	}
+	return fields
+}
+
+// parseCount reads a count that is not negative
+func parseCount(field []byte) (int, bool) {
+	if len(field) == 0 || len(field) > 18 {
+		return 0, false
+	}
+	var count = 0
+	for _, digit := range field {
+		if digit < '0' || digit > '9' {
+			return 0, false
+		}
+		count = count*10 + int(digit-'0')
+	}
+	return count, true
+}
+
+// parseNumber reads a node number from 1 to n, returning its index from 0
+func parseNumber(field []byte, n int) (int, bool) {
+	var number, ok = parseCount(field)
+	return number - 1, ok && number >= 1 && number <= n
+}
+
+// textGraph gathers the nodes and edges read from a text format, looking nodes up by the bytes
+// of their IDs
+type textGraph struct {
+	decodedGraph
+	index map[string]int
+}
+
+// node returns the number of the node with an ID, adding the node if it is new
+func (graph *textGraph) node(ID []byte) int {
+	if index, ok := graph.index[string(ID)]; ok {
+		return index
+	}
+	var name = string(ID)
+	graph.index[name] = len(graph.IDs)
+	graph.IDs = append(graph.IDs, name)
+	graph.values = append(graph.values, nil)
+	return len(graph.IDs) - 1
+}
+
+// numberNodes adds nodes with IDs "1" to "n"
+func (graph *textGraph) numberNodes(n int) {
+	for number := 1; number <= n; number++ {
+		graph.IDs = append(graph.IDs, strconv.Itoa(number))
+		graph.values = append(graph.values, nil)
+	}
+}
+
+// edge adds an edge with an optional value under a key
+func (graph *textGraph) edge(from int, to int, key string, value []byte) {
+	var values map[string]string
+	if value != nil {
+		values = map[string]string{key: string(value)}
+	}
+	graph.edges = append(graph.edges, [2]int{from, to})
+	graph.edgeValues = append(graph.edgeValues, values)
+}
+
+// writeEdgeList writes an edge per line, with a weight column if any edge has a weight
+func writeEdgeList(writer *bufio.Writer, source textSource, options TextOptions) error {
+	var delimiter, err = options.delimiter()
+	if err != nil {
+		return err
+	}
+	var weighted = false
+	err = source.edges(func(from int, to int, values map[string]string) error {
+		var weight, ok = values[WeightKey]
+		weighted = weighted || ok
+		if err := checkLineText(EdgeListFormat, source.IDs[from]); err != nil {
+			return err
+		}
+		if err := checkLineText(EdgeListFormat, source.IDs[to]); err != nil {
+			return err
+		}
+		return checkLineText(EdgeListFormat, weight)
+	})
+	if err != nil {
+		return err
+	}
+
+	var line []byte
+	var appendField = func(field string) {
+		if delimiter == ' ' {
+			line = appendQuoted(line, field, false)
+		} else {
+			line = appendDelimited(line, field, delimiter)
+		}
+	}
+	if options.Header {
+		line = append(line, "source"...)
+		line = append(append(line, delimiter), "target"...)
+		if weighted {
+			line = append(append(line, delimiter), "weight"...)
+		}
+		writer.Write(append(line, '\n'))
+	}
+	return source.edges(func(from int, to int, values map[string]string) error {
+		line = line[:0]
+		appendField(source.IDs[from])
+		line = append(line, delimiter)
+		appendField(source.IDs[to])
+		if weighted {
+			line = append(line, delimiter)
+			if weight, ok := values[WeightKey]; ok {
+				appendField(weight)
+			} else if delimiter == ' ' {
+				line = append(line, `""`...)
+			}
+		}
+		_, err := writer.Write(append(line, '\n'))
+		return err
+	})
+}
+
+// readEdgeList reads an edge per line, with weights from a third column
+func readEdgeList(lines *lineReader, graph *textGraph, options TextOptions) error {
+	var delimiter, err = options.delimiter()
+	if err != nil {
+		return err
+	}
+	var header = options.Header
+	var fields [][]byte
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(line) == 0 || line[0] == '#' || (delimiter == ' ' && len(bytes.Trim(line, " \t")) == 0) {
+			continue
+		}
+		if header {
+			header = false
+			continue
+		}
+		if delimiter == ' ' {
+			fields, err = splitWhitespace(line, fields[:0])
+		} else {
+			fields, err = splitDelimited(line, delimiter, fields[:0])
+		}
+		if err != nil {
+			return lines.syntaxError("%v", err)
+		}
+		if len(fields) < 2 {
+			return lines.syntaxError("expected a source and a target")
+		}
+		var from, to = graph.node(fields[0]), graph.node(fields[1])
+		var weight []byte
+		if len(fields) > 2 && len(fields[2]) > 0 {
+			weight = fields[2]
+		}
+		graph.edge(from, to, WeightKey, weight)
+	}
+}
+
+// writeAdjacencyList writes every node on a line of its own followed by its children, or by
+// its neighbors that are listed after it
+func writeAdjacencyList(writer *bufio.Writer, source textSource) error {
+	for _, ID := range source.IDs {
+		if err := checkLineText(AdjacencyListFormat, ID); err != nil {
+			return err
+		}
+	}
+	// Edges are visited in the order of their first node, so each line is written once the
+	// edges of the next node come up
+	var line []byte
+	var current = -1
+	var advance = func(until int) {
+		for current < until {
+			if current >= 0 {
+				writer.Write(append(line, '\n'))
+			}
+			current++
+			if current < len(source.IDs) {
+				line = appendQuoted(line[:0], source.IDs[current], false)
+			}
+		}
+	}
+	source.edges(func(from int, to int, values map[string]string) error {
+		advance(from)
+		line = appendQuoted(append(line, ' '), source.IDs[to], false)
+		return nil
+	})
+	advance(len(source.IDs))
+	return nil
+}
+
+// readAdjacencyList reads a node per line followed by the nodes it has edges to
+func readAdjacencyList(lines *lineReader, graph *textGraph) error {
+	var fields [][]byte
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(line) > 0 && line[0] == '#' {
+			continue
+		}
+		if fields, err = splitWhitespace(line, fields[:0]); err != nil {
+			return lines.syntaxError("%v", err)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		var from = graph.node(fields[0])
+		for _, field := range fields[1:] {
+			graph.edge(from, graph.node(field), "", nil)
+		}
+	}
+}
+
+// writePajek writes the nodes as numbered vertices labeled with their IDs, then the edges
+func writePajek(writer *bufio.Writer, source textSource) error {
+	var err = source.edges(func(from int, to int, values map[string]string) error {
+		return checkLineText(PajekFormat, values[WeightKey])
+	})
+	if err != nil {
+		return err
+	}
+	var line = append([]byte("*Vertices "), strconv.Itoa(len(source.IDs))...)
+	writer.Write(append(line, '\n'))
+	for index, ID := range source.IDs {
+		if err := checkLineText(PajekFormat, ID); err != nil {
+			return err
+		}
+		line = strconv.AppendInt(line[:0], int64(index+1), 10)
+		line = appendQuoted(append(line, ' '), ID, true)
+		writer.Write(append(line, '\n'))
+	}
+	if source.directed {
+		writer.WriteString("*Arcs\n")
+	} else {
+		writer.WriteString("*Edges\n")
+	}
+	return source.edges(func(from int, to int, values map[string]string) error {
+		line = strconv.AppendInt(line[:0], int64(from+1), 10)
+		line = strconv.AppendInt(append(line, ' '), int64(to+1), 10)
+		if weight, ok := values[WeightKey]; ok {
+			line = appendQuoted(append(line, ' '), weight, false)
+		}
+		_, err := writer.Write(append(line, '\n'))
+		return err
+	})
+}
+
+// readPajek reads the vertices of a Pajek network, which become nodes with their labels as
+// IDs, and its arcs and edges with their weights. Other sections are skipped.
+func readPajek(lines *lineReader, graph *textGraph) error {
+	var section string
+	var labels []*string
+	var vertices = -1
+	var added = false
+	var addVertices = func() error {
+		if vertices < 0 || added {
+			return nil
+		}
+		added = true
+		for index, label := range labels {
+			var ID = strconv.Itoa(index + 1)
+			if label != nil {
+				ID = *label
+			}
+			if err := graph.addNode(ID, nil, graph.index); err != nil {
+				return lines.syntaxError("%v", err)
+			}
+		}
+		return nil
+	}
+	var fields [][]byte
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			return addVertices()
+		} else if err != nil {
+			return err
+		}
+		line = bytes.TrimLeft(line, " \t")
+		if len(line) == 0 || line[0] == '%' {
+			continue
+		}
+		if fields, err = splitWhitespace(line, fields[:0]); err != nil {
+			return lines.syntaxError("%v", err)
+		}
+		if line[0] == '*' {
+			if err := addVertices(); err != nil {
+				return err
+			}
+			section = strings.ToLower(string(fields[0]))
+			switch section {
+			case "*vertices":
+				if vertices >= 0 {
+					return lines.syntaxError("vertices are listed twice")
+				}
+				var count, ok = 0, len(fields) > 1
+				if ok {
+					count, ok = parseCount(fields[1])
+				}
+				if !ok {
+					return lines.syntaxError("expected the number of vertices after *Vertices")
+				}
+				vertices, labels = count, make([]*string, count)
+			case "*arcs", "*edges", "*arcslist", "*edgeslist":
+				if vertices < 0 {
+					return lines.syntaxError("%s before *Vertices", fields[0])
+				}
+				graph.directed = graph.directed && strings.HasPrefix(section, "*arcs")
+			case "*matrix":
+				return lines.syntaxError("networks given as matrices are not supported")
+			}
+			continue
+		}
+		switch section {
+		case "*vertices":
+			var index, ok = parseNumber(fields[0], vertices)
+			if !ok {
+				return lines.syntaxError("%q is not a vertex number from 1 to %d", fields[0], vertices)
+			}
+			if len(fields) > 1 {
+				var label = string(fields[1])
+				labels[index] = &label
+			}
+		case "*arcs", "*edges", "*arcslist", "*edgeslist":
+			var numbers = fields
+			if section == "*arcs" || section == "*edges" {
+				if len(fields) < 2 {
+					return lines.syntaxError("expected two vertex numbers")
+				}
+				numbers = fields[:2]
+			}
+			var from, ok = parseNumber(numbers[0], vertices)
+			for _, field := range numbers[1:] {
+				var to, toOK = parseNumber(field, vertices)
+				if !ok || !toOK {
+					return lines.syntaxError("edge names a vertex that is not a number from 1 to %d", vertices)
+				}
+				var weight []byte
+				if len(numbers) == 2 && len(fields) > 2 {
+					weight = fields[2]
+				}
+				graph.edge(from, to, WeightKey, weight)
+			}
+		}
+	}
+}
+
+// numericWeights checks that every weight of a numeric format is a number, returning the
+// number of edges, whether any edge has a weight and whether every weight is an integer
+func numericWeights(source textSource, format TextFormat) (int, bool, bool, error) {
+	var count, weighted, integers = 0, false, true
+	var err = source.edges(func(from int, to int, values map[string]string) error {
+		count++
+		var weight, ok = values[WeightKey]
+		if !ok {
+			return nil
+		}
+		if !decimalText.MatchString(weight) {
+			return fmt.Errorf("%v weights are numbers, but the edge from %q to %q has weight %q", format, source.IDs[from], source.IDs[to], weight)
+		}
+		weighted, integers = true, integers && integerText.MatchString(weight)
+		return nil
+	})
+	return count, weighted, integers, err
+}
+
+// writeDIMACS writes a problem line and a line for every edge between nodes numbered from 1
+func writeDIMACS(writer *bufio.Writer, source textSource) error {
+	var count, _, _, err = numericWeights(source, DIMACSFormat)
+	if err != nil {
+		return err
+	}
+	var line, prefix = []byte("p sp "), []byte("a ")
+	if !source.directed {
+		line, prefix = []byte("p edge "), []byte("e ")
+	}
+	line = strconv.AppendInt(line, int64(len(source.IDs)), 10)
+	line = strconv.AppendInt(append(line, ' '), int64(count), 10)
+	writer.Write(append(line, '\n'))
+	return source.edges(func(from int, to int, values map[string]string) error {
+		line = strconv.AppendInt(append(line[:0], prefix...), int64(from+1), 10)
+		line = strconv.AppendInt(append(line, ' '), int64(to+1), 10)
+		if weight, ok := values[WeightKey]; ok {
+			line = append(append(line, ' '), weight...)
+		}
+		_, err := writer.Write(append(line, '\n'))
+		return err
+	})
+}
+
+// readDIMACS reads the problem line and the "a" and "e" lines of a DIMACS file, skipping
+// comments and node designations
+func readDIMACS(lines *lineReader, graph *textGraph) error {
+	var n, m = -1, 0
+	var fields [][]byte
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if fields = splitFields(line, fields[:0]); len(fields) == 0 {
+			continue
+		}
+		switch string(fields[0]) {
+		case "c", "n":
+		case "p":
+			if n >= 0 {
+				return lines.syntaxError("the problem is given twice")
+			}
+			var nOK, mOK bool
+			if len(fields) == 4 {
+				n, nOK = parseCount(fields[2])
+				m, mOK = parseCount(fields[3])
+			}
+			if !nOK || !mOK {
+				return lines.syntaxError("expected a problem line \"p <type> <nodes> <edges>\"")
+			}
+			graph.numberNodes(n)
+		case "a", "e":
+			if n < 0 {
+				return lines.syntaxError("edge before the problem line")
+			}
+			if len(fields) < 3 {
+				return lines.syntaxError("expected two node numbers")
+			}
+			var from, fromOK = parseNumber(fields[1], n)
+			var to, toOK = parseNumber(fields[2], n)
+			if !fromOK || !toOK {
+				return lines.syntaxError("edge names a node that is not a number from 1 to %d", n)
+			}
+			var weight []byte
+			if len(fields) > 3 {
+				weight = fields[3]
+			}
+			graph.edge(from, to, WeightKey, weight)
+			graph.directed = graph.directed && fields[0][0] == 'a'
+		default:
+			return lines.syntaxError("unknown line type %q", fields[0])
+		}
+	}
+	if n < 0 {
+		return errors.New("DIMACS file has no problem line")
+	}
+	if len(graph.edges) != m {
+		return fmt.Errorf("DIMACS problem line declares %d edges, but %d are listed", m, len(graph.edges))
+	}
+	return nil
+}
+
+// writeMatrixMarket writes the entries of the adjacency matrix in coordinate format, with the
+// weights as integer or real values if any edge has a weight, and 1 for edges without one
+func writeMatrixMarket(writer *bufio.Writer, source textSource) error {
+	var count, weighted, integers, err = numericWeights(source, MatrixMarketFormat)
+	if err != nil {
+		return err
+	}
+	var line = []byte("%%MatrixMarket matrix coordinate ")
+	switch {
+	case !weighted:
+		line = append(line, "pattern"...)
+	case integers:
+		line = append(line, "integer"...)
+	default:
+		line = append(line, "real"...)
+	}
+	if source.directed {
+		line = append(line, " general\n"...)
+	} else {
+		line = append(line, " symmetric\n"...)
+	}
+	line = strconv.AppendInt(line, int64(len(source.IDs)), 10)
+	line = strconv.AppendInt(append(line, ' '), int64(len(source.IDs)), 10)
+	line = strconv.AppendInt(append(line, ' '), int64(count), 10)
+	writer.Write(append(line, '\n'))
+	return source.edges(func(from int, to int, values map[string]string) error {
+		if !source.directed {
+			// Symmetric matrices list the entries of their lower triangle
+			from, to = to, from
+		}
+		line = strconv.AppendInt(line[:0], int64(from+1), 10)
+		line = strconv.AppendInt(append(line, ' '), int64(to+1), 10)
+		if weighted {
+			var weight, ok = values[WeightKey]
+			if !ok {
+				weight = "1"
+			}
+			line = append(append(line, ' '), weight...)
+		}
+		_, err := writer.Write(append(line, '\n'))
+		return err
+	})
+}
+
+// readMatrixMarket reads a square coordinate matrix, with an edge for every entry weighted by
+// its value. The entries of symmetric matrices are undirected edges.
+func readMatrixMarket(lines *lineReader, graph *textGraph) error {
+	var line, err = lines.next()
+	if err == io.EOF {
+		return errors.New("Matrix Market file is empty")
+	} else if err != nil {
+		return err
+	}
+	var banner = splitFields(line, nil)
+	if len(banner) != 5 || !bytes.EqualFold(banner[0], []byte("%%MatrixMarket")) || !bytes.EqualFold(banner[1], []byte("matrix")) {
+		return lines.syntaxError("expected a header \"%%%%MatrixMarket matrix coordinate <field> <symmetry>\"")
+	}
+	if !bytes.EqualFold(banner[2], []byte("coordinate")) {
+		return lines.syntaxError("only matrices in coordinate format are supported")
+	}
+	var field, symmetry = strings.ToLower(string(banner[3])), strings.ToLower(string(banner[4]))
+	if field != "real" && field != "integer" && field != "pattern" {
+		return lines.syntaxError("matrices of %s values are not supported", banner[3])
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return lines.syntaxError("%s matrices are not supported", banner[4])
+	}
+	graph.directed = symmetry == "general"
+
+	var n, entries = -1, 0
+	var fields [][]byte
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(line) > 0 && line[0] == '%' {
+			continue
+		}
+		if fields = splitFields(line, fields[:0]); len(fields) == 0 {
+			continue
+		}
+		if n < 0 {
+			var rows, rowsOK = parseCount(fields[0])
+			var columns, columnsOK, entriesOK = 0, false, false
+			if len(fields) == 3 {
+				columns, columnsOK = parseCount(fields[1])
+				entries, entriesOK = parseCount(fields[2])
+			}
+			if !rowsOK || !columnsOK || !entriesOK {
+				return lines.syntaxError("expected the size line \"<rows> <columns> <entries>\"")
+			}
+			if rows != columns {
+				return lines.syntaxError("matrix is not square: it has %d rows and %d columns", rows, columns)
+			}
+			n = rows
+			graph.numberNodes(n)
+			continue
+		}
+		if len(fields) < 2 || (field != "pattern" && len(fields) < 3) {
+			return lines.syntaxError("expected an entry \"<row> <column> <value>\"")
+		}
+		var from, fromOK = parseNumber(fields[0], n)
+		var to, toOK = parseNumber(fields[1], n)
+		if !fromOK || !toOK {
+			return lines.syntaxError("entry is not in a row and column from 1 to %d", n)
+		}
+		var weight []byte
+		if field != "pattern" {
+			if !decimalText.Match(fields[2]) {
+				return lines.syntaxError("entry value %q is not a number", fields[2])
+			}
+			weight = fields[2]
+		}
+		graph.edge(from, to, WeightKey, weight)
+	}
+	if n < 0 {
+		return errors.New("Matrix Market file has no size line")
+	}
+	if len(graph.edges) != entries {
+		return fmt.Errorf("Matrix Market size line declares %d entries, but %d are listed", entries, len(graph.edges))
+	}
+	return nil
+}
+
+// writeTGF writes every node with its label, a "#" line, then every edge with its label
+func writeTGF(writer *bufio.Writer, source textSource) error {
+	var line []byte
+	for index, ID := range source.IDs {
+		if ID == "" || ID == "#" || strings.ContainsAny(ID, " \t\r\n") {
+			return fmt.Errorf("TGF cannot hold node ID %q, which is empty, \"#\" or has whitespace", ID)
+		}
+		line = append(line[:0], ID...)
+		if label, ok := source.values[index]["label"]; ok {
+			if err := checkLineText(TGFFormat, label); err != nil {
+				return err
+			}
+			line = append(append(line, ' '), label...)
+		}
+		writer.Write(append(line, '\n'))
+	}
+	writer.WriteString("#\n")
+	return source.edges(func(from int, to int, values map[string]string) error {
+		line = append(append(append(line[:0], source.IDs[from]...), ' '), source.IDs[to]...)
+		if label, ok := values["label"]; ok {
+			if err := checkLineText(TGFFormat, label); err != nil {
+				return err
+			}
+			line = append(append(line, ' '), label...)
+		}
+		_, err := writer.Write(append(line, '\n'))
+		return err
+	})
+}
+
+// cutField splits the first field of a line from the rest, which is trimmed of the whitespace
+// that separates them
+func cutField(line []byte) ([]byte, []byte) {
+	line = bytes.TrimLeft(line, " \t")
+	var end = bytes.IndexAny(line, " \t")
+	if end < 0 {
+		return line, nil
+	}
+	return line[:end], bytes.TrimLeft(line[end:], " \t")
+}
+
+// readTGF reads the nodes of a TGF file with their labels, then its edges with their labels
+func readTGF(lines *lineReader, graph *textGraph) error {
+	var edges = false
+	for {
+		var line, err = lines.next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var first, rest = cutField(line)
+		if len(first) == 0 {
+			continue
+		}
+		if !edges {
+			if string(first) == "#" {
+				edges = true
+				continue
+			}
+			var values map[string]string
+			if len(rest) > 0 {
+				values = map[string]string{"label": string(rest)}
+			}
+			if err := graph.addNode(string(first), values, graph.index); err != nil {
+				return lines.syntaxError("%v", err)
+			}
+			continue
+		}
+		var second, label = cutField(rest)
+		var from, fromOK = graph.index[string(first)]
+		var to, toOK = graph.index[string(second)]
+		if !fromOK || !toOK {
+			return lines.syntaxError("edge names a node that is not listed")
+		}
+		if len(label) == 0 {
+			label = nil
+		}
+		graph.edge(from, to, "label", label)
+	}
+}
+
+// writeText writes a graph in a text format through a buffer
+func writeText(writer io.Writer, source textSource, options TextOptions) error {
+	var buffered = bufio.NewWriter(writer)
+	var err error
+	switch options.Format {
+	case EdgeListFormat:
+		err = writeEdgeList(buffered, source, options)
+	case AdjacencyListFormat:
+		err = writeAdjacencyList(buffered, source)
+	case PajekFormat:
+		err = writePajek(buffered, source)
+	case DIMACSFormat:
+		err = writeDIMACS(buffered, source)
+	case MatrixMarketFormat:
+		err = writeMatrixMarket(buffered, source)
+	case TGFFormat:
+		err = writeTGF(buffered, source)
+	default:
+		err = fmt.Errorf("unknown text format %v", options.Format)
+	}
+	if err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// WriteTextFormat writes a directed graph in a plain text format, streaming it line by line.
+// Edges keep their weights in the formats with weights, and TGF keeps the "label" values of
+// nodes and edges; other values are left out. Node IDs with line breaks, and weights that are
+// not numbers in DIMACS and Matrix Market, cannot be written and give an error.
+func WriteTextFormat(writer io.Writer, graph DirectedGraph, options TextOptions) error {
+	return writeText(writer, directedTextSource(graph), options)
+}
+
+// WriteUndirectedTextFormat writes an undirected graph in a plain text format, like
+// WriteTextFormat, with every edge written once
+func WriteUndirectedTextFormat(writer io.Writer, graph Graph, options TextOptions) error {
+	return writeText(writer, undirectedTextSource(graph), options)
+}
+
+// decodeText reads a graph in a text format line by line
+func decodeText(reader io.Reader, options TextOptions) (*textGraph, error) {
+	var lines = &lineReader{format: options.Format, reader: bufio.NewReaderSize(reader, 64*1024)}
+	var graph = &textGraph{decodedGraph: decodedGraph{directed: true}, index: map[string]int{}}
+	var err error
+	switch options.Format {
+	case EdgeListFormat:
+		err = readEdgeList(lines, graph, options)
+	case AdjacencyListFormat:
+		err = readAdjacencyList(lines, graph)
+	case PajekFormat:
+		err = readPajek(lines, graph)
+	case DIMACSFormat:
+		err = readDIMACS(lines, graph)
+	case MatrixMarketFormat:
+		err = readMatrixMarket(lines, graph)
+	case TGFFormat:
+		err = readTGF(lines, graph)
+	default:
+		err = fmt.Errorf("unknown text format %v", options.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(graph.IDs) > 0 {
+		// Text formats cannot record a root, so the first node listed becomes it
+		graph.root = &graph.IDs[0]
+	}
+	return graph, nil
+}
+
+// ReadTextFormat builds a directed graph from a plain text format, reading it line by line so
+// that graphs with millions of edges load without a string for every line. Nodes are added in
+// the order they are first listed, the first becoming the root, and edges keep their weights
+// under WeightKey as text, or their TGF labels under "label". An error naming the line is
+// returned if the text is malformed, and an error is returned if the graph has undirected edges.
+func ReadTextFormat(reader io.Reader, options TextOptions) (DirectedGraph, error) {
+	var decoded, err = decodeText(reader, options)
+	if err != nil {
+		return DirectedGraph{}, err
+	}
+	if !decoded.directed {
+		return DirectedGraph{}, fmt.Errorf("%v graph has undirected edges; read it with ReadUndirectedTextFormat", options.Format)
+	}
+	return decoded.directedGraph()
+}
+
+// ReadUndirectedTextFormat builds an undirected graph from a plain text format, like
+// ReadTextFormat. Directed edges become undirected edges.
+func ReadUndirectedTextFormat(reader io.Reader, options TextOptions) (Graph, error) {
+	var decoded, err = decodeText(reader, options)
+	if err != nil {
+		return Graph{}, err
+	}
+	return decoded.undirectedGraph(), nil
+}
@@ -0,0 +1,248 @@
+package gograph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// createTextTestGraph builds the graph of createDOTTestGraph with a labeled sink and edge
+func createTextTestGraph() DirectedGraph {
+	var graph = createDOTTestGraph()
+	graph.DirectedNodes[2].Values = map[string]string{"label": "Sink"}
+	SetDirectedEdgeValue(graph.DirectedNodes[1], graph.DirectedNodes[2], "label", "feeds")
+	return graph
+}
+
+func TestWriteTextFormat(t *testing.T) {
+	describe("WriteTextFormat", t)
+	var graph = createTextTestGraph()
+	var write = func(options TextOptions) string {
+		var buffer bytes.Buffer
+		var err = WriteTextFormat(&buffer, graph, options)
+		expectEqualBools(err == nil, true, t)
+		return buffer.String()
+	}
+
+	it("writes edge lists with a weight column", t)
+	expectEqualStrings(write(TextOptions{}), "a,b,2.5\na,c,\nb,c,\n", t)
+	expectEqualStrings(write(TextOptions{Delimiter: '\t', Header: true}), "source\ttarget\tweight\na\tb\t2.5\na\tc\t\nb\tc\t\n", t)
+
+	it("writes adjacency lists", t)
+	expectEqualStrings(write(TextOptions{Format: AdjacencyListFormat}), "a b c\nb c\nc\n", t)
+
+	it("writes Pajek networks with IDs as vertex labels", t)
+	expectEqualStrings(write(TextOptions{Format: PajekFormat}), "*Vertices 3\n1 \"a\"\n2 \"b\"\n3 \"c\"\n*Arcs\n1 2 2.5\n1 3\n2 3\n", t)
+
+	it("writes DIMACS problems", t)
+	expectEqualStrings(write(TextOptions{Format: DIMACSFormat}), "p sp 3 3\na 1 2 2.5\na 1 3\na 2 3\n", t)
+
+	it("writes Matrix Market matrices, weighting edges without a weight by 1", t)
+	expectEqualStrings(write(TextOptions{Format: MatrixMarketFormat}), "%%MatrixMarket matrix coordinate real general\n3 3 3\n1 2 2.5\n1 3 1\n2 3 1\n", t)
+
+	it("writes TGF with labels", t)
+	expectEqualStrings(write(TextOptions{Format: TGFFormat}), "a\nb\nc Sink\n#\na b\na c\nb c feeds\n", t)
+
+	it("quotes IDs that would break a field", t)
+	var quoted = createDirectedNodes([]string{"x, y", "say \"hi\"", "#tag", ""}, MatrixGraphOptions{})
+	quoted, _, _ = CreateDirectedEdge(quoted, quoted.DirectedNodes[0], quoted.DirectedNodes[1])
+	quoted, _, _ = CreateDirectedEdge(quoted, quoted.DirectedNodes[2], quoted.DirectedNodes[3])
+	var buffer bytes.Buffer
+	WriteTextFormat(&buffer, quoted, TextOptions{})
+	expectEqualStrings(buffer.String(), "\"x, y\",\"say \"\"hi\"\"\"\n\"#tag\",\"\"\n", t)
+	buffer.Reset()
+	WriteTextFormat(&buffer, quoted, TextOptions{Format: AdjacencyListFormat})
+	expectEqualStrings(buffer.String(), "\"x, y\" \"say \\\"hi\\\"\"\n\"say \\\"hi\\\"\"\n\"#tag\" \"\"\n\"\"\n", t)
+
+	context("the graph does not fit the format", t)
+
+	it("returns an error", t)
+	var broken = createDirectedNodes([]string{"line\nbreak"}, MatrixGraphOptions{})
+	expectEqualBools(WriteTextFormat(&buffer, broken, TextOptions{}) == nil, true, t)
+	broken, _, _ = CreateDirectedEdge(broken, broken.DirectedNodes[0], broken.DirectedNodes[0])
+	expectEqualBools(WriteTextFormat(&buffer, broken, TextOptions{}) == nil, false, t)
+	expectEqualBools(WriteTextFormat(&buffer, broken, TextOptions{Format: PajekFormat}) == nil, false, t)
+	expectEqualBools(WriteTextFormat(&buffer, quoted, TextOptions{Format: TGFFormat}) == nil, false, t)
+	SetDirectedEdgeValue(graph.DirectedNodes[0], graph.DirectedNodes[2], WeightKey, "heavy")
+	expectEqualBools(WriteTextFormat(&buffer, graph, TextOptions{Format: DIMACSFormat}) == nil, false, t)
+	expectEqualBools(WriteTextFormat(&buffer, graph, TextOptions{Format: MatrixMarketFormat}) == nil, false, t)
+	expectEqualBools(WriteTextFormat(&buffer, graph, TextOptions{Delimiter: '"'}) == nil, false, t)
+	expectEqualBools(WriteTextFormat(&buffer, graph, TextOptions{Format: TextFormat(-1)}) == nil, false, t)
+}
+
+func TestWriteUndirectedTextFormat(t *testing.T) {
+	describe("WriteUndirectedTextFormat", t)
+	var graph = CreateUndirectedGraph(createTextTestGraph())
+	var write = func(options TextOptions) string {
+		var buffer bytes.Buffer
+		WriteUndirectedTextFormat(&buffer, graph, options)
+		return buffer.String()
+	}
+
+	it("writes every edge once", t)
+	expectEqualStrings(write(TextOptions{Delimiter: ' '}), "a b 2.5\na c \"\"\nb c \"\"\n", t)
+	expectEqualStrings(write(TextOptions{Format: PajekFormat}), "*Vertices 3\n1 \"a\"\n2 \"b\"\n3 \"c\"\n*Edges\n1 2 2.5\n1 3\n2 3\n", t)
+	expectEqualStrings(write(TextOptions{Format: DIMACSFormat}), "p edge 3 3\ne 1 2 2.5\ne 1 3\ne 2 3\n", t)
+
+	it("writes symmetric matrices by their lower triangle", t)
+	expectEqualStrings(write(TextOptions{Format: MatrixMarketFormat}), "%%MatrixMarket matrix coordinate real symmetric\n3 3 3\n2 1 2.5\n3 1 1\n3 2 1\n", t)
+}
+
+func TestReadTextFormat(t *testing.T) {
+	describe("ReadTextFormat", t)
+	var graph = createTextTestGraph()
+	graph.DirectedNodes[0].Values = nil
+	graph.DirectedNodes[1].Values = nil
+
+	it("gives back a graph written in Pajek or TGF", t)
+	graph.DirectedNodes[0].ID = "first node"
+	for _, format := range []TextFormat{PajekFormat, TGFFormat} {
+		var buffer bytes.Buffer
+		if format == TGFFormat {
+			graph.DirectedNodes[0].ID = "first"
+			graph.DirectedNodes[0].EdgeValues = map[string]map[string]string{"b": {"label": "2.5"}}
+		}
+		WriteTextFormat(&buffer, graph, TextOptions{Format: format})
+		var read, err = ReadTextFormat(&buffer, TextOptions{Format: format})
+		expectEqualBools(err == nil, true, t)
+		var expected = createTextTestGraph()
+		expected.DirectedNodes[0].Values, expected.DirectedNodes[1].Values = nil, nil
+		expected.DirectedNodes[0].ID = graph.DirectedNodes[0].ID
+		if format == PajekFormat {
+			expected.DirectedNodes[2].Values = nil
+			delete(expected.DirectedNodes[1].EdgeValues, "c")
+		} else {
+			expected.DirectedNodes[0].EdgeValues = map[string]map[string]string{"b": {"label": "2.5"}}
+		}
+		expectEqualStrings(describeDirectedGraph(read), describeDirectedGraph(expected), t)
+	}
+
+	it("reads SNAP edge lists with comments", t)
+	var read, err = ReadTextFormat(strings.NewReader("# Directed graph: web.txt\n# FromNodeId\tToNodeId\n10\t20\n10   30\n\n30\t10\t4\n"), TextOptions{Delimiter: ' '})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:10\n"+
+		"10 map[] -> [20 30] map[]\n"+
+		"20 map[] -> [] map[]\n"+
+		"30 map[] -> [10] map[10:map[weight:4]]", t)
+
+	it("reads CSV edge lists with a header and quoted IDs", t)
+	read, _ = ReadTextFormat(strings.NewReader("from,to,weight,kind\r\n\"Smith, J\",\"say \"\"hi\"\"\",0.5,extra\r\nx,,\r\n"), TextOptions{Header: true})
+	expectEqualStrings(describeDirectedGraph(read), "root:Smith, J\n"+
+		"Smith, J map[] -> [say \"hi\"] map[say \"hi\":map[weight:0.5]]\n"+
+		"say \"hi\" map[] -> [] map[]\n"+
+		"x map[] -> [] map[]\n"+
+		" map[] -> [] map[]", t)
+	expectEqualStrings(read.DirectedNodes[2].Children[0].ID, "", t)
+
+	it("reads adjacency lists", t)
+	read, _ = ReadTextFormat(strings.NewReader("# comment\na b c\nb \"c d\"\nc\n\"c d\" a a\n"), TextOptions{Format: AdjacencyListFormat})
+	expectEqualStrings(describeDirectedGraph(read), "root:a\n"+
+		"a map[] -> [b c] map[]\n"+
+		"b map[] -> [c d] map[]\n"+
+		"c map[] -> [] map[]\n"+
+		"c d map[] -> [a a] map[]", t)
+
+	it("reads Pajek arcs and arc lists, numbering unlabeled vertices", t)
+	read, err = ReadTextFormat(strings.NewReader("% made by hand\n*Network example\n*Vertices 4\n1 \"Ann\" 0.1 0.2 0.5 ic Red\n2 Bob\n4 \"Dee\"\n*Arcs\n1 2 3 c Blue\n*Arcslist\n2 3 4\n"), TextOptions{Format: PajekFormat})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:Ann\n"+
+		"Ann map[] -> [Bob] map[Bob:map[weight:3]]\n"+
+		"Bob map[] -> [3 Dee] map[]\n"+
+		"3 map[] -> [] map[]\n"+
+		"Dee map[] -> [] map[]", t)
+
+	it("reads DIMACS shortest path problems", t)
+	read, err = ReadTextFormat(strings.NewReader("c 9th DIMACS challenge\np sp 3 2\nc arcs\na 1 2 7\na 3 1 2\n"), TextOptions{Format: DIMACSFormat})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:1\n"+
+		"1 map[] -> [2] map[2:map[weight:7]]\n"+
+		"2 map[] -> [] map[]\n"+
+		"3 map[] -> [1] map[1:map[weight:2]]", t)
+
+	it("reads general Matrix Market matrices", t)
+	read, err = ReadTextFormat(strings.NewReader("%%MatrixMarket matrix coordinate integer general\n% comment\n3 3 2\n1 3 5\n3 3 -1\n"), TextOptions{Format: MatrixMarketFormat})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeDirectedGraph(read), "root:1\n"+
+		"1 map[] -> [3] map[3:map[weight:5]]\n"+
+		"2 map[] -> [] map[]\n"+
+		"3 map[] -> [3] map[3:map[weight:-1]]", t)
+
+	context("the graph has undirected edges", t)
+
+	it("returns an error", t)
+	for _, source := range []struct {
+		format TextFormat
+		text   string
+	}{
+		{PajekFormat, "*Vertices 2\n*Edges\n1 2\n"},
+		{DIMACSFormat, "p edge 2 1\ne 1 2\n"},
+		{MatrixMarketFormat, "%%MatrixMarket matrix coordinate pattern symmetric\n2 2 1\n2 1\n"},
+	} {
+		_, err = ReadTextFormat(strings.NewReader(source.text), TextOptions{Format: source.format})
+		expectEqualBools(err == nil, false, t)
+	}
+
+	context("the text is malformed", t)
+
+	it("returns an error naming the line", t)
+	_, err = ReadTextFormat(strings.NewReader("a,b\nc\n"), TextOptions{})
+	expectEqualStrings(err.Error(), "edge list syntax error on line 2: expected a source and a target", t)
+	_, err = ReadTextFormat(strings.NewReader("a,b\n\"c,d\n"), TextOptions{})
+	expectEqualStrings(err.Error(), "edge list syntax error on line 2: quoted field is not closed", t)
+	_, err = ReadTextFormat(strings.NewReader("*Vertices 2\n3 \"c\"\n"), TextOptions{Format: PajekFormat})
+	expectEqualStrings(err.Error(), `Pajek syntax error on line 2: "3" is not a vertex number from 1 to 2`, t)
+	_, err = ReadTextFormat(strings.NewReader("p sp 2 1\na 1 3\n"), TextOptions{Format: DIMACSFormat})
+	expectEqualStrings(err.Error(), "DIMACS syntax error on line 2: edge names a node that is not a number from 1 to 2", t)
+	_, err = ReadTextFormat(strings.NewReader("%%MatrixMarket matrix array real general\n2 2\n"), TextOptions{Format: MatrixMarketFormat})
+	expectEqualStrings(err.Error(), "Matrix Market syntax error on line 1: only matrices in coordinate format are supported", t)
+	_, err = ReadTextFormat(strings.NewReader("a\n#\na b\n"), TextOptions{Format: TGFFormat})
+	expectEqualStrings(err.Error(), "TGF syntax error on line 3: edge names a node that is not listed", t)
+	for _, source := range []struct {
+		format TextFormat
+		text   string
+	}{
+		{PajekFormat, "*Arcs\n1 2\n"},
+		{PajekFormat, "*Vertices 2\n1 a\n2 a\n"},
+		{PajekFormat, "*Vertices 1\n*Matrix\n1\n"},
+		{DIMACSFormat, "a 1 2\n"},
+		{DIMACSFormat, "p sp 2 2\na 1 2\n"},
+		{DIMACSFormat, "c no problem\n"},
+		{DIMACSFormat, "p sp 2 1\nx 1 2\n"},
+		{MatrixMarketFormat, ""},
+		{MatrixMarketFormat, "%%MatrixMarket matrix coordinate real general\n2 3 0\n"},
+		{MatrixMarketFormat, "%%MatrixMarket matrix coordinate complex general\n2 2 0\n"},
+		{MatrixMarketFormat, "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 one\n"},
+		{MatrixMarketFormat, "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 1\n"},
+		{TGFFormat, "a\na\n"},
+	} {
+		_, err = ReadTextFormat(strings.NewReader(source.text), TextOptions{Format: source.format})
+		expectEqualBools(err == nil, false, t)
+	}
+}
+
+func TestReadUndirectedTextFormat(t *testing.T) {
+	describe("ReadUndirectedTextFormat", t)
+
+	it("gives back a graph written in Pajek", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {3, 3, 1}, {0, 1, 4}})
+	var buffer bytes.Buffer
+	WriteUndirectedTextFormat(&buffer, graph, TextOptions{Format: PajekFormat})
+	var read, err = ReadUndirectedTextFormat(&buffer, TextOptions{Format: PajekFormat})
+	expectEqualBools(err == nil, true, t)
+	expectEqualStrings(describeGraph(read), describeGraph(graph), t)
+
+	it("reads symmetric Matrix Market matrices and DIMACS edge problems", t)
+	read, err = ReadUndirectedTextFormat(strings.NewReader("%%MatrixMarket matrix coordinate pattern symmetric\n3 3 3\n2 1\n3 2\n3 3\n"), TextOptions{Format: MatrixMarketFormat})
+	expectEqualBools(err == nil, true, t)
+	var expected = createNodes([]string{"1", "2", "3"}, MatrixGraphOptions{})
+	expected, _, _ = CreateEdge(expected, expected.Nodes[1], expected.Nodes[0])
+	expected, _, _ = CreateEdge(expected, expected.Nodes[2], expected.Nodes[1])
+	expected, _, _ = CreateEdge(expected, expected.Nodes[2], expected.Nodes[2])
+	expectEqualStrings(describeGraph(read), describeGraph(expected), t)
+	read, _ = ReadUndirectedTextFormat(strings.NewReader("c coloring\np edge 3 3\ne 2 1\ne 3 2\ne 3 3\n"), TextOptions{Format: DIMACSFormat})
+	expectEqualStrings(describeGraph(read), describeGraph(expected), t)
+
+	it("makes directed edges undirected", t)
+	read, _ = ReadUndirectedTextFormat(strings.NewReader("a b c\nb c\n"), TextOptions{Format: AdjacencyListFormat})
+	expectEqualInts(len(read.Nodes[2].Edges), 2, t)
+}
@@ -0,0 +1,363 @@
+package gograph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// tourEpsilon is the least decrease in cost for which local search changes a tour
+const tourEpsilon = 1e-9
+
+// heldKarpLimit is the largest number of nodes HeldKarpTour accepts, beyond which its tables
+// would take hundreds of megabytes
+const heldKarpLimit = 18
+
+// distanceMatrix returns the weight of the edge between every pair of nodes of a complete
+// undirected graph, or an error naming a pair of nodes that are not adjacent
+func distanceMatrix(graph Graph) ([][]float64, error) {
+	var indices = indexNodes(graph)
+	var n = len(graph.Nodes)
+	var distances = make([][]float64, n)
+	var adjacent = make([][]bool, n)
+	for i := range distances {
+		distances[i] = make([]float64, n)
+		adjacent[i] = make([]bool, n)
+	}
+	for i, node := range graph.Nodes {
+		for _, neighbor := range node.Edges {
+			if j, ok := indices[neighbor]; ok {
+				adjacent[i][j], adjacent[j][i] = true, true
+				distances[i][j] = EdgeWeight(node, neighbor)
+				distances[j][i] = distances[i][j]
+			}
+		}
+	}
+	for i := range adjacent {
+		for j := i + 1; j < n; j++ {
+			if !adjacent[i][j] {
+				return nil, fmt.Errorf("graph is not complete: no edge joins %q and %q", graph.Nodes[i].ID, graph.Nodes[j].ID)
+			}
+		}
+	}
+	return distances, nil
+}
+
+// tourCost sums the distances around a closed tour of node indices
+func tourCost(distances [][]float64, tour []int) float64 {
+	var cost float64
+	for index := range tour {
+		cost += distances[tour[index]][tour[(index+1)%len(tour)]]
+	}
+	if len(tour) == 1 {
+		cost = 0
+	}
+	return cost
+}
+
+// tourIndices returns the indices of the nodes of a tour, or an error if the tour does not
+// visit every node of the graph exactly once
+func tourIndices(graph Graph, tour []*Node) ([]int, error) {
+	if len(tour) != len(graph.Nodes) {
+		return nil, errors.New("tour must visit every node of the graph exactly once")
+	}
+	var indices = indexNodes(graph)
+	var seen = make([]bool, len(graph.Nodes))
+	var order = make([]int, len(tour))
+	for position, node := range tour {
+		var i, ok = indices[node]
+		if !ok || seen[i] {
+			return nil, errors.New("tour must visit every node of the graph exactly once")
+		}
+		seen[i] = true
+		order[position] = i
+	}
+	return order, nil
+}
+
+// NearestNeighborTour builds a tour of a complete weighted graph by starting at its first node
+// and repeatedly travelling to the nearest unvisited node. The tour lists every node once,
+// returning from the last node to the first, and its cost includes that final edge. An error
+// is returned if the graph is not complete.
+func NearestNeighborTour(graph Graph) ([]*Node, float64, error) {
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	var n = len(graph.Nodes)
+	if n == 0 {
+		return nil, 0, nil
+	}
+	var visited = make([]bool, n)
+	var tour = []int{0}
+	visited[0] = true
+	for len(tour) < n {
+		var last, nearest = tour[len(tour)-1], -1
+		for i := range visited {
+			if !visited[i] && (nearest == -1 || distances[last][i] < distances[last][nearest]) {
+				nearest = i
+			}
+		}
+		visited[nearest] = true
+		tour = append(tour, nearest)
+	}
+	return indexedNodes(graph, tour), tourCost(distances, tour), nil
+}
+
+// minimumPerfectMatching pairs up an even number of nodes of a complete graph at the least
+// total distance, as the matching of greatest weight when every distance is subtracted from the
+// largest one
+func minimumPerfectMatching(distances [][]float64, nodes []int) [][2]int {
+	var longest float64
+	for a := range nodes {
+		for b := a + 1; b < len(nodes); b++ {
+			longest = math.Max(longest, distances[nodes[a]][nodes[b]])
+		}
+	}
+	var edges []weightedEdge
+	for a := range nodes {
+		for b := a + 1; b < len(nodes); b++ {
+			edges = append(edges, weightedEdge{from: a, to: b, weight: longest - distances[nodes[a]][nodes[b]]})
+		}
+	}
+	var pairs [][2]int
+	for a, b := range weightedMatching(len(nodes), edges) {
+		if a < b {
+			pairs = append(pairs, [2]int{nodes[a], nodes[b]})
+		}
+	}
+	return pairs
+}
+
+// ChristofidesTour builds a tour of a complete weighted graph whose weights satisfy the triangle
+// inequality, costing at most one and a half times the optimum. It joins a minimum spanning
+// tree with a minimum-weight perfect matching of the tree's odd-degree nodes, walks an
+// Eulerian circuit of the result from the first node, and skips nodes already visited. An error
+// is returned if the graph is not complete.
+func ChristofidesTour(graph Graph) ([]*Node, float64, error) {
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	var n = len(graph.Nodes)
+	if n == 0 {
+		return nil, 0, nil
+	}
+
+	var tree, _ = PrimMinimumSpanningForest(graph)
+	var indices = indexNodes(tree)
+	var edges []eulerEdge
+	var degree = make([]int, n)
+	for i, node := range tree.Nodes {
+		for _, neighbor := range node.Edges {
+			if j := indices[neighbor]; i < j {
+				edges = append(edges, eulerEdge{from: i, to: j})
+			}
+		}
+		degree[i] = len(node.Edges)
+	}
+
+	var odd []int
+	for i, d := range degree {
+		if d%2 == 1 {
+			odd = append(odd, i)
+		}
+	}
+	for _, pair := range minimumPerfectMatching(distances, odd) {
+		edges = append(edges, eulerEdge{from: pair[0], to: pair[1]})
+	}
+
+	var tour = make([]int, 0, n)
+	var visited = make([]bool, n)
+	for _, i := range hierholzer(n, edges, false, 0) {
+		if !visited[i] {
+			visited[i] = true
+			tour = append(tour, i)
+		}
+	}
+	return indexedNodes(graph, tour), tourCost(distances, tour), nil
+}
+
+// TwoOpt improves a tour of a complete weighted graph by 2-opt local search: while replacing
+// two edges of the tour with the two edges that reconnect it the other way around is cheaper,
+// the segment between them is reversed. The improved tour keeps the first node of the given
+// tour first. An error is returned if the graph is not complete or if the tour does not visit
+// every node exactly once.
+func TwoOpt(graph Graph, tour []*Node) ([]*Node, float64, error) {
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	order, err := tourIndices(graph, tour)
+	if err != nil {
+		return nil, 0, err
+	}
+	twoOpt(distances, order)
+	return indexedNodes(graph, order), tourCost(distances, order), nil
+}
+
+// twoOpt applies 2-opt moves to a tour in place until none improves it, reporting whether any did
+func twoOpt(distances [][]float64, tour []int) bool {
+	var n = len(tour)
+	var changed = false
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					continue
+				}
+				var a, b, c, d = tour[i], tour[i+1], tour[j], tour[(j+1)%n]
+				if distances[a][c]+distances[b][d] < distances[a][b]+distances[c][d]-tourEpsilon {
+					for x, y := i+1, j; x < y; x, y = x+1, y-1 {
+						tour[x], tour[y] = tour[y], tour[x]
+					}
+					improved, changed = true, true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// OrOpt improves a tour of a complete weighted graph by Or-opt local search: while moving a
+// run of one to three consecutive nodes, in either orientation, elsewhere in the tour is
+// cheaper, the run is moved. The improved tour keeps the first node of the given tour first.
+// An error is returned if the graph is not complete or if the tour does not visit every node
+// exactly once.
+func OrOpt(graph Graph, tour []*Node) ([]*Node, float64, error) {
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	order, err := tourIndices(graph, tour)
+	if err != nil {
+		return nil, 0, err
+	}
+	order, _ = orOpt(distances, order)
+	return indexedNodes(graph, order), tourCost(distances, order), nil
+}
+
+// orOpt applies Or-opt moves to a tour until none improves it, reporting whether any did
+func orOpt(distances [][]float64, tour []int) ([]int, bool) {
+	var n = len(tour)
+	var changed = false
+	for improved := true; improved; {
+		improved = false
+		for length := 1; length <= 3 && length <= n-3; length++ {
+			for i := 1; i+length <= n && !improved; i++ {
+				var first, last = tour[i], tour[i+length-1]
+				var previous, next = tour[i-1], tour[(i+length)%n]
+				var gain = distances[previous][first] + distances[last][next] - distances[previous][next]
+
+				// Try reinserting the run between each pair of consecutive nodes outside of it
+				var rest = make([]int, 0, n-length)
+				rest = append(append(rest, tour[:i]...), tour[i+length:]...)
+				for j := range rest {
+					var a, b = rest[j], rest[(j+1)%len(rest)]
+					if a == previous {
+						continue
+					}
+					var forward = distances[a][first] + distances[last][b] - distances[a][b]
+					var backward = distances[a][last] + distances[first][b] - distances[a][b]
+					if forward >= gain-tourEpsilon && backward >= gain-tourEpsilon {
+						continue
+					}
+					var run = append([]int{}, tour[i:i+length]...)
+					if backward < forward {
+						for x, y := 0, len(run)-1; x < y; x, y = x+1, y-1 {
+							run[x], run[y] = run[y], run[x]
+						}
+					}
+					var moved = make([]int, 0, n)
+					moved = append(append(append(moved, rest[:j+1]...), run...), rest[j+1:]...)
+					tour = moved
+					improved, changed = true, true
+					break
+				}
+			}
+		}
+	}
+	return tour, changed
+}
+
+// ImproveTour alternates TwoOpt and OrOpt until neither improves the tour any further
+func ImproveTour(graph Graph, tour []*Node) ([]*Node, float64, error) {
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	order, err := tourIndices(graph, tour)
+	if err != nil {
+		return nil, 0, err
+	}
+	for {
+		var reversed = twoOpt(distances, order)
+		var moved bool
+		order, moved = orOpt(distances, order)
+		if !reversed && !moved {
+			break
+		}
+	}
+	return indexedNodes(graph, order), tourCost(distances, order), nil
+}
+
+// HeldKarpTour finds a cheapest tour of a complete weighted graph with the Held-Karp dynamic
+// program, which computes the cheapest path from the first node through every subset of the
+// other nodes. Its time and memory grow exponentially, so an error is returned for graphs of
+// more than 18 nodes, as well as for graphs that are not complete.
+func HeldKarpTour(graph Graph) ([]*Node, float64, error) {
+	var n = len(graph.Nodes)
+	if n > heldKarpLimit {
+		return nil, 0, fmt.Errorf("graph has %d nodes, more than the %d Held-Karp can handle", n, heldKarpLimit)
+	}
+	var distances, err = distanceMatrix(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n <= 1 {
+		return indexedNodes(graph, identityPartition(n)), 0, nil
+	}
+
+	// cost[mask][v] is the cheapest path from node 0 through the nodes of mask, ending at
+	// v. Bit v-1 of mask stands for node v.
+	var m = n - 1
+	var full = 1<<uint(m) - 1
+	var cost = make([][]float64, full+1)
+	var previous = make([][]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		cost[mask] = make([]float64, m)
+		previous[mask] = make([]int, m)
+		for v := 0; v < m; v++ {
+			cost[mask][v] = math.Inf(1)
+			if mask&(1<<uint(v)) == 0 {
+				continue
+			}
+			var rest = mask &^ (1 << uint(v))
+			if rest == 0 {
+				cost[mask][v], previous[mask][v] = distances[0][v+1], -1
+				continue
+			}
+			for u := 0; u < m; u++ {
+				if rest&(1<<uint(u)) != 0 && cost[rest][u]+distances[u+1][v+1] < cost[mask][v] {
+					cost[mask][v], previous[mask][v] = cost[rest][u]+distances[u+1][v+1], u
+				}
+			}
+		}
+	}
+
+	var best, last = math.Inf(1), -1
+	for v := 0; v < m; v++ {
+		if cost[full][v]+distances[v+1][0] < best {
+			best, last = cost[full][v]+distances[v+1][0], v
+		}
+	}
+	var tour = make([]int, n)
+	for mask, position := full, n-1; last != -1; position-- {
+		tour[position] = last + 1
+		var u = previous[mask][last]
+		mask &^= 1 << uint(last)
+		last = u
+	}
+	return indexedNodes(graph, tour), best, nil
+}
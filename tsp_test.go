@@ -0,0 +1,185 @@
+package gograph
+
+import (
+	"math"
+	"testing"
+)
+
+// createCircleGraph builds a complete graph of n points evenly spaced around a unit circle,
+// weighted by their distances, with the points listed in a scrambled order
+func createCircleGraph(n int) Graph {
+	var angles = make([]float64, n)
+	for i := range angles {
+		angles[i] = 2 * math.Pi * float64((i*7)%n) / float64(n)
+	}
+	var edges [][3]float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			var distance = math.Hypot(math.Cos(angles[i])-math.Cos(angles[j]), math.Sin(angles[i])-math.Sin(angles[j]))
+			edges = append(edges, [3]float64{float64(i), float64(j), distance})
+		}
+	}
+	return createWeightedGraph(n, edges)
+}
+
+// circlePerimeter is the cost of the optimal tour of createCircleGraph
+func circlePerimeter(n int) float64 {
+	return 2 * float64(n) * math.Sin(math.Pi/float64(n))
+}
+
+// createCaterpillarGraph builds the complete graph of the distances along a tree whose spine of
+// n nodes, joined by edges of weight 1, has two leaves joined by edges of weight 2 to each spine
+// node. Every tour crosses each tree edge at least twice, so the optimal tour costs twice the
+// tree's weight, which is returned too.
+func createCaterpillarGraph(n int) (Graph, float64) {
+	var size = 3 * n
+	var distances = make([][]float64, size)
+	for i := range distances {
+		distances[i] = make([]float64, size)
+		for j := range distances[i] {
+			if i != j {
+				distances[i][j] = math.Inf(1)
+			}
+		}
+	}
+	var join = func(i int, j int, weight float64) {
+		distances[i][j], distances[j][i] = weight, weight
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			join(i-1, i, 1)
+		}
+		join(i, n+2*i, 2)
+		join(i, n+2*i+1, 2)
+	}
+	for k := range distances {
+		for i := range distances {
+			for j := range distances {
+				distances[i][j] = math.Min(distances[i][j], distances[i][k]+distances[k][j])
+			}
+		}
+	}
+	var edges [][3]float64
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			edges = append(edges, [3]float64{float64(i), float64(j), distances[i][j]})
+		}
+	}
+	return createWeightedGraph(size, edges), 2 * float64(n-1+4*n)
+}
+
+func expectTour(graph Graph, tour []*Node, cost float64, t *testing.T) {
+	expectEqualInts(len(tour), len(graph.Nodes), t)
+	expectEqualStrings(tour[0].ID, graph.Nodes[0].ID, t)
+	var total float64
+	for index := range tour {
+		total += EdgeWeight(tour[index], tour[(index+1)%len(tour)])
+	}
+	expectEqualFloats(cost, total, t)
+}
+
+func TestNearestNeighborTour(t *testing.T) {
+	describe("NearestNeighborTour", t)
+	var graph = createWeightedGraph(4, [][3]float64{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 5}, {0, 2, 2}, {1, 3, 2}})
+	var tour, cost, err = NearestNeighborTour(graph)
+
+	it("travels to the nearest unvisited node each time", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualStrings(tour[3].ID, graph.Nodes[3].ID, t)
+	expectEqualFloats(cost, 8, t)
+
+	context("the graph is not complete", t)
+	graph = createWeightedGraph(3, [][3]float64{{0, 1, 1}, {1, 2, 1}})
+	_, _, err = NearestNeighborTour(graph)
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestChristofidesTour(t *testing.T) {
+	describe("ChristofidesTour", t)
+	var graph = createCircleGraph(9)
+	var tour, cost, err = ChristofidesTour(graph)
+
+	it("returns a tour within half again of the optimum", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualBools(cost <= 1.5*circlePerimeter(9)+1e-9, true, t)
+
+	context("the spanning tree has many odd-degree nodes", t)
+	var optimum float64
+	graph, optimum = createCaterpillarGraph(12)
+	tour, cost, err = ChristofidesTour(graph)
+
+	it("still returns a tour within half again of the optimum", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualBools(cost <= 1.5*optimum+1e-9, true, t)
+}
+
+func TestTwoOpt(t *testing.T) {
+	describe("TwoOpt", t)
+	var graph = createCircleGraph(8)
+	var tour, cost, err = TwoOpt(graph, graph.Nodes)
+
+	it("uncrosses the tour around the circle", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualFloats(cost, circlePerimeter(8), t)
+
+	context("the tour leaves out a node", t)
+	_, _, err = TwoOpt(graph, graph.Nodes[1:])
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}
+
+func TestOrOpt(t *testing.T) {
+	describe("OrOpt", t)
+	// Points on a line, where node 1 belongs between nodes 3 and 4
+	var positions = []float64{0, 3.5, 1, 2, 4, 5}
+	var edges [][3]float64
+	for i := range positions {
+		for j := i + 1; j < len(positions); j++ {
+			edges = append(edges, [3]float64{float64(i), float64(j), math.Abs(positions[i] - positions[j])})
+		}
+	}
+	var graph = createWeightedGraph(len(positions), edges)
+	var tour, cost, err = OrOpt(graph, graph.Nodes)
+
+	it("moves nodes to where they are cheapest to visit", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualFloats(cost, 10, t)
+}
+
+func TestImproveTour(t *testing.T) {
+	describe("ImproveTour", t)
+	var graph = createCircleGraph(10)
+	var start, startCost, _ = NearestNeighborTour(graph)
+	var tour, cost, err = ImproveTour(graph, start)
+
+	it("returns a tour no costlier than the one given", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualBools(cost <= startCost+1e-9, true, t)
+	expectEqualFloats(cost, circlePerimeter(10), t)
+}
+
+func TestHeldKarpTour(t *testing.T) {
+	describe("HeldKarpTour", t)
+	var graph = createCircleGraph(9)
+	var tour, cost, err = HeldKarpTour(graph)
+
+	it("returns an optimal tour", t)
+	expectEqualBools(err == nil, true, t)
+	expectTour(graph, tour, cost, t)
+	expectEqualFloats(cost, circlePerimeter(9), t)
+
+	context("the graph is too large", t)
+	_, _, err = HeldKarpTour(createCircleGraph(19))
+
+	it("returns an error", t)
+	expectEqualBools(err != nil, true, t)
+}